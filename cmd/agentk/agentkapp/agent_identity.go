@@ -0,0 +1,55 @@
+package agentkapp
+
+import (
+	"context"
+	"sync"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modagent"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
+)
+
+// agentIdentityHolder holds the identity of the agent, received from kas with the configuration.
+type agentIdentityHolder struct {
+	mu sync.Mutex
+	// identity is nil if kas has not sent it.
+	identity *modshared.AgentIdentity
+	// received is closed when the first configuration is received, with or without the identity.
+	received     chan struct{}
+	receivedOnce sync.Once
+}
+
+func newAgentIdentityHolder() *agentIdentityHolder {
+	return &agentIdentityHolder{
+		received: make(chan struct{}),
+	}
+}
+
+// set stores the identity. It may change, e.g. when the configuration project is renamed.
+// identity is nil if the configuration has been received from an older kas that doesn't send it. The previously
+// received identity, if any, is kept then.
+func (h *agentIdentityHolder) set(identity *modshared.AgentIdentity) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if identity != nil {
+		h.identity = identity
+	}
+	h.receivedOnce.Do(func() {
+		close(h.received)
+	})
+}
+
+// get blocks until the first configuration is received or ctx signals done.
+// It returns modagent.ErrAgentIdentityNotAvailable if kas has not sent the identity with the configuration.
+func (h *agentIdentityHolder) get(ctx context.Context) (*modshared.AgentIdentity, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-h.received:
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.identity == nil {
+		return nil, modagent.ErrAgentIdentityNotAvailable
+	}
+	return h.identity, nil
+}
@@ -0,0 +1,41 @@
+package agentkapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modagent"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestAgentIdentityNotSentByKas(t *testing.T) {
+	h := newAgentIdentityHolder()
+	h.set(nil)
+	_, err := h.get(context.Background())
+	assert.Equal(t, modagent.ErrAgentIdentityNotAvailable, err)
+}
+
+func TestAgentIdentityIsKeptIfNotSent(t *testing.T) {
+	identity := &modshared.AgentIdentity{
+		Id:   123,
+		Name: "agent1",
+	}
+	h := newAgentIdentityHolder()
+	h.set(identity)
+	h.set(nil)
+	id, err := h.get(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, cmp.Diff(identity, id, protocmp.Transform()))
+}
+
+func TestAgentIdentityGetBlocksUntilReceived(t *testing.T) {
+	h := newAgentIdentityHolder()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := h.get(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -8,6 +8,7 @@ import (
 
 	gitlab_access_rpc "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitlab_access/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modagent"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/grpctool"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -26,6 +27,11 @@ type agentAPI struct {
 	ModuleName      string
 	Client          gitlab_access_rpc.GitlabAccessClient
 	ResponseVisitor *grpctool.StreamVisitor
+	AgentIdentity   *agentIdentityHolder
+}
+
+func (a *agentAPI) GetAgentIdentity(ctx context.Context) (*modshared.AgentIdentity, error) {
+	return a.AgentIdentity.get(ctx)
 }
 
 func (a *agentAPI) MakeGitLabRequest(ctx context.Context, path string, opts ...modagent.GitLabRequestOption) (*modagent.GitLabResponse, error) {
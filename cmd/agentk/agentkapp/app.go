@@ -12,6 +12,7 @@ import (
 
 	"github.com/ash2k/stager"
 	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/pflag"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/cmd"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
@@ -36,6 +37,7 @@ import (
 	"google.golang.org/grpc/keepalive"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp" // Install the GCP auth plugin
 	"k8s.io/klog/v2"
 	"nhooyr.io/websocket"
@@ -52,6 +54,8 @@ const (
 
 	envVarPodNamespace = "POD_NAMESPACE"
 	envVarPodName      = "POD_NAME"
+
+	defaultObservabilityListenAddress = "0.0.0.0:8080"
 )
 
 type App struct {
@@ -63,6 +67,14 @@ type App struct {
 	CACertFile      string
 	TokenFile       string
 	K8sClientGetter resource.RESTClientGetter
+	// ObservabilityListenAddress is the address to serve Prometheus metrics on. Empty disables the endpoint.
+	ObservabilityListenAddress string
+	// ConfigurationRef is the branch or tag of the configuration project to read the configuration from.
+	// Empty means the default branch.
+	ConfigurationRef string
+	// ConfigurationOverridesConfigMap is the name of the ConfigMap in the agent's namespace with local
+	// configuration overrides. Empty disables overrides.
+	ConfigurationOverridesConfigMap string
 }
 
 func (a *App) Run(ctx context.Context) (retErr error) {
@@ -78,16 +90,23 @@ func (a *App) Run(ctx context.Context) (retErr error) {
 	defer errz.SafeClose(kasConn, &retErr)
 
 	// Construct agent modules
-	modules, err := a.constructModules(kasConn)
+	identity := newAgentIdentityHolder()
+	modules, err := a.constructModules(kasConn, identity)
+	if err != nil {
+		return err
+	}
+	overridesWatcher, err := a.constructOverridesWatcher()
 	if err != nil {
 		return err
 	}
+	configurationClient := rpc.NewAgentConfigurationClient(kasConn)
 	runner := newModuleRunner(a.Log, modules, &rpc.ConfigurationWatcher{
 		Log:         a.Log,
 		AgentMeta:   a.AgentMeta,
-		Client:      rpc.NewAgentConfigurationClient(kasConn),
+		Client:      configurationClient,
 		RetryPeriod: defaultRefreshConfigurationRetryPeriod,
-	})
+		Ref:         a.ConfigurationRef,
+	}, overridesWatcher, configurationClient, identity)
 
 	// Start things up. Stages are shut down in reverse order.
 	return cmd.RunStages(ctx,
@@ -102,21 +121,12 @@ func (a *App) Run(ctx context.Context) (retErr error) {
 	)
 }
 
-func (a *App) constructModules(kasConn grpc.ClientConnInterface) ([]modagent.Module, error) {
+func (a *App) constructModules(kasConn grpc.ClientConnInterface, identity *agentIdentityHolder) ([]modagent.Module, error) {
 	sv, err := grpctool.NewStreamVisitor(&gitlab_access_rpc.Response{})
 	if err != nil {
 		return nil, err
 	}
-	factories := []modagent.Factory{
-		//  Should be the first to configure logging ASAP
-		&observability_agent.Factory{
-			LogLevel: a.LogLevel,
-		},
-		&gitops_agent.Factory{
-			GetObjectsToSynchronizeRetryPeriod: defaultGetObjectsToSynchronizeRetryPeriod,
-		},
-		&cilium_agent.Factory{},
-	}
+	factories := a.moduleFactories()
 	modules := make([]modagent.Module, 0, len(factories))
 	for _, factory := range factories {
 		moduleName := factory.Name()
@@ -127,9 +137,11 @@ func (a *App) constructModules(kasConn grpc.ClientConnInterface) ([]modagent.Mod
 				ModuleName:      moduleName,
 				Client:          gitlab_access_rpc.NewGitlabAccessClient(kasConn),
 				ResponseVisitor: sv,
+				AgentIdentity:   identity,
 			},
 			K8sClientGetter: a.K8sClientGetter,
 			KasConn:         kasConn,
+			Registerer:      prometheus.DefaultRegisterer,
 		})
 		if err != nil {
 			return nil, err
@@ -139,6 +151,76 @@ func (a *App) constructModules(kasConn grpc.ClientConnInterface) ([]modagent.Mod
 	return modules, nil
 }
 
+// agentModule is an entry of agentModules.
+type agentModule struct {
+	factory func(a *App) modagent.Factory
+	// validate applies defaults and validates the configuration of the module without creating it.
+	validate func(cfg *agentcfg.AgentConfiguration) error
+}
+
+// agentModules lists the modules of agentk in the order they are created.
+// Both App.moduleFactories() and moduleValidators() are derived from it so that `agentk config validate`
+// validates the configuration with exactly the modules agentk runs.
+var agentModules = []agentModule{
+	{
+		//  Should be the first to configure logging ASAP
+		factory: func(a *App) modagent.Factory {
+			return &observability_agent.Factory{
+				LogLevel:      a.LogLevel,
+				ListenAddress: a.ObservabilityListenAddress,
+				Gatherer:      prometheus.DefaultGatherer,
+			}
+		},
+		validate: observability_agent.DefaultAndValidateConfiguration,
+	},
+	{
+		factory: func(a *App) modagent.Factory {
+			return &gitops_agent.Factory{
+				GetObjectsToSynchronizeRetryPeriod: defaultGetObjectsToSynchronizeRetryPeriod,
+			}
+		},
+		validate: gitops_agent.DefaultAndValidateConfiguration,
+	},
+	{
+		factory: func(a *App) modagent.Factory {
+			return &cilium_agent.Factory{}
+		},
+		validate: cilium_agent.DefaultAndValidateConfiguration,
+	},
+}
+
+func (a *App) moduleFactories() []modagent.Factory {
+	factories := make([]modagent.Factory, 0, len(agentModules))
+	for _, m := range agentModules {
+		factories = append(factories, m.factory(a))
+	}
+	return factories
+}
+
+// constructOverridesWatcher returns nil if configuration overrides are not used.
+func (a *App) constructOverridesWatcher() (overridesWatcherInterface, error) {
+	if a.ConfigurationOverridesConfigMap == "" {
+		return nil, nil
+	}
+	if a.AgentMeta.PodNamespace == "" {
+		return nil, fmt.Errorf("%s environment variable must be set to use configuration overrides", envVarPodNamespace)
+	}
+	restConfig, err := a.K8sClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("ToRESTConfig: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes.NewForConfig: %v", err)
+	}
+	return &overridesWatcher{
+		log:        a.Log,
+		kubeClient: kubeClient,
+		namespace:  a.AgentMeta.PodNamespace,
+		name:       a.ConfigurationOverridesConfigMap,
+	}, nil
+}
+
 func (a *App) constructKasConnection(ctx context.Context) (*grpc.ClientConn, error) {
 	tokenData, err := ioutil.ReadFile(a.TokenFile)
 	if err != nil {
@@ -225,6 +307,9 @@ func (a *App) constructKasConnection(ctx context.Context) (*grpc.ClientConn, err
 }
 
 func NewFromFlags(flagset *pflag.FlagSet, arguments []string) (cmd.Runnable, error) {
+	if len(arguments) > 0 && arguments[0] == "config" {
+		return newConfigCommandFromFlags(flagset, arguments[1:])
+	}
 	log, level, err := logger()
 	if err != nil {
 		return nil, err
@@ -242,6 +327,9 @@ func NewFromFlags(flagset *pflag.FlagSet, arguments []string) (cmd.Runnable, err
 	flagset.StringVar(&app.KasAddress, "kas-address", "", "GitLab Kubernetes Agent Server address")
 	flagset.StringVar(&app.CACertFile, "ca-cert-file", "", "Optional file with X.509 certificate authority certificate in PEM format")
 	flagset.StringVar(&app.TokenFile, "token-file", "", "File with access token")
+	flagset.StringVar(&app.ObservabilityListenAddress, "observability-listen-address", defaultObservabilityListenAddress, "Address to serve Prometheus metrics on. Set to an empty string to disable")
+	flagset.StringVar(&app.ConfigurationRef, "configuration-ref", "", "Branch or tag of the configuration project to read the configuration from. Defaults to the default branch")
+	flagset.StringVar(&app.ConfigurationOverridesConfigMap, "configuration-overrides-configmap", "", "Optional name of the ConfigMap in the agent's namespace with configuration overrides")
 	kubeConfigFlags := genericclioptions.NewConfigFlags(true)
 	kubeConfigFlags.AddFlags(flagset)
 	if err := flagset.Parse(arguments); err != nil {
@@ -0,0 +1,74 @@
+package agentkapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/cmd"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration"
+	agent_configuration_server "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/server"
+)
+
+const (
+	configValidateUsage = "usage: agentk config validate [--repository-root DIR] FILE"
+)
+
+// configValidateApp validates an agent's configuration file offline and prints the configuration that agentk
+// modules would run with. Files, included from the same repository, are resolved relative to RepositoryRoot,
+// the same way as by `kas agent-config validate`.
+type configValidateApp struct {
+	RepositoryRoot    string
+	ConfigurationFile string
+	Out               io.Writer
+	ErrOut            io.Writer
+	Validators        []configurationValidator
+}
+
+func (a *configValidateApp) Run(ctx context.Context) error {
+	filename, err := filepath.Rel(a.RepositoryRoot, a.ConfigurationFile)
+	if err != nil {
+		return err
+	}
+	config, skipped, err := agent_configuration_server.LoadLocalConfiguration(ctx, a.RepositoryRoot, filename)
+	if err != nil {
+		return err
+	}
+	for _, project := range skipped {
+		fmt.Fprintf(a.ErrOut, "Warning: files included from project %s are not validated\n", project) // nolint: errcheck
+	}
+	errs := defaultAndValidateConfiguration(a.Validators, config)
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid agent configuration: %w", configurationErrors(errs))
+	}
+	effectiveYAML, err := agent_configuration.MarshalAgentConfiguration(config)
+	if err != nil {
+		return err
+	}
+	_, err = a.Out.Write(effectiveYAML)
+	return err
+}
+
+func newConfigCommandFromFlags(flagset *pflag.FlagSet, arguments []string) (cmd.Runnable, error) {
+	if len(arguments) == 0 || arguments[0] != "validate" {
+		return nil, errors.New(configValidateUsage)
+	}
+	app := &configValidateApp{
+		Out:        os.Stdout,
+		ErrOut:     os.Stderr,
+		Validators: moduleValidators(),
+	}
+	flagset.StringVar(&app.RepositoryRoot, "repository-root", ".", "Root directory of the checked out configuration repository")
+	if err := flagset.Parse(arguments[1:]); err != nil {
+		return nil, err
+	}
+	if flagset.NArg() != 1 {
+		return nil, errors.New(configValidateUsage)
+	}
+	app.ConfigurationFile = flagset.Arg(0)
+	return app, nil
+}
@@ -0,0 +1,130 @@
+package agentkapp
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	app, out, errOut := setupConfigValidate(t, `
+include:
+- local: base.yaml
+- project: group/shared
+  file: agents/base.yaml
+observability:
+  logging:
+    level: debug
+`)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(app.RepositoryRoot, "base.yaml"), []byte(`
+gitops:
+  manifest_projects:
+  - id: group/app
+`), 0600))
+	err := app.Run(context.Background())
+	require.NoError(t, err)
+	expected := `gitops:
+  manifest_projects:
+  - default_namespace: default
+    id: group/app
+    paths:
+    - glob: '**/*.{yaml,yml,json}'
+observability:
+  logging:
+    level: debug
+`
+	assert.Equal(t, expected, out.String())
+	assert.Equal(t, "Warning: files included from project group/shared are not validated\n", errOut.String())
+}
+
+func TestConfigValidateMissingInclude(t *testing.T) {
+	app, out, _ := setupConfigValidate(t, `
+include:
+- local: missing.yaml
+`)
+	err := app.Run(context.Background())
+	assert.EqualError(t, err, "configuration file not found: missing.yaml")
+	assert.Empty(t, out.String())
+}
+
+func TestConfigValidateErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		expectedErr string
+	}{
+		{
+			name: "syntax",
+			config: `
+gitops:
+  manifest_projects: {}
+`,
+			expectedErr: "protojson.Unmarshal",
+		},
+		{
+			name: "schema",
+			config: `
+gitops:
+  manifest_projects:
+  - id: ""
+`,
+			expectedErr: "invalid agent configuration: invalid ConfigurationFile.Gitops: embedded message failed validation | caused by: invalid GitopsCF.ManifestProjects[0]",
+		},
+		{
+			name: "module",
+			config: `
+gitops:
+  manifest_projects:
+  - id: group/app
+  - id: group/app
+`,
+			expectedErr: "invalid agent configuration: gitops: duplicate project id: group/app",
+		},
+		{
+			name: "unknown module",
+			config: `
+modules:
+  gitopz:
+    enabled: false
+`,
+			expectedErr: "invalid agent configuration: gitopz: unknown module",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			app, out, _ := setupConfigValidate(t, tc.config) // nolint: scopelint
+			err := app.Run(context.Background())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedErr) // nolint: scopelint
+			assert.Empty(t, out.String())
+		})
+	}
+}
+
+func TestModuleValidatorsMatchFactories(t *testing.T) {
+	factories := (&App{}).moduleFactories()
+	validators := moduleValidators()
+	require.Len(t, validators, len(factories))
+	for i, factory := range factories {
+		assert.Equal(t, factory.Name(), validators[i].Name())
+	}
+}
+
+func setupConfigValidate(t *testing.T, config string) (*configValidateApp, *bytes.Buffer, *bytes.Buffer) {
+	root := t.TempDir()
+	file := filepath.Join(root, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte(config), 0600))
+	var out, errOut bytes.Buffer
+	return &configValidateApp{
+		RepositoryRoot:    root,
+		ConfigurationFile: file,
+		Out:               &out,
+		ErrOut:            &errOut,
+		Validators:        moduleValidators(),
+	}, &out, &errOut
+}
@@ -0,0 +1,146 @@
+package agentkapp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// configurationOverridesKey is the key in the ConfigMap that holds the overrides.
+	configurationOverridesKey = "config.yaml"
+)
+
+// overridesWatcherInterface abstracts overridesWatcher.
+type overridesWatcherInterface interface {
+	Run(ctx context.Context, callback func(*agentcfg.AgentConfiguration))
+}
+
+// overridesWatcher watches a ConfigMap with local configuration overrides.
+type overridesWatcher struct {
+	log        *zap.Logger
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+}
+
+// Run calls the callback with the overrides once the ConfigMap has been read and then each time it changes.
+// The callback is called with an empty configuration if the ConfigMap does not exist. Invalid overrides are
+// logged and the previous overrides are kept. If overrides are invalid when the ConfigMap is read for the first time,
+// the callback is called with an empty configuration so that configuration from the repository is used.
+// Run blocks until ctx is done.
+func (w *overridesWatcher) Run(ctx context.Context, callback func(*agentcfg.AgentConfiguration)) {
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default: // a notification is already pending
+		}
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(w.kubeClient, 0,
+		informers.WithNamespace(w.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", w.name).String()
+		}),
+	)
+	informer := factory.Core().V1().ConfigMaps()
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			notify()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			notify()
+		},
+		DeleteFunc: func(obj interface{}) {
+			notify()
+		},
+	})
+	var wg wait.Group
+	defer wg.Wait()
+	wg.StartWithChannel(ctx.Done(), informer.Informer().Run)
+	if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+		return // context is done
+	}
+	// reported is true once the callback has been called.
+	reported := false
+	notify() // report the initial state, even if the ConfigMap does not exist
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+		cm, err := informer.Lister().ConfigMaps(w.namespace).Get(w.name)
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				// This should never happen
+				w.log.Error("Failed to get configuration overrides ConfigMap", zap.Error(err))
+				continue
+			}
+			callback(&agentcfg.AgentConfiguration{})
+			reported = true
+			continue
+		}
+		overrides, err := parseConfigurationOverrides(cm)
+		if err != nil {
+			if reported {
+				w.log.Error("Invalid configuration overrides, keeping the previous overrides", logz.Namespace(w.namespace),
+					logz.ObjectName(w.name), zap.Error(err))
+				continue
+			}
+			w.log.Error("Invalid configuration overrides, using configuration without overrides", logz.Namespace(w.namespace),
+				logz.ObjectName(w.name), zap.Error(err))
+			overrides = &agentcfg.AgentConfiguration{}
+		}
+		callback(overrides)
+		reported = true
+	}
+}
+
+func parseConfigurationOverrides(cm *corev1.ConfigMap) (*agentcfg.AgentConfiguration, error) {
+	overrides := &agentcfg.AgentConfiguration{}
+	data, ok := cm.Data[configurationOverridesKey]
+	if !ok {
+		return overrides, nil
+	}
+	configJSON, err := yaml.YAMLToJSON([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("YAMLToJSON: %v", err)
+	}
+	if bytes.Equal(configJSON, []byte("null")) {
+		// Empty overrides
+		return overrides, nil
+	}
+	err = protojson.Unmarshal(configJSON, overrides)
+	if err != nil {
+		return nil, fmt.Errorf("protojson.Unmarshal: %v", err)
+	}
+	err = overrides.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// mergeConfigurationOverrides returns a copy of the configuration with the overrides deep-merged over it.
+// Fields that are set in overrides take precedence, lists from overrides are appended.
+func mergeConfigurationOverrides(config, overrides *agentcfg.AgentConfiguration) *agentcfg.AgentConfiguration {
+	merged := proto.Clone(config).(*agentcfg.AgentConfiguration)
+	proto.Merge(merged, overrides)
+	return merged
+}
@@ -0,0 +1,228 @@
+package agentkapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	overridesNamespace = "gitlab-agent"
+	overridesName      = "agent-overrides"
+)
+
+var (
+	_ overridesWatcherInterface = &overridesWatcher{}
+)
+
+func TestParseConfigurationOverrides(t *testing.T) {
+	overrides, err := parseConfigurationOverrides(overridesConfigMap(`
+observability:
+  logging:
+    level: debug
+cilium:
+  hubble_relay_address: hubble-relay.kube-system:80
+`))
+	require.NoError(t, err)
+	expected := &agentcfg.AgentConfiguration{
+		Observability: &agentcfg.ObservabilityCF{
+			Logging: &agentcfg.LoggingCF{
+				Level: agentcfg.LoggingLevelEnum_debug,
+			},
+		},
+		Cilium: &agentcfg.CiliumCF{
+			HubbleRelayAddress: "hubble-relay.kube-system:80",
+		},
+	}
+	assert.Empty(t, cmp.Diff(expected, overrides, protocmp.Transform()))
+}
+
+func TestParseConfigurationOverridesErrors(t *testing.T) {
+	_, err := parseConfigurationOverrides(overridesConfigMap(`
+include:
+- local: base.yaml
+`))
+	assert.Error(t, err)
+	_, err = parseConfigurationOverrides(overridesConfigMap(`gitops: [`))
+	assert.Error(t, err)
+}
+
+func TestMergeConfigurationOverrides(t *testing.T) {
+	config := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id: "group/app",
+				},
+			},
+		},
+		Observability: &agentcfg.ObservabilityCF{
+			Logging: &agentcfg.LoggingCF{
+				Level: agentcfg.LoggingLevelEnum_warn,
+			},
+		},
+	}
+	overrides := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id: "group/local",
+				},
+			},
+		},
+		Observability: &agentcfg.ObservabilityCF{
+			Logging: &agentcfg.LoggingCF{
+				Level: agentcfg.LoggingLevelEnum_debug,
+			},
+		},
+	}
+	merged := mergeConfigurationOverrides(config, overrides)
+	expected := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id: "group/app",
+				},
+				{
+					Id: "group/local",
+				},
+			},
+		},
+		Observability: &agentcfg.ObservabilityCF{
+			Logging: &agentcfg.LoggingCF{
+				Level: agentcfg.LoggingLevelEnum_debug,
+			},
+		},
+	}
+	assert.Empty(t, cmp.Diff(expected, merged, protocmp.Transform()))
+	assert.Len(t, config.Gitops.ManifestProjects, 1) // not modified
+}
+
+func TestOverridesWatcher(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	w := &overridesWatcher{
+		log:        zaptest.NewLogger(t),
+		kubeClient: kubeClient,
+		namespace:  overridesNamespace,
+		name:       overridesName,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	overrides := make(chan *agentcfg.AgentConfiguration)
+	var wg wait.Group
+	defer wg.Wait()
+	defer cancel()
+	wg.StartWithContext(ctx, func(ctx context.Context) {
+		w.Run(ctx, func(o *agentcfg.AgentConfiguration) {
+			select {
+			case <-ctx.Done():
+			case overrides <- o:
+			}
+		})
+	})
+	receive := func() *agentcfg.AgentConfiguration {
+		select {
+		case <-ctx.Done():
+			require.FailNow(t, ctx.Err().Error())
+			return nil
+		case o := <-overrides:
+			return o
+		}
+	}
+
+	// ConfigMap does not exist
+	assert.Empty(t, cmp.Diff(&agentcfg.AgentConfiguration{}, receive(), protocmp.Transform()))
+
+	// ConfigMap is created
+	_, err := kubeClient.CoreV1().ConfigMaps(overridesNamespace).Create(ctx, overridesConfigMap(`
+observability:
+  logging:
+    level: debug
+`), metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, agentcfg.LoggingLevelEnum_debug, receive().Observability.Logging.Level)
+
+	// ConfigMap is deleted
+	err = kubeClient.CoreV1().ConfigMaps(overridesNamespace).Delete(ctx, overridesName, metav1.DeleteOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, cmp.Diff(&agentcfg.AgentConfiguration{}, receive(), protocmp.Transform()))
+}
+
+func TestOverridesWatcherInvalidOverrides(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(overridesConfigMap(`gitops: [`))
+	w := &overridesWatcher{
+		log:        zaptest.NewLogger(t),
+		kubeClient: kubeClient,
+		namespace:  overridesNamespace,
+		name:       overridesName,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	overrides := make(chan *agentcfg.AgentConfiguration)
+	var wg wait.Group
+	defer wg.Wait()
+	defer cancel()
+	wg.StartWithContext(ctx, func(ctx context.Context) {
+		w.Run(ctx, func(o *agentcfg.AgentConfiguration) {
+			select {
+			case <-ctx.Done():
+			case overrides <- o:
+			}
+		})
+	})
+	receive := func() *agentcfg.AgentConfiguration {
+		select {
+		case <-ctx.Done():
+			require.FailNow(t, ctx.Err().Error())
+			return nil
+		case o := <-overrides:
+			return o
+		}
+	}
+	update := func(config string) {
+		_, err := kubeClient.CoreV1().ConfigMaps(overridesNamespace).Update(ctx, overridesConfigMap(config), metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+
+	// Invalid at startup, configuration is used without overrides
+	assert.Empty(t, cmp.Diff(&agentcfg.AgentConfiguration{}, receive(), protocmp.Transform()))
+
+	update(`
+observability:
+  logging:
+    level: debug
+`)
+	assert.Equal(t, agentcfg.LoggingLevelEnum_debug, receive().Observability.Logging.Level)
+
+	// Invalid later, previous overrides are kept i.e. the callback is not called
+	update(`gitops: [`)
+	update(`
+observability:
+  logging:
+    level: warn
+`)
+	assert.Equal(t, agentcfg.LoggingLevelEnum_warn, receive().Observability.Logging.Level)
+}
+
+func overridesConfigMap(config string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      overridesName,
+			Namespace: overridesNamespace,
+		},
+		Data: map[string]string{
+			configurationOverridesKey: config,
+		},
+	}
+}
@@ -9,3 +9,8 @@ import (
 func agentConfig(config *agentcfg.AgentConfiguration) zap.Field {
 	return zap.Reflect(logz.AgentConfig, config)
 }
+
+// appliedCommitId is the commit of the configuration that modules are running with.
+func appliedCommitId(commitId string) zap.Field {
+	return zap.String("applied_commit_id", commitId)
+}
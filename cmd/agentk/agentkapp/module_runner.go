@@ -2,43 +2,95 @@ package agentkapp
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/ash2k/stager"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/cmd"
 	agent_configuration_rpc "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modagent"
-	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/grpctool"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 type moduleHolder struct {
-	module      modagent.Module
-	cfg2pipe    chan *agentcfg.AgentConfiguration
-	pipe2module chan *agentcfg.AgentConfiguration
+	log      *zap.Logger
+	module   modagent.Module
+	cfg2pipe chan *agentcfg.AgentConfiguration
+	// pipe2runner is used to start the module. Each sent channel is given to a new Run() invocation.
+	pipe2runner chan chan *agentcfg.AgentConfiguration
 }
 
+// runModule runs the module each time runPipe starts it. Only one instance of the module runs at a time.
 func (h *moduleHolder) runModule(ctx context.Context) error {
-	return h.module.Run(ctx, h.pipe2module)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case pipe2module, ok := <-h.pipe2runner:
+			if !ok {
+				return nil
+			}
+			err := h.module.Run(ctx, pipe2module)
+			if err != nil {
+				return err
+			}
+		}
+	}
 }
 
 func (h *moduleHolder) runPipe(ctx context.Context) error {
-	defer close(h.pipe2module)
+	defer close(h.pipe2runner)
 	var (
+		pipe2module        chan *agentcfg.AgentConfiguration // nil when the module is not running
 		nilablePipe2module chan<- *agentcfg.AgentConfiguration
 		cfgToSend          *agentcfg.AgentConfiguration
 	)
+	defer func() {
+		if pipe2module != nil {
+			close(pipe2module)
+		}
+	}()
 	// The loop consumes the incoming items from the configuration channel (cfg2pipe) and only sends the last
 	// received item to the module (pipe2module). This allows to skip configuration changes that happened while the module was handling the
 	// previous configuration change.
+	// The module is started when a configuration enables it and is stopped, by closing pipe2module, when a configuration
+	// disables it.
 	for {
 		select {
 		case <-ctx.Done(): // case #1
 			return nil
-		case cfgToSend = <-h.cfg2pipe: // case #2
-			nilablePipe2module = h.pipe2module // enable case #3
+		case cfg := <-h.cfg2pipe: // case #2
+			enabled := moduleEnabled(cfg, h.module.Name())
+			switch {
+			case enabled && pipe2module == nil:
+				h.log.Info("Starting module")
+				pipe2module = make(chan *agentcfg.AgentConfiguration)
+				select {
+				case <-ctx.Done():
+					return nil
+				case h.pipe2runner <- pipe2module:
+				}
+			case !enabled && pipe2module != nil:
+				h.log.Info("Stopping disabled module")
+				close(pipe2module)
+				pipe2module = nil
+			}
+			if enabled {
+				cfgToSend = cfg
+				nilablePipe2module = pipe2module // enable case #3
+			} else {
+				cfgToSend = nil          // help GC
+				nilablePipe2module = nil // disable case #3
+			}
 		case nilablePipe2module <- cfgToSend: // case #3, disabled when nilablePipe2module == nil i.e. when there is nothing to send
 			// config sent
 			cfgToSend = nil          // help GC
@@ -51,21 +103,51 @@ type moduleRunner struct {
 	log                  *zap.Logger
 	holders              []moduleHolder
 	configurationWatcher agent_configuration_rpc.ConfigurationWatcherInterface
+	// overridesWatcher watches local configuration overrides. nil if overrides are not used.
+	overridesWatcher overridesWatcherInterface
+	// configurationClient is used to report configuration errors.
+	configurationClient agent_configuration_rpc.AgentConfigurationClient
+	// identity is updated with the agent identity received with each configuration.
+	identity *agentIdentityHolder
+
+	// applyMu serializes applying configurations so that modules get them in order.
+	// It is held while configuration is given to modules, unlike mu.
+	applyMu sync.Mutex
+	// applied is the configuration modules are running with. Protected by applyMu.
+	applied *agent_configuration_rpc.AppliedConfiguration
+
+	mu sync.Mutex // protects the fields below
+	// data is the last configuration received from kas. nil until one is received.
+	data *agent_configuration_rpc.ConfigurationData
+	// overrides are the local configuration overrides. nil until they have been read.
+	overrides *agentcfg.AgentConfiguration
 }
 
-func newModuleRunner(log *zap.Logger, modules []modagent.Module, configurationWatcher agent_configuration_rpc.ConfigurationWatcherInterface) *moduleRunner {
+func newModuleRunner(log *zap.Logger, modules []modagent.Module, configurationWatcher agent_configuration_rpc.ConfigurationWatcherInterface,
+	overridesWatcher overridesWatcherInterface, configurationClient agent_configuration_rpc.AgentConfigurationClient,
+	identity *agentIdentityHolder) *moduleRunner {
 	holders := make([]moduleHolder, 0, len(modules))
 	for _, module := range modules {
 		holders = append(holders, moduleHolder{
+			log:         log.With(logz.ModuleName(module.Name())),
 			module:      module,
 			cfg2pipe:    make(chan *agentcfg.AgentConfiguration),
-			pipe2module: make(chan *agentcfg.AgentConfiguration),
+			pipe2runner: make(chan chan *agentcfg.AgentConfiguration),
 		})
 	}
+	var overrides *agentcfg.AgentConfiguration
+	if overridesWatcher == nil {
+		overrides = &agentcfg.AgentConfiguration{} // nothing to wait for
+	}
 	return &moduleRunner{
 		log:                  log,
 		holders:              holders,
 		configurationWatcher: configurationWatcher,
+		overridesWatcher:     overridesWatcher,
+		configurationClient:  configurationClient,
+		identity:             identity,
+		overrides:            overrides,
+		applied:              &agent_configuration_rpc.AppliedConfiguration{},
 	}
 }
 
@@ -87,30 +169,158 @@ func (r *moduleRunner) RunModules(ctx context.Context) error {
 }
 
 func (r *moduleRunner) RunConfigurationRefresh(ctx context.Context) error {
-	r.configurationWatcher.Watch(ctx, func(ctx context.Context, data agent_configuration_rpc.ConfigurationData) {
-		err := r.applyConfiguration(r.holders, data.CommitId, data.Config)
-		if err != nil {
-			if !errz.ContextDone(err) {
-				r.log.Error("Failed to apply configuration", logz.CommitId(data.CommitId), zap.Error(err))
-			}
-			return
-		}
+	var wg wait.Group
+	defer wg.Wait()
+	if r.overridesWatcher != nil {
+		wg.StartWithContext(ctx, func(ctx context.Context) {
+			r.overridesWatcher.Run(ctx, func(overrides *agentcfg.AgentConfiguration) {
+				r.mu.Lock()
+				r.overrides = overrides
+				r.mu.Unlock()
+				r.apply(ctx)
+			})
+		})
+	}
+	r.configurationWatcher.Watch(ctx, func(ctx context.Context, data agent_configuration_rpc.ConfigurationData) *agent_configuration_rpc.AppliedConfiguration {
+		// Set before the configuration is applied so that modules have it when they are started.
+		// Older kas versions don't send the identity.
+		r.identity.set(data.AgentIdentity)
+		r.mu.Lock()
+		r.data = &data
+		r.mu.Unlock()
+		return r.apply(ctx)
 	})
 	return nil
 }
 
-func (r *moduleRunner) applyConfiguration(holders []moduleHolder, commitId string, config *agentcfg.AgentConfiguration) error {
+// apply applies the last configuration, received from kas, with the overrides merged over it.
+// It returns the configuration that modules are running with afterwards.
+// Nothing is applied until both the configuration and the overrides are available.
+func (r *moduleRunner) apply(ctx context.Context) *agent_configuration_rpc.AppliedConfiguration {
+	applied, commitId, errs := r.applyLatest()
+	if len(errs) > 0 {
+		// Not holding any locks while making the call.
+		r.reportConfigurationErrors(ctx, commitId, errs)
+	}
+	return applied
+}
+
+// applyLatest applies the last configuration, received from kas, with the overrides merged over it.
+// It returns the configuration that modules are running with and, if the configuration was rejected,
+// the commit id and the errors to report.
+// Configuration from the repository is applied without the overrides if it is valid and nothing has been applied
+// yet. This way invalid overrides don't prevent the agent from starting.
+func (r *moduleRunner) applyLatest() (*agent_configuration_rpc.AppliedConfiguration, string, []*agent_configuration_rpc.ConfigurationError) {
+	r.applyMu.Lock()
+	defer r.applyMu.Unlock()
+	r.mu.Lock()
+	data, overrides := r.data, r.overrides
+	r.mu.Unlock()
+	if data == nil || overrides == nil {
+		return r.applied, "", nil
+	}
+	config := mergeConfigurationOverrides(data.Config, overrides)
+	hash, errs := r.applyConfiguration(r.holders, data.CommitId, config)
+	if len(errs) == 0 {
+		r.applied = &agent_configuration_rpc.AppliedConfiguration{
+			CommitId: data.CommitId,
+			Hash:     hash,
+		}
+		return r.applied, "", nil
+	}
+	local := r.overridesRejected(data.Config, overrides)
+	if local {
+		for _, e := range errs {
+			e.Local = true
+		}
+	}
+	if local && r.applied.CommitId == "" {
+		r.log.Error("Configuration overrides rejected, applying the configuration without them", logz.CommitId(data.CommitId),
+			zap.Error(configurationErrors(errs)))
+		hash, _ = r.applyConfiguration(r.holders, data.CommitId, proto.Clone(data.Config).(*agentcfg.AgentConfiguration))
+		r.applied = &agent_configuration_rpc.AppliedConfiguration{
+			CommitId: data.CommitId,
+			Hash:     hash,
+			Errors:   errs,
+		}
+		return r.applied, data.CommitId, errs
+	}
+	r.log.Error("Configuration rejected, keeping the last applied configuration", logz.CommitId(data.CommitId),
+		appliedCommitId(r.applied.CommitId), zap.Error(configurationErrors(errs)))
+	r.applied = &agent_configuration_rpc.AppliedConfiguration{
+		CommitId:         r.applied.CommitId,
+		Hash:             r.applied.Hash,
+		RejectedCommitId: data.CommitId,
+		Errors:           errs,
+	}
+	return r.applied, data.CommitId, errs
+}
+
+// overridesRejected returns true if the configuration from the repository is valid on its own, i.e. if it has
+// been rejected because of the overrides.
+func (r *moduleRunner) overridesRejected(config, overrides *agentcfg.AgentConfiguration) bool {
+	if proto.Size(overrides) == 0 {
+		return false
+	}
+	// Clone as defaulting modifies the configuration.
+	config = proto.Clone(config).(*agentcfg.AgentConfiguration)
+	return len(defaultAndValidateConfiguration(r.validators(), config)) == 0
+}
+
+// applyConfiguration gives the configuration to all modules if all of them accept it.
+// It returns the hash of the applied configuration.
+// If one or more modules reject it, modules keep running with the previous configuration and the errors are returned.
+func (r *moduleRunner) applyConfiguration(holders []moduleHolder, commitId string, config *agentcfg.AgentConfiguration) (string, []*agent_configuration_rpc.ConfigurationError) {
 	r.log.Debug("Applying configuration", logz.CommitId(commitId), agentConfig(config))
 	// Default and validate before setting for use.
-	for _, holder := range holders {
-		err := holder.module.DefaultAndValidateConfiguration(config)
-		if err != nil {
-			return fmt.Errorf("%s: %v", holder.module.Name(), err)
-		}
+	errs := defaultAndValidateConfiguration(r.validators(), config)
+	if len(errs) > 0 {
+		return "", errs
 	}
+	// Hash before giving the configuration to modules as they may use it concurrently.
+	hash := configurationHash(config)
 	// Set for use.
 	for _, holder := range holders {
 		holder.cfg2pipe <- config
 	}
-	return nil
+	return hash, nil
+}
+
+func (r *moduleRunner) validators() []configurationValidator {
+	validators := make([]configurationValidator, 0, len(r.holders))
+	for _, holder := range r.holders {
+		validators = append(validators, holder.module)
+	}
+	return validators
+}
+
+// reportConfigurationErrors sends the errors to kas so that they can be shown to the user in GitLab.
+func (r *moduleRunner) reportConfigurationErrors(ctx context.Context, commitId string, errs []*agent_configuration_rpc.ConfigurationError) {
+	_, err := r.configurationClient.ReportConfigurationErrors(ctx, &agent_configuration_rpc.ReportConfigurationErrorsRequest{
+		CommitId: commitId,
+		Errors:   errs,
+	})
+	if err != nil && !grpctool.RequestCanceled(err) {
+		r.log.Warn("Failed to report configuration errors", logz.CommitId(commitId), zap.Error(err))
+	}
+}
+
+// configurationHash returns the hex-encoded SHA-256 hash of the configuration.
+func configurationHash(config *agentcfg.AgentConfiguration) string {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(config)
+	if err != nil {
+		// This should never happen
+		return ""
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// configurationErrors joins errors from all modules into one error.
+func configurationErrors(errs []*agent_configuration_rpc.ConfigurationError) error {
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", e.Module, e.Message))
+	}
+	return errors.New(strings.Join(msgs, "; "))
 }
@@ -2,7 +2,9 @@ package agentkapp
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
@@ -10,17 +12,21 @@ import (
 	"github.com/stretchr/testify/require"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modagent"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/matcher"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_modagent"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap/zaptest"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 const (
 	revision1 = "rev12341234_1"
 	revision2 = "rev12341234_2"
+	revision3 = "rev12341234_3"
 )
 
 func TestConfigurationIsApplied(t *testing.T) {
@@ -39,6 +45,10 @@ func TestConfigurationIsApplied(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	watcher := mock_rpc.NewMockConfigurationWatcherInterface(ctrl)
 	m := mock_modagent.NewMockModule(ctrl)
+	m.EXPECT().
+		Name().
+		Return("m").
+		AnyTimes()
 	ctx1, cancel1 := context.WithCancel(context.Background())
 	defer cancel1()
 	ctx2, cancel2 := context.WithCancel(context.Background())
@@ -69,7 +79,7 @@ func TestConfigurationIsApplied(t *testing.T) {
 		m.EXPECT().
 			DefaultAndValidateConfiguration(cfg2),
 	)
-	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher)
+	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher, nil, nil, newAgentIdentityHolder())
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		return a.RunModules(ctx)
@@ -97,6 +107,10 @@ func TestConfigurationIsSquashed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	watcher := mock_rpc.NewMockConfigurationWatcherInterface(ctrl)
 	m := mock_modagent.NewMockModule(ctrl)
+	m.EXPECT().
+		Name().
+		Return("m").
+		AnyTimes()
 	ctx1, cancel1 := context.WithCancel(context.Background())
 	defer cancel1()
 	m.EXPECT().
@@ -122,7 +136,102 @@ func TestConfigurationIsSquashed(t *testing.T) {
 		m.EXPECT().
 			DefaultAndValidateConfiguration(cfg2),
 	)
-	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher)
+	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher, nil, nil, newAgentIdentityHolder())
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return a.RunModules(ctx)
+	})
+	g.Go(func() error {
+		return a.RunConfigurationRefresh(ctx)
+	})
+	err := g.Wait()
+	require.NoError(t, err)
+}
+
+func TestInvalidConfigurationIsNotApplied(t *testing.T) {
+	cfg1 := &agentcfg.AgentConfiguration{}
+	cfg2 := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id: "bla",
+				},
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl := gomock.NewController(t)
+	watcher := mock_rpc.NewMockConfigurationWatcherInterface(ctrl)
+	client := mock_rpc.NewMockAgentConfigurationClient(ctrl)
+	m1 := mock_modagent.NewMockModule(ctrl)
+	m2 := mock_modagent.NewMockModule(ctrl)
+	applied := make(chan struct{}, 2)
+	for _, m := range []*mock_modagent.MockModule{m1, m2} {
+		m.EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguration) {
+				c := <-cfg
+				assert.Empty(t, cmp.Diff(c, cfg1, protocmp.Transform()))
+				applied <- struct{}{}
+				for c = range cfg { // channel is closed on shutdown
+					assert.Fail(t, "invalid configuration applied", "%v", c)
+				}
+			})
+	}
+	m1.EXPECT().
+		Name().
+		Return("m1").
+		AnyTimes()
+	m2.EXPECT().
+		Name().
+		Return("m2").
+		AnyTimes()
+	gomock.InOrder(
+		watcher.EXPECT().
+			Watch(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, callback rpc.ConfigurationCallback) {
+				applied1 := callback(ctx, rpc.ConfigurationData{CommitId: revision1, Config: cfg1})
+				assert.Equal(t, revision1, applied1.CommitId)
+				assert.Equal(t, configurationHash(cfg1), applied1.Hash)
+				<-applied
+				<-applied
+				applied2 := callback(ctx, rpc.ConfigurationData{CommitId: revision2, Config: cfg2})
+				assert.Empty(t, cmp.Diff(&rpc.AppliedConfiguration{
+					CommitId:         revision1,
+					Hash:             applied1.Hash,
+					RejectedCommitId: revision2,
+					Errors: []*rpc.ConfigurationError{
+						{
+							Module:  "m2",
+							Message: "bad project",
+						},
+					},
+				}, applied2, protocmp.Transform()))
+				cancel()
+			}),
+		m1.EXPECT().
+			DefaultAndValidateConfiguration(cfg1),
+		m2.EXPECT().
+			DefaultAndValidateConfiguration(cfg1),
+		m1.EXPECT().
+			DefaultAndValidateConfiguration(cfg2), // valid for m1
+		m2.EXPECT().
+			DefaultAndValidateConfiguration(cfg2).
+			Return(errors.New("bad project")),
+		client.EXPECT().
+			ReportConfigurationErrors(gomock.Any(), matcher.ProtoEq(t, &rpc.ReportConfigurationErrorsRequest{
+				CommitId: revision2,
+				Errors: []*rpc.ConfigurationError{
+					{
+						Module:  "m2",
+						Message: "bad project",
+					},
+				},
+			})).
+			Return(&rpc.ReportConfigurationErrorsResponse{}, nil),
+	)
+	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m1, m2}, watcher, nil, client, newAgentIdentityHolder())
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		return a.RunModules(ctx)
@@ -133,3 +242,339 @@ func TestConfigurationIsSquashed(t *testing.T) {
 	err := g.Wait()
 	require.NoError(t, err)
 }
+
+func TestConfigurationOverridesAreApplied(t *testing.T) {
+	cfg := &agentcfg.AgentConfiguration{}
+	overrides1 := &agentcfg.AgentConfiguration{
+		Observability: &agentcfg.ObservabilityCF{
+			Logging: &agentcfg.LoggingCF{
+				Level: agentcfg.LoggingLevelEnum_debug,
+			},
+		},
+	}
+	overrides2 := &agentcfg.AgentConfiguration{
+		Observability: &agentcfg.ObservabilityCF{
+			Logging: &agentcfg.LoggingCF{
+				Level: agentcfg.LoggingLevelEnum_warn,
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl := gomock.NewController(t)
+	watcher := mock_rpc.NewMockConfigurationWatcherInterface(ctrl)
+	m := mock_modagent.NewMockModule(ctrl)
+	m.EXPECT().
+		Name().
+		Return("m").
+		AnyTimes()
+	applied := make(chan struct{})
+	configReceived := make(chan struct{})
+	m.EXPECT().
+		Run(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguration) {
+			c := <-cfg
+			assert.Empty(t, cmp.Diff(c, overrides1, protocmp.Transform()))
+			close(applied)
+			c = <-cfg
+			assert.Empty(t, cmp.Diff(c, overrides2, protocmp.Transform()))
+			cancel()
+			<-ctx.Done()
+		})
+	m.EXPECT().
+		DefaultAndValidateConfiguration(gomock.Any()).
+		Times(2)
+	watcher.EXPECT().
+		Watch(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, callback rpc.ConfigurationCallback) {
+			callback(ctx, rpc.ConfigurationData{CommitId: revision1, Config: cfg}) // not applied until overrides have been read
+			close(configReceived)
+			<-ctx.Done()
+		})
+	ow := overridesWatcherFunc(func(ctx context.Context, callback func(*agentcfg.AgentConfiguration)) {
+		<-configReceived
+		callback(overrides1)
+		<-applied
+		callback(overrides2)
+		<-ctx.Done()
+	})
+	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher, ow, nil, newAgentIdentityHolder())
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return a.RunModules(ctx)
+	})
+	g.Go(func() error {
+		return a.RunConfigurationRefresh(ctx)
+	})
+	err := g.Wait()
+	require.NoError(t, err)
+}
+
+func TestInvalidConfigurationOverridesAreNotApplied(t *testing.T) {
+	cfg := &agentcfg.AgentConfiguration{}
+	overrides := &agentcfg.AgentConfiguration{
+		Observability: &agentcfg.ObservabilityCF{
+			Logging: &agentcfg.LoggingCF{
+				Level: agentcfg.LoggingLevelEnum_debug,
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl := gomock.NewController(t)
+	watcher := mock_rpc.NewMockConfigurationWatcherInterface(ctrl)
+	client := mock_rpc.NewMockAgentConfigurationClient(ctrl)
+	m := mock_modagent.NewMockModule(ctrl)
+	m.EXPECT().
+		Name().
+		Return("m").
+		AnyTimes()
+	m.EXPECT().
+		Run(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, cfgs <-chan *agentcfg.AgentConfiguration) {
+			// Applied without the overrides
+			c := <-cfgs
+			assert.Empty(t, cmp.Diff(c, cfg, protocmp.Transform()))
+			for range cfgs { // channel is closed on shutdown
+			}
+		})
+	m.EXPECT().
+		DefaultAndValidateConfiguration(gomock.Any()).
+		DoAndReturn(func(config *agentcfg.AgentConfiguration) error {
+			if config.Observability != nil {
+				return errors.New("bad log level")
+			}
+			return nil
+		}).
+		Times(3) // merged configuration, configuration without overrides to find the cause and to apply it
+	expectedErrs := []*rpc.ConfigurationError{
+		{
+			Module:  "m",
+			Message: "bad log level",
+			Local:   true,
+		},
+	}
+	client.EXPECT().
+		ReportConfigurationErrors(gomock.Any(), matcher.ProtoEq(t, &rpc.ReportConfigurationErrorsRequest{
+			CommitId: revision1,
+			Errors:   expectedErrs,
+		})).
+		Return(&rpc.ReportConfigurationErrorsResponse{}, nil)
+	overridesRead := make(chan struct{})
+	watcher.EXPECT().
+		Watch(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, callback rpc.ConfigurationCallback) {
+			<-overridesRead
+			applied := callback(ctx, rpc.ConfigurationData{CommitId: revision1, Config: cfg})
+			assert.Empty(t, cmp.Diff(&rpc.AppliedConfiguration{
+				CommitId: revision1,
+				Hash:     configurationHash(cfg),
+				Errors:   expectedErrs,
+			}, applied, protocmp.Transform()))
+			cancel()
+		})
+	ow := overridesWatcherFunc(func(ctx context.Context, callback func(*agentcfg.AgentConfiguration)) {
+		callback(overrides)
+		close(overridesRead)
+		<-ctx.Done()
+	})
+	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher, ow, client, newAgentIdentityHolder())
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return a.RunModules(ctx)
+	})
+	g.Go(func() error {
+		return a.RunConfigurationRefresh(ctx)
+	})
+	err := g.Wait()
+	require.NoError(t, err)
+}
+
+func TestModuleIsDisabledAndEnabled(t *testing.T) {
+	cfg1 := &agentcfg.AgentConfiguration{}
+	cfg2 := &agentcfg.AgentConfiguration{
+		Modules: map[string]*agentcfg.ModuleCF{
+			"m": {
+				Enabled: wrapperspb.Bool(false),
+			},
+		},
+	}
+	cfg3 := &agentcfg.AgentConfiguration{
+		Modules: map[string]*agentcfg.ModuleCF{
+			"m": {
+				Enabled: wrapperspb.Bool(true),
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl := gomock.NewController(t)
+	watcher := mock_rpc.NewMockConfigurationWatcherInterface(ctrl)
+	m := mock_modagent.NewMockModule(ctrl)
+	m.EXPECT().
+		Name().
+		Return("m").
+		AnyTimes()
+	running := make(chan struct{})
+	stopped := make(chan struct{})
+	gomock.InOrder(
+		m.EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguration) {
+				c := <-cfg
+				assert.Empty(t, cmp.Diff(c, cfg1, protocmp.Transform()))
+				close(running)
+				for c = range cfg { // channel is closed when the module is disabled
+					assert.Fail(t, "configuration applied to a disabled module", "%v", c)
+				}
+				close(stopped)
+			}),
+		m.EXPECT().
+			Run(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguration) {
+				c := <-cfg
+				assert.Empty(t, cmp.Diff(c, cfg3, protocmp.Transform()))
+				cancel()
+				for range cfg { // channel is closed on shutdown
+				}
+			}),
+	)
+	gomock.InOrder(
+		m.EXPECT().
+			DefaultAndValidateConfiguration(cfg1),
+		// not called for cfg2 as the module is disabled
+		m.EXPECT().
+			DefaultAndValidateConfiguration(cfg3),
+	)
+	watcher.EXPECT().
+		Watch(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, callback rpc.ConfigurationCallback) {
+			callback(ctx, rpc.ConfigurationData{CommitId: revision1, Config: cfg1})
+			<-running
+			callback(ctx, rpc.ConfigurationData{CommitId: revision2, Config: cfg2})
+			<-stopped
+			callback(ctx, rpc.ConfigurationData{CommitId: revision3, Config: cfg3})
+			<-ctx.Done()
+		})
+	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher, nil, nil, newAgentIdentityHolder())
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return a.RunModules(ctx)
+	})
+	g.Go(func() error {
+		return a.RunConfigurationRefresh(ctx)
+	})
+	err := g.Wait()
+	require.NoError(t, err)
+}
+
+func TestUnknownModuleIsRejected(t *testing.T) {
+	cfg := &agentcfg.AgentConfiguration{
+		Modules: map[string]*agentcfg.ModuleCF{
+			"typo": {
+				Enabled: wrapperspb.Bool(false),
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl := gomock.NewController(t)
+	watcher := mock_rpc.NewMockConfigurationWatcherInterface(ctrl)
+	client := mock_rpc.NewMockAgentConfigurationClient(ctrl)
+	m := mock_modagent.NewMockModule(ctrl)
+	m.EXPECT().
+		Name().
+		Return("m").
+		AnyTimes()
+	m.EXPECT().
+		DefaultAndValidateConfiguration(cfg)
+	client.EXPECT().
+		ReportConfigurationErrors(gomock.Any(), matcher.ProtoEq(t, &rpc.ReportConfigurationErrorsRequest{
+			CommitId: revision1,
+			Errors: []*rpc.ConfigurationError{
+				{
+					Module:  "typo",
+					Message: "unknown module",
+				},
+			},
+		})).
+		Return(&rpc.ReportConfigurationErrorsResponse{}, nil)
+	watcher.EXPECT().
+		Watch(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, callback rpc.ConfigurationCallback) {
+			applied := callback(ctx, rpc.ConfigurationData{CommitId: revision1, Config: cfg})
+			assert.Equal(t, revision1, applied.RejectedCommitId)
+			cancel()
+		})
+	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher, nil, client, newAgentIdentityHolder())
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return a.RunModules(ctx)
+	})
+	g.Go(func() error {
+		return a.RunConfigurationRefresh(ctx)
+	})
+	err := g.Wait()
+	require.NoError(t, err)
+}
+
+func TestAgentIdentityIsSetBeforeModuleIsStarted(t *testing.T) {
+	identity := &modshared.AgentIdentity{
+		Id:          123,
+		Name:        "agent1",
+		ProjectId:   234,
+		ProjectPath: "group/project",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl := gomock.NewController(t)
+	watcher := mock_rpc.NewMockConfigurationWatcherInterface(ctrl)
+	m := mock_modagent.NewMockModule(ctrl)
+	m.EXPECT().
+		Name().
+		Return("m").
+		AnyTimes()
+	holder := newAgentIdentityHolder()
+	m.EXPECT().
+		Run(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguration) {
+			<-cfg
+			idCtx, idCancel := context.WithTimeout(ctx, 10*time.Second)
+			defer idCancel()
+			id, err := holder.get(idCtx)
+			if assert.NoError(t, err) {
+				assert.Empty(t, cmp.Diff(identity, id, protocmp.Transform()))
+			}
+			cancel()
+			for range cfg { // channel is closed on shutdown
+			}
+		})
+	m.EXPECT().
+		DefaultAndValidateConfiguration(gomock.Any())
+	watcher.EXPECT().
+		Watch(gomock.Any(), gomock.Any()).
+		Do(func(ctx context.Context, callback rpc.ConfigurationCallback) {
+			callback(ctx, rpc.ConfigurationData{
+				CommitId:      revision1,
+				Config:        &agentcfg.AgentConfiguration{},
+				AgentIdentity: identity,
+			})
+			<-ctx.Done()
+		})
+	a := newModuleRunner(zaptest.NewLogger(t), []modagent.Module{m}, watcher, nil, nil, holder)
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return a.RunModules(ctx)
+	})
+	g.Go(func() error {
+		return a.RunConfigurationRefresh(ctx)
+	})
+	err := g.Wait()
+	require.NoError(t, err)
+}
+
+type overridesWatcherFunc func(ctx context.Context, callback func(*agentcfg.AgentConfiguration))
+
+func (f overridesWatcherFunc) Run(ctx context.Context, callback func(*agentcfg.AgentConfiguration)) {
+	f(ctx, callback)
+}
@@ -0,0 +1,82 @@
+package agentkapp
+
+import (
+	"sort"
+
+	agent_configuration_rpc "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+)
+
+// configurationValidator is implemented by modagent.Module and moduleValidator.
+type configurationValidator interface {
+	DefaultAndValidateConfiguration(cfg *agentcfg.AgentConfiguration) error
+	Name() string
+}
+
+// moduleValidator validates the configuration of a module without creating the module.
+type moduleValidator struct {
+	name     string
+	validate func(cfg *agentcfg.AgentConfiguration) error
+}
+
+func (v moduleValidator) DefaultAndValidateConfiguration(cfg *agentcfg.AgentConfiguration) error {
+	return v.validate(cfg)
+}
+
+func (v moduleValidator) Name() string {
+	return v.name
+}
+
+// moduleValidators returns validators for the modules in agentModules, in the same order.
+func moduleValidators() []configurationValidator {
+	validators := make([]configurationValidator, 0, len(agentModules))
+	for _, m := range agentModules {
+		validators = append(validators, moduleValidator{
+			name:     m.factory(&App{}).Name(), // factories don't use the App to report the name
+			validate: m.validate,
+		})
+	}
+	return validators
+}
+
+// defaultAndValidateConfiguration applies defaults and validates the configuration with all enabled modules.
+// Disabled modules don't validate the configuration as they don't use it.
+func defaultAndValidateConfiguration(validators []configurationValidator, config *agentcfg.AgentConfiguration) []*agent_configuration_rpc.ConfigurationError {
+	var errs []*agent_configuration_rpc.ConfigurationError
+	known := make(map[string]struct{}, len(validators))
+	for _, v := range validators {
+		known[v.Name()] = struct{}{}
+	}
+	names := make([]string, 0, len(config.Modules))
+	for name := range config.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names) // stable error order
+	for _, name := range names {
+		if _, ok := known[name]; !ok {
+			errs = append(errs, &agent_configuration_rpc.ConfigurationError{
+				Module:  name,
+				Message: "unknown module",
+			})
+		}
+	}
+	for _, v := range validators {
+		if !moduleEnabled(config, v.Name()) {
+			continue
+		}
+		err := v.DefaultAndValidateConfiguration(config)
+		if err != nil {
+			errs = append(errs, &agent_configuration_rpc.ConfigurationError{
+				Module:  v.Name(),
+				Message: err.Error(),
+			})
+		}
+	}
+	return errs
+}
+
+// moduleEnabled returns true unless the configuration disables the module.
+func moduleEnabled(config *agentcfg.AgentConfiguration, name string) bool {
+	m := config.Modules[name]
+	return m == nil || m.Enabled == nil || m.Enabled.Value
+}
@@ -0,0 +1,68 @@
+package kasapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/cmd"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration"
+	agent_configuration_server "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/server"
+)
+
+const (
+	agentConfigValidateUsage = "usage: kas agent-config validate [--repository-root DIR] FILE"
+)
+
+// agentConfigValidateApp validates an agent's configuration file offline, the same way kas validates it before
+// sending it to agentk. Files, included from the same repository, are resolved relative to RepositoryRoot.
+// It prints the configuration that kas would send.
+type agentConfigValidateApp struct {
+	RepositoryRoot    string
+	ConfigurationFile string
+	Out               io.Writer
+	ErrOut            io.Writer
+}
+
+func (a *agentConfigValidateApp) Run(ctx context.Context) error {
+	filename, err := filepath.Rel(a.RepositoryRoot, a.ConfigurationFile)
+	if err != nil {
+		return err
+	}
+	config, skipped, err := agent_configuration_server.LoadLocalConfiguration(ctx, a.RepositoryRoot, filename)
+	if err != nil {
+		return err
+	}
+	for _, project := range skipped {
+		fmt.Fprintf(a.ErrOut, "Warning: files included from project %s are not validated\n", project) // nolint: errcheck
+	}
+	configYAML, err := agent_configuration.MarshalAgentConfiguration(config)
+	if err != nil {
+		return err
+	}
+	_, err = a.Out.Write(configYAML)
+	return err
+}
+
+func newAgentConfigCommandFromFlags(flagset *pflag.FlagSet, arguments []string) (cmd.Runnable, error) {
+	if len(arguments) == 0 || arguments[0] != "validate" {
+		return nil, errors.New(agentConfigValidateUsage)
+	}
+	app := &agentConfigValidateApp{
+		Out:    os.Stdout,
+		ErrOut: os.Stderr,
+	}
+	flagset.StringVar(&app.RepositoryRoot, "repository-root", ".", "Root directory of the checked out configuration repository")
+	if err := flagset.Parse(arguments[1:]); err != nil {
+		return nil, err
+	}
+	if flagset.NArg() != 1 {
+		return nil, errors.New(agentConfigValidateUsage)
+	}
+	app.ConfigurationFile = flagset.Arg(0)
+	return app, nil
+}
@@ -0,0 +1,87 @@
+package kasapp
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentConfigValidate(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ".gitlab/agents/agent1/config.yaml", `
+gitops:
+  manifest_projects:
+  - id: group/app
+`)
+	var out, errOut bytes.Buffer
+	app := &agentConfigValidateApp{
+		RepositoryRoot:    root,
+		ConfigurationFile: filepath.Join(root, ".gitlab/agents/agent1/config.yaml"),
+		Out:               &out,
+		ErrOut:            &errOut,
+	}
+	err := app.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, `gitops:
+  manifest_projects:
+  - id: group/app
+`, out.String())
+	assert.Empty(t, errOut.String())
+}
+
+func TestAgentConfigValidateIncludes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ".gitlab/agents/agent1/config.yaml", `
+include:
+- local: ../shared.yaml
+- project: group/shared
+  file: agents/base.yaml
+`)
+	writeFile(t, root, ".gitlab/agents/shared.yaml", `
+gitops:
+  manifest_projects:
+  - id: group/app
+`)
+	var out, errOut bytes.Buffer
+	app := &agentConfigValidateApp{
+		RepositoryRoot:    root,
+		ConfigurationFile: filepath.Join(root, ".gitlab/agents/agent1/config.yaml"),
+		Out:               &out,
+		ErrOut:            &errOut,
+	}
+	err := app.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, `gitops:
+  manifest_projects:
+  - id: group/app
+`, out.String())
+	assert.Equal(t, "Warning: files included from project group/shared are not validated\n", errOut.String())
+}
+
+func TestAgentConfigValidateMissingInclude(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, ".gitlab/agents/agent1/config.yaml", `
+include:
+- local: missing.yaml
+`)
+	app := &agentConfigValidateApp{
+		RepositoryRoot:    root,
+		ConfigurationFile: filepath.Join(root, ".gitlab/agents/agent1/config.yaml"),
+		Out:               ioutil.Discard,
+		ErrOut:            ioutil.Discard,
+	}
+	err := app.Run(context.Background())
+	assert.EqualError(t, err, "configuration file not found: .gitlab/agents/agent1/missing.yaml")
+}
+
+func writeFile(t *testing.T, root, name, data string) {
+	file := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+	require.NoError(t, ioutil.WriteFile(file, []byte(data), 0600))
+}
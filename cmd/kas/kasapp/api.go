@@ -74,10 +74,14 @@ func (a *serverAPI) GetAgentInfo(ctx context.Context, log *zap.Logger, agentToke
 }
 
 func (a *serverAPI) PollImmediateUntil(ctx context.Context, interval, maxConnectionAge time.Duration, condition modserver.ConditionFunc) error {
+	return a.PollImmediateUntilWithWakeup(ctx, interval, maxConnectionAge, nil, condition)
+}
+
+func (a *serverAPI) PollImmediateUntilWithWakeup(ctx context.Context, interval, maxConnectionAge time.Duration, wakeup <-chan struct{}, condition modserver.ConditionFunc) error {
 	// this context must only be used here, not inside of condition() - connection should be closed only when idle.
 	ageCtx, cancel := context.WithTimeout(ctx, mathz.DurationWithJitter(maxConnectionAge, maxConnectionAgeJitterPercent))
 	defer cancel()
-	err := retry.PollImmediateUntil(ageCtx, interval, wait.ConditionFunc(condition))
+	err := retry.PollImmediateUntilWithWakeup(ageCtx, interval, wakeup, wait.ConditionFunc(condition))
 	if errors.Is(err, wait.ErrWaitTimeout) {
 		return nil // all good, ctx is done
 	}
@@ -92,8 +96,8 @@ func (a *serverAPI) HandleProcessingError(ctx context.Context, log *zap.Logger,
 	var ue *errz.UserError
 	isUserError := errors.As(err, &ue)
 	if isUserError {
-		// TODO Don't log it, send it somewhere the user can see it https://gitlab.com/gitlab-org/gitlab/-/issues/277323
-		// Log at Info for now.
+		// User errors are not tracked. Modules report them to GitLab where the user can see them,
+		// e.g. errors in agent configuration. See https://gitlab.com/gitlab-org/gitlab/-/issues/277323
 		log.Info(msg, zap.Error(err))
 	} else {
 		a.logAndCapture(ctx, log, msg, err)
@@ -67,6 +67,9 @@ func LoadConfigurationFile(configFile string) (*kascfg.ConfigurationFile, error)
 }
 
 func NewFromFlags(flagset *pflag.FlagSet, arguments []string) (cmd.Runnable, error) {
+	if len(arguments) > 0 && arguments[0] == "agent-config" {
+		return newAgentConfigCommandFromFlags(flagset, arguments[1:])
+	}
 	app := &App{}
 	flagset.StringVar(&app.ConfigurationFile, "configuration-file", "", "Optional configuration file to use (YAML)")
 	if err := flagset.Parse(arguments); err != nil {
@@ -4,12 +4,15 @@ go 1.15
 
 require (
 	cloud.google.com/go v0.74.0
+	github.com/Masterminds/semver/v3 v3.1.1
 	github.com/argoproj/gitops-engine v0.2.1-0.20210108000020-0b4199b00135
 	github.com/ash2k/stager v0.2.1
 	github.com/bmatcuk/doublestar/v2 v2.0.4
 	github.com/cilium/cilium v1.8.1
 	github.com/dgrijalva/jwt-go/v4 v4.0.0-preview1.0.20200107205605-c66185887605
 	github.com/envoyproxy/protoc-gen-validate v0.4.2-0.20201217164128-7df253a68e6b
+	github.com/go-git/go-billy/v5 v5.0.0
+	github.com/go-git/go-git/v5 v5.2.0
 	github.com/go-logr/zapr v0.3.0
 	github.com/go-redis/redis/v8 v8.4.4
 	github.com/go-redis/redismock/v8 v8.0.3
@@ -26,6 +29,7 @@ require (
 	gitlab.com/gitlab-org/gitaly v1.87.1-0.20201117220727-89c1ee804f27
 	gitlab.com/gitlab-org/labkit v1.2.0
 	go.uber.org/zap v1.16.0
+	golang.org/x/crypto v0.0.0-20201002170205-7f63de1d35b0
 	golang.org/x/sync v0.0.0-20201207232520-09787c993a3a
 	golang.org/x/time v0.0.0-20200630173020-3af7569d3a1e
 	golang.org/x/tools v0.0.0-20201208233053-a543418bbed2
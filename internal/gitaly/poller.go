@@ -6,12 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/Masterminds/semver/v3"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
 	"gitlab.com/gitlab-org/gitaly/proto/go/gitalypb"
 )
 
 const (
 	DefaultBranch = ""
+
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+	peeledRefSuffix = "^{}"
 )
 
 var (
@@ -20,20 +27,35 @@ var (
 
 type PollerInterface interface {
 	Poll(ctx context.Context, repo *gitalypb.Repository, lastProcessedCommitId, refName string) (*PollInfo, error)
+	PollSemverTag(ctx context.Context, repo *gitalypb.Repository, lastProcessedCommitId string, constraint *semver.Constraints) (*PollInfo, error)
+	Branches(ctx context.Context, repo *gitalypb.Repository) ([]string, error)
 }
 
 // Poller does the following:
 // - polls ref advertisement for updates to the repository
 // - detects which is the main branch, if branch or tag name is not specified
+// - lists branches of the repository
+// - finds the highest tag, satisfying a semantic version constraint, if one is specified
 // - compares the commit id the branch or tag is referring to with the last processed one
 // - returns the information about the change
 type Poller struct {
 	Client gitalypb.SmartHTTPServiceClient
 }
 
+// RefNotFoundError is returned by Poll when the requested branch or tag does not exist.
+type RefNotFoundError struct {
+	RefName string
+}
+
+func (e *RefNotFoundError) Error() string {
+	return fmt.Sprintf("ref %q not found", e.RefName)
+}
+
 type PollInfo struct {
 	UpdateAvailable bool
 	CommitId        string
+	// Tag is the name of the selected tag. Only set by PollSemverTag.
+	Tag string
 }
 
 // Poll performs a poll on the repository.
@@ -45,7 +67,7 @@ func (p *Poller) Poll(ctx context.Context, repo *gitalypb.Repository, lastProces
 		return nil, err // don't wrap
 	}
 	refNameTag := "refs/tags/" + refName
-	refNameBranch := "refs/heads/" + refName
+	refNameBranch := branchRefPrefix + refName
 	var head, master, wanted *Reference
 
 loop:
@@ -62,7 +84,9 @@ loop:
 	}
 	if wanted == nil { // not found
 		if refName != DefaultBranch { // were looking for something specific, but didn't find it
-			return nil, fmt.Errorf("ref %q not found", refName)
+			return nil, &RefNotFoundError{
+				RefName: refName,
+			}
 		}
 		// looking for default branch
 		if head != nil {
@@ -79,6 +103,72 @@ loop:
 	}, nil
 }
 
+// PollSemverTag performs a poll on the repository, looking for the highest tag that satisfies the constraint.
+// Tags that are not valid semantic versions are ignored. For annotated tags, the commit the tag points to is used.
+// PollSemverTag returns a wrapped context.Canceled, context.DeadlineExceeded or gRPC error if ctx signals done and interrupts a running gRPC call.
+func (p *Poller) PollSemverTag(ctx context.Context, repo *gitalypb.Repository, lastProcessedCommitId string, constraint *semver.Constraints) (*PollInfo, error) {
+	r, err := p.fetchRefs(ctx, repo)
+	if err != nil {
+		return nil, err // don't wrap
+	}
+	var (
+		wanted        *Reference
+		wantedTag     string
+		wantedVersion *semver.Version
+	)
+	peeled := make(map[string]string) // tag name -> commit id
+	for i := range r.Refs {
+		if !strings.HasPrefix(r.Refs[i].Name, tagRefPrefix) {
+			continue
+		}
+		tag := strings.TrimPrefix(r.Refs[i].Name, tagRefPrefix)
+		if strings.HasSuffix(tag, peeledRefSuffix) {
+			peeled[strings.TrimSuffix(tag, peeledRefSuffix)] = r.Refs[i].Oid
+			continue
+		}
+		v, err := semver.NewVersion(tag) // nolint: govet
+		if err != nil {
+			continue // not a semantic version
+		}
+		if !constraint.Check(v) {
+			continue
+		}
+		if wantedVersion == nil || v.GreaterThan(wantedVersion) {
+			wanted = &r.Refs[i]
+			wantedTag = tag
+			wantedVersion = v
+		}
+	}
+	if wanted == nil {
+		return nil, errz.NewUserErrorf("no tag satisfies semantic version constraint %q", constraint)
+	}
+	commitId := wanted.Oid
+	if peeledCommitId, ok := peeled[wantedTag]; ok { // annotated tag
+		commitId = peeledCommitId
+	}
+	return &PollInfo{
+		UpdateAvailable: commitId != lastProcessedCommitId,
+		CommitId:        commitId,
+		Tag:             wantedTag,
+	}, nil
+}
+
+// Branches returns names of all branches in the repository, in the order they were advertised.
+// Branches returns a wrapped context.Canceled, context.DeadlineExceeded or gRPC error if ctx signals done and interrupts a running gRPC call.
+func (p *Poller) Branches(ctx context.Context, repo *gitalypb.Repository) ([]string, error) {
+	r, err := p.fetchRefs(ctx, repo)
+	if err != nil {
+		return nil, err // don't wrap
+	}
+	var branches []string
+	for i := range r.Refs {
+		if strings.HasPrefix(r.Refs[i].Name, branchRefPrefix) {
+			branches = append(branches, strings.TrimPrefix(r.Refs[i].Name, branchRefPrefix))
+		}
+	}
+	return branches, nil
+}
+
 // fetchRefs returns a wrapped context.Canceled, context.DeadlineExceeded or gRPC error if ctx signals done and interrupts a running gRPC call.
 func (p *Poller) fetchRefs(ctx context.Context, repo *gitalypb.Repository) (*ReferenceDiscovery, error) {
 	ctx, cancel := context.WithCancel(ctx)
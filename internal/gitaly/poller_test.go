@@ -2,12 +2,16 @@ package gitaly
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/matcher"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_gitaly"
 	"gitlab.com/gitlab-org/gitaly/proto/go/gitalypb"
@@ -116,6 +120,104 @@ func TestPoller(t *testing.T) {
 	}
 }
 
+func TestPollerSemverTag(t *testing.T) {
+	const (
+		revision3 = "d52802a91a0685d8507ebc6de9bcac25628aa7af"
+		revision4 = "a91a0685d8507ebc6de9bcac25628aa7afd52802"
+		tagObject = "bcac25628aa7afd52802a91a0685d8507ebc6de9"
+	)
+	data := `001e# service=git-upload-pack
+00000148` + revision1 + ` HEAD` + "\x00" + `multi_ack thin-pack side-band side-band-64k ofs-delta shallow deepen-since deepen-not deepen-relative no-progress include-tag multi_ack_detailed allow-tip-sha1-in-want allow-reachable-sha1-in-want no-done symref=HEAD:refs/heads/master filter object-format=sha1 agent=git/2.28.0
+003f` + revision1 + ` refs/heads/master
+` + pktLine(revision2+" refs/tags/v1.0.0") +
+		pktLine(revision3+" refs/tags/v1.1.0") +
+		pktLine(tagObject+" refs/tags/v1.2.0") +
+		pktLine(revision4+" refs/tags/v1.2.0^{}") +
+		pktLine(revision1+" refs/tags/v2.0.0") +
+		pktLine(revision1+" refs/tags/not-a-version") +
+		"0000"
+	tests := []struct {
+		name                string
+		constraint          string
+		lastProcessedCommit string
+		expectedInfoCommit  string
+		expectedInfoTag     string
+		expectedInfoUpdate  bool
+	}{
+		{
+			name:               "lightweight tag",
+			constraint:         "~1.1.0",
+			expectedInfoCommit: revision3,
+			expectedInfoTag:    "v1.1.0",
+			expectedInfoUpdate: true,
+		},
+		{
+			name:               "annotated tag",
+			constraint:         "^1",
+			expectedInfoCommit: revision4,
+			expectedInfoTag:    "v1.2.0",
+			expectedInfoUpdate: true,
+		},
+		{
+			name:                "highest tag same commit",
+			constraint:          ">= 1.0",
+			lastProcessedCommit: revision1,
+			expectedInfoCommit:  revision1,
+			expectedInfoTag:     "v2.0.0",
+			expectedInfoUpdate:  false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			r := repo()
+			infoRefsReq := &gitalypb.InfoRefsRequest{Repository: r}
+			httpClient := mock_gitaly.NewMockSmartHTTPServiceClient(mockCtrl)
+			mockInfoRefsUploadPack(t, mockCtrl, httpClient, infoRefsReq, []byte(data))
+			p := Poller{
+				Client: httpClient,
+			}
+			constraint, err := semver.NewConstraint(tc.constraint) // nolint: scopelint
+			require.NoError(t, err)
+			pollInfo, err := p.PollSemverTag(context.Background(), r, tc.lastProcessedCommit, constraint) // nolint: scopelint
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedInfoUpdate, pollInfo.UpdateAvailable) // nolint: scopelint
+			assert.Equal(t, tc.expectedInfoCommit, pollInfo.CommitId)        // nolint: scopelint
+			assert.Equal(t, tc.expectedInfoTag, pollInfo.Tag)                // nolint: scopelint
+		})
+	}
+	t.Run("no matching tag", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		r := repo()
+		infoRefsReq := &gitalypb.InfoRefsRequest{Repository: r}
+		httpClient := mock_gitaly.NewMockSmartHTTPServiceClient(mockCtrl)
+		mockInfoRefsUploadPack(t, mockCtrl, httpClient, infoRefsReq, []byte(data))
+		p := Poller{
+			Client: httpClient,
+		}
+		constraint, err := semver.NewConstraint("~3")
+		require.NoError(t, err)
+		_, err = p.PollSemverTag(context.Background(), r, "", constraint)
+		require.EqualError(t, err, `no tag satisfies semantic version constraint "~3"`)
+		var ue *errz.UserError
+		assert.True(t, errors.As(err, &ue))
+	})
+}
+
+func TestPollerBranches(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	r := repo()
+	infoRefsReq := &gitalypb.InfoRefsRequest{Repository: r}
+	httpClient := mock_gitaly.NewMockSmartHTTPServiceClient(mockCtrl)
+	mockInfoRefsUploadPack(t, mockCtrl, httpClient, infoRefsReq, []byte(infoRefsData))
+	p := Poller{
+		Client: httpClient,
+	}
+	branches, err := p.Branches(context.Background(), r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"master", branch}, branches)
+}
+
 func TestPollerErrors(t *testing.T) {
 	t.Run("branch not found", func(t *testing.T) {
 		mockCtrl := gomock.NewController(t)
@@ -128,6 +230,9 @@ func TestPollerErrors(t *testing.T) {
 		}
 		_, err := p.Poll(context.Background(), r, "", "some_branch")
 		require.EqualError(t, err, `ref "some_branch" not found`)
+		var notFound *RefNotFoundError
+		assert.True(t, errors.As(err, &notFound))
+		assert.False(t, errors.As(err, new(*errz.UserError)))
 	})
 	t.Run("no HEAD", func(t *testing.T) {
 		noHEAD := `001e# service=git-upload-pack
@@ -165,6 +270,10 @@ func TestPollerErrors(t *testing.T) {
 	})
 }
 
+func pktLine(line string) string {
+	return fmt.Sprintf("%04x%s\n", len(line)+5, line)
+}
+
 func mockInfoRefsUploadPack(t *testing.T, mockCtrl *gomock.Controller, httpClient *mock_gitaly.MockSmartHTTPServiceClient, infoRefsReq *gitalypb.InfoRefsRequest, data []byte) {
 	infoRefsClient := mock_gitaly.NewMockSmartHTTPService_InfoRefsUploadPackClient(mockCtrl)
 	// Emulate streaming response
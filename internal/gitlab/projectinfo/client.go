@@ -1,4 +1,4 @@
-package server
+package projectinfo
 
 import (
 	"context"
@@ -12,23 +12,24 @@ import (
 )
 
 const (
-	projectInfoApiPath  = "/api/v4/internal/kubernetes/project_info"
-	projectIdQueryParam = "id"
+	ApiPath             = "/api/v4/internal/kubernetes/project_info"
+	ProjectIdQueryParam = "id"
 )
 
-type projectInfoClient struct {
-	GitLabClient             gitlab.ClientInterface
-	ProjectInfoCacheTtl      time.Duration
-	ProjectInfoCacheErrorTtl time.Duration
-	ProjectInfoCache         *cache.Cache
+// Client gets information about projects that an agent has access to.
+type Client struct {
+	GitLabClient  gitlab.ClientInterface
+	CacheTtl      time.Duration
+	CacheErrorTtl time.Duration
+	Cache         *cache.Cache
 }
 
-func (c *projectInfoClient) GetProjectInfo(ctx context.Context, agentToken api.AgentToken, projectId string) (*api.ProjectInfo, error) {
-	if c.ProjectInfoCacheTtl == 0 {
+func (c *Client) GetProjectInfo(ctx context.Context, agentToken api.AgentToken, projectId string) (*api.ProjectInfo, error) {
+	if c.CacheTtl == 0 {
 		return c.getProjectInfoDirect(ctx, agentToken, projectId)
 	}
-	c.ProjectInfoCache.EvictExpiredEntries()
-	entry := c.ProjectInfoCache.GetOrCreateCacheEntry(projectInfoCacheKey{
+	c.Cache.EvictExpiredEntries()
+	entry := c.Cache.GetOrCreateCacheEntry(cacheKey{
 		agentToken: agentToken,
 		projectId:  projectId,
 	})
@@ -36,29 +37,29 @@ func (c *projectInfoClient) GetProjectInfo(ctx context.Context, agentToken api.A
 		return nil, ctx.Err()
 	}
 	defer entry.Unlock()
-	var item projectInfoCacheItem
+	var item cacheItem
 	if entry.IsNeedRefreshLocked() {
 		item.projectInfo, item.err = c.getProjectInfoDirect(ctx, agentToken, projectId)
 		var ttl time.Duration
 		if item.err == nil {
-			ttl = c.ProjectInfoCacheTtl
+			ttl = c.CacheTtl
 		} else {
-			ttl = c.ProjectInfoCacheErrorTtl
+			ttl = c.CacheErrorTtl
 		}
 		entry.Item = item
 		entry.Expires = time.Now().Add(ttl)
 	} else {
-		item = entry.Item.(projectInfoCacheItem)
+		item = entry.Item.(cacheItem)
 	}
 	return item.projectInfo, item.err
 }
 
-func (c *projectInfoClient) getProjectInfoDirect(ctx context.Context, agentToken api.AgentToken, projectId string) (*api.ProjectInfo, error) {
+func (c *Client) getProjectInfoDirect(ctx context.Context, agentToken api.AgentToken, projectId string) (*api.ProjectInfo, error) {
 	query := url.Values{
-		projectIdQueryParam: []string{projectId},
+		ProjectIdQueryParam: []string{projectId},
 	}
-	response := projectInfoResponse{}
-	err := c.GitLabClient.DoJSON(ctx, http.MethodGet, projectInfoApiPath, query, agentToken, nil, &response)
+	response := Response{}
+	err := c.GitLabClient.DoJSON(ctx, http.MethodGet, ApiPath, query, agentToken, nil, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -69,19 +70,20 @@ func (c *projectInfoClient) getProjectInfoDirect(ctx context.Context, agentToken
 	}, nil
 }
 
-type projectInfoResponse struct {
+// Response is the response of the project info API.
+type Response struct {
 	ProjectId        int64                   `json:"project_id"`
 	GitalyInfo       gitlab.GitalyInfo       `json:"gitaly_info"`
 	GitalyRepository gitlab.GitalyRepository `json:"gitaly_repository"`
 }
 
-type projectInfoCacheKey struct {
+type cacheKey struct {
 	agentToken api.AgentToken
 	projectId  string
 }
 
-// projectInfoCacheItem holds cached information about a project.
-type projectInfoCacheItem struct {
+// cacheItem holds cached information about a project.
+type cacheItem struct {
 	projectInfo *api.ProjectInfo
 	err         error
 }
@@ -1,4 +1,4 @@
-package server
+package projectinfo
 
 import (
 	"net/http"
@@ -18,7 +18,7 @@ func TestGetProjectInfo(t *testing.T) {
 		projectId = "bla/bla"
 	)
 	ctx, correlationId := mock_gitlab.CtxWithCorrelation(t)
-	response := projectInfoResponse{
+	response := Response{
 		ProjectId: 234,
 		GitalyInfo: gitlab.GitalyInfo{
 			Address: "example.com",
@@ -35,12 +35,12 @@ func TestGetProjectInfo(t *testing.T) {
 		},
 	}
 	r := http.NewServeMux()
-	r.HandleFunc(projectInfoApiPath, func(w http.ResponseWriter, r *http.Request) {
+	r.HandleFunc(ApiPath, func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodGet, r.Method)
 		if !mock_gitlab.AssertGetRequestIsCorrect(t, w, r, correlationId) {
 			return
 		}
-		assert.Equal(t, projectId, r.URL.Query().Get(projectIdQueryParam))
+		assert.Equal(t, projectId, r.URL.Query().Get(ProjectIdQueryParam))
 
 		mock_gitlab.RespondWithJSON(t, w, response)
 	})
@@ -49,11 +49,11 @@ func TestGetProjectInfo(t *testing.T) {
 
 	u, err := url.Parse(s.URL)
 	require.NoError(t, err)
-	pic := projectInfoClient{
-		GitLabClient:             gitlab.NewClient(u, []byte(mock_gitlab.AuthSecretKey), mock_gitlab.ClientOptionsForTest()...),
-		ProjectInfoCacheTtl:      0, // no cache
-		ProjectInfoCacheErrorTtl: 0,
-		ProjectInfoCache:         cache.New(0),
+	pic := Client{
+		GitLabClient:  gitlab.NewClient(u, []byte(mock_gitlab.AuthSecretKey), mock_gitlab.ClientOptionsForTest()...),
+		CacheTtl:      0, // no cache
+		CacheErrorTtl: 0,
+		Cache:         cache.New(0),
 	}
 
 	projInfo, err := pic.GetProjectInfo(ctx, mock_gitlab.AgentkToken, projectId)
@@ -0,0 +1,66 @@
+package agent_configuration
+
+import (
+	"bytes"
+	"fmt"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/yaml"
+)
+
+// ParseConfigurationFile parses the YAML of an agent's configuration file.
+// The parsed configuration is not validated.
+func ParseConfigurationFile(configYAML []byte) (*agentcfg.ConfigurationFile, error) {
+	configJSON, err := yaml.YAMLToJSON(configYAML)
+	if err != nil {
+		return nil, fmt.Errorf("YAMLToJSON: %v", err)
+	}
+	configFile := &agentcfg.ConfigurationFile{}
+	if bytes.Equal(configJSON, []byte("null")) {
+		// Empty config
+		return configFile, nil
+	}
+	err = protojson.Unmarshal(configJSON, configFile)
+	if err != nil {
+		return nil, fmt.Errorf("protojson.Unmarshal: %v", err)
+	}
+	return configFile, nil
+}
+
+// ValidateConfigurationFile parses and validates an agent's configuration file the same way kas does, except that
+// include directives are not resolved.
+func ValidateConfigurationFile(configYAML []byte) (*agentcfg.ConfigurationFile, error) {
+	configFile, err := ParseConfigurationFile(configYAML)
+	if err != nil {
+		return nil, err
+	}
+	err = configFile.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent configuration: %v", err)
+	}
+	return configFile, nil
+}
+
+// MarshalAgentConfiguration returns the configuration as YAML, in the syntax of the configuration file.
+func MarshalAgentConfiguration(config *agentcfg.AgentConfiguration) ([]byte, error) {
+	configJSON, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("protojson.Marshal: %v", err)
+	}
+	configYAML, err := yaml.JSONToYAML(configJSON)
+	if err != nil {
+		return nil, fmt.Errorf("JSONToYAML: %v", err)
+	}
+	return configYAML, nil
+}
+
+// ToAgentConfiguration returns the part of the configuration file that is sent to agentk.
+func ToAgentConfiguration(configFile *agentcfg.ConfigurationFile) *agentcfg.AgentConfiguration {
+	return &agentcfg.AgentConfiguration{
+		Gitops:        configFile.Gitops,
+		Observability: configFile.Observability,
+		Cilium:        configFile.Cilium,
+		Modules:       configFile.Modules,
+	}
+}
@@ -11,14 +11,19 @@ import (
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type ConfigurationData struct {
 	CommitId string
 	Config   *agentcfg.AgentConfiguration
+	// AgentIdentity is the identity of the agent in GitLab. nil if kas did not send it.
+	AgentIdentity *modshared.AgentIdentity
 }
 
-type ConfigurationCallback func(context.Context, ConfigurationData)
+// ConfigurationCallback applies the configuration and returns the configuration that the agent is running with afterwards.
+type ConfigurationCallback func(context.Context, ConfigurationData) *AppliedConfiguration
 
 // ConfigurationWatcherInterface abstracts ConfigurationWatcher.
 type ConfigurationWatcherInterface interface {
@@ -30,16 +35,24 @@ type ConfigurationWatcher struct {
 	AgentMeta   *modshared.AgentMeta
 	Client      AgentConfigurationClient
 	RetryPeriod time.Duration
+	// Ref is the branch or tag of the configuration project to read the configuration from.
+	// Empty means the default branch.
+	Ref string
 }
 
 func (w *ConfigurationWatcher) Watch(ctx context.Context, callback ConfigurationCallback) {
-	var lastProcessedCommitId string
+	var (
+		lastProcessedCommitId string
+		applied               *AppliedConfiguration
+	)
 	retry.JitterUntil(ctx, w.RetryPeriod, func(ctx context.Context) {
 		ctx, cancel := context.WithCancel(ctx)
 		defer cancel() // ensure streaming call is canceled
 		req := &ConfigurationRequest{
-			CommitId:  lastProcessedCommitId,
-			AgentMeta: w.AgentMeta,
+			CommitId:             lastProcessedCommitId,
+			AgentMeta:            w.AgentMeta,
+			AppliedConfiguration: applied,
+			Ref:                  w.Ref,
 		}
 		res, err := w.Client.GetConfiguration(ctx, req)
 		if err != nil {
@@ -59,11 +72,33 @@ func (w *ConfigurationWatcher) Watch(ctx context.Context, callback Configuration
 				}
 				return
 			}
-			callback(ctx, ConfigurationData{
-				CommitId: config.CommitId,
-				Config:   config.Configuration,
+			applied = callback(ctx, ConfigurationData{
+				CommitId:      config.CommitId,
+				Config:        config.Configuration,
+				AgentIdentity: config.AgentIdentity,
 			})
 			lastProcessedCommitId = config.CommitId
+			w.reportAppliedConfiguration(ctx, applied)
 		}
 	})
 }
+
+// reportAppliedConfiguration tells kas which configuration the agent is running with.
+// The report is best effort: kas gets the applied configuration with the next GetConfiguration request anyway.
+func (w *ConfigurationWatcher) reportAppliedConfiguration(ctx context.Context, applied *AppliedConfiguration) {
+	if applied == nil {
+		return
+	}
+	_, err := w.Client.ReportAppliedConfiguration(ctx, &ReportAppliedConfigurationRequest{
+		AgentMeta:            w.AgentMeta,
+		AppliedConfiguration: applied,
+	})
+	switch {
+	case err == nil:
+	case grpctool.RequestCanceled(err):
+	case status.Code(err) == codes.Unimplemented:
+		w.Log.Debug("kas does not support ReportAppliedConfiguration", zap.Error(err))
+	default:
+		w.Log.Warn("ReportAppliedConfiguration failed", zap.Error(err))
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/matcher"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
@@ -31,7 +32,8 @@ func TestConfigurationWatcher(t *testing.T) {
 	defer cancel()
 	mockCtrl := gomock.NewController(t)
 	client := mock_rpc.NewMockAgentConfigurationClient(mockCtrl)
-	configStream := mock_rpc.NewMockAgentConfiguration_GetConfigurationClient(mockCtrl)
+	configStream1 := mock_rpc.NewMockAgentConfiguration_GetConfigurationClient(mockCtrl)
+	configStream2 := mock_rpc.NewMockAgentConfiguration_GetConfigurationClient(mockCtrl)
 	cfg1 := &agentcfg.AgentConfiguration{
 		Gitops: &agentcfg.GitopsCF{
 			ManifestProjects: []*agentcfg.ManifestProjectCF{
@@ -42,23 +44,64 @@ func TestConfigurationWatcher(t *testing.T) {
 		},
 	}
 	cfg2 := &agentcfg.AgentConfiguration{}
+	identity := &modshared.AgentIdentity{
+		Id:          123,
+		Name:        "agent1",
+		ProjectId:   234,
+		ProjectPath: "group/project",
+	}
+	applied1 := &rpc.AppliedConfiguration{
+		CommitId: revision1,
+		Hash:     "hash1",
+	}
+	applied2 := &rpc.AppliedConfiguration{
+		CommitId:         revision1,
+		Hash:             "hash1",
+		RejectedCommitId: revision2,
+		Errors: []*rpc.ConfigurationError{
+			{
+				Module:  "gitops",
+				Message: "bad config",
+			},
+		},
+	}
 	gomock.InOrder(
 		client.EXPECT().
 			GetConfiguration(gomock.Any(), matcher.ProtoEq(t, &rpc.ConfigurationRequest{})).
-			Return(configStream, nil),
-		configStream.EXPECT().
+			Return(configStream1, nil),
+		configStream1.EXPECT().
 			Recv().
 			Return(&rpc.ConfigurationResponse{
 				Configuration: cfg1,
 				CommitId:      revision1,
+				AgentIdentity: identity,
 			}, nil),
-		configStream.EXPECT().
+		client.EXPECT().
+			ReportAppliedConfiguration(gomock.Any(), matcher.ProtoEq(t, &rpc.ReportAppliedConfigurationRequest{
+				AppliedConfiguration: applied1,
+			})).
+			Return(&rpc.ReportAppliedConfigurationResponse{}, nil),
+		configStream1.EXPECT().
 			Recv().
 			Return(&rpc.ConfigurationResponse{
 				Configuration: cfg2,
 				CommitId:      revision2,
 			}, nil),
-		configStream.EXPECT().
+		client.EXPECT().
+			ReportAppliedConfiguration(gomock.Any(), matcher.ProtoEq(t, &rpc.ReportAppliedConfigurationRequest{
+				AppliedConfiguration: applied2,
+			})).
+			Return(&rpc.ReportAppliedConfigurationResponse{}, nil),
+		configStream1.EXPECT().
+			Recv().
+			Return(nil, io.EOF),
+		client.EXPECT().
+			GetConfiguration(gomock.Any(), matcher.ProtoEq(t, &rpc.ConfigurationRequest{
+				CommitId:             revision2,
+				AppliedConfiguration: applied2,
+			})).
+			Return(configStream2, nil),
+		configStream2.EXPECT().
 			Recv().
 			DoAndReturn(func() (*rpc.ConfigurationResponse, error) {
 				cancel()
@@ -71,16 +114,22 @@ func TestConfigurationWatcher(t *testing.T) {
 		RetryPeriod: 10 * time.Millisecond,
 	}
 	iter := 0
-	w.Watch(ctx, func(ctx context.Context, config rpc.ConfigurationData) {
+	w.Watch(ctx, func(ctx context.Context, config rpc.ConfigurationData) *rpc.AppliedConfiguration {
+		defer func() {
+			iter++
+		}()
 		switch iter {
 		case 0:
 			assert.Empty(t, cmp.Diff(config.Config, cfg1, protocmp.Transform()))
+			assert.Empty(t, cmp.Diff(config.AgentIdentity, identity, protocmp.Transform()))
+			return applied1
 		case 1:
 			assert.Empty(t, cmp.Diff(config.Config, cfg2, protocmp.Transform()))
+			return applied2
 		default:
 			t.Fatal(iter)
+			return nil
 		}
-		iter++
 	})
 	assert.EqualValues(t, 2, iter)
 }
@@ -122,7 +171,7 @@ func TestConfigurationWatcherResumeConnection(t *testing.T) {
 		Client:      client,
 		RetryPeriod: 10 * time.Millisecond,
 	}
-	w.Watch(ctx, func(ctx context.Context, config rpc.ConfigurationData) {
-		// Don't care
+	w.Watch(ctx, func(ctx context.Context, config rpc.ConfigurationData) *rpc.AppliedConfiguration {
+		return nil // Don't care
 	})
 }
@@ -38,8 +38,10 @@ type ConfigurationRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CommitId  string               `protobuf:"bytes,1,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
-	AgentMeta *modshared.AgentMeta `protobuf:"bytes,2,opt,name=agent_meta,json=agentMeta,proto3" json:"agent_meta,omitempty"`
+	CommitId             string                `protobuf:"bytes,1,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
+	AgentMeta            *modshared.AgentMeta  `protobuf:"bytes,2,opt,name=agent_meta,json=agentMeta,proto3" json:"agent_meta,omitempty"`
+	AppliedConfiguration *AppliedConfiguration `protobuf:"bytes,3,opt,name=applied_configuration,json=appliedConfiguration,proto3" json:"applied_configuration,omitempty"`
+	Ref                  string                `protobuf:"bytes,4,opt,name=ref,proto3" json:"ref,omitempty"`
 }
 
 func (x *ConfigurationRequest) Reset() {
@@ -88,6 +90,20 @@ func (x *ConfigurationRequest) GetAgentMeta() *modshared.AgentMeta {
 	return nil
 }
 
+func (x *ConfigurationRequest) GetAppliedConfiguration() *AppliedConfiguration {
+	if x != nil {
+		return x.AppliedConfiguration
+	}
+	return nil
+}
+
+func (x *ConfigurationRequest) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
 type ConfigurationResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -95,6 +111,7 @@ type ConfigurationResponse struct {
 
 	Configuration *agentcfg.AgentConfiguration `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
 	CommitId      string                       `protobuf:"bytes,2,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
+	AgentIdentity *modshared.AgentIdentity     `protobuf:"bytes,3,opt,name=agent_identity,json=agentIdentity,proto3" json:"agent_identity,omitempty"`
 }
 
 func (x *ConfigurationResponse) Reset() {
@@ -143,6 +160,418 @@ func (x *ConfigurationResponse) GetCommitId() string {
 	return ""
 }
 
+func (x *ConfigurationResponse) GetAgentIdentity() *modshared.AgentIdentity {
+	if x != nil {
+		return x.AgentIdentity
+	}
+	return nil
+}
+
+type ConfigurationError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Module  string `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Local   bool   `protobuf:"varint,3,opt,name=local,proto3" json:"local,omitempty"`
+}
+
+func (x *ConfigurationError) Reset() {
+	*x = ConfigurationError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigurationError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigurationError) ProtoMessage() {}
+
+func (x *ConfigurationError) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigurationError.ProtoReflect.Descriptor instead.
+func (*ConfigurationError) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConfigurationError) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *ConfigurationError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ConfigurationError) GetLocal() bool {
+	if x != nil {
+		return x.Local
+	}
+	return false
+}
+
+type AppliedConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommitId         string                `protobuf:"bytes,1,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
+	Hash             string                `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	RejectedCommitId string                `protobuf:"bytes,3,opt,name=rejected_commit_id,json=rejectedCommitId,proto3" json:"rejected_commit_id,omitempty"`
+	Errors           []*ConfigurationError `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *AppliedConfiguration) Reset() {
+	*x = AppliedConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppliedConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppliedConfiguration) ProtoMessage() {}
+
+func (x *AppliedConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppliedConfiguration.ProtoReflect.Descriptor instead.
+func (*AppliedConfiguration) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AppliedConfiguration) GetCommitId() string {
+	if x != nil {
+		return x.CommitId
+	}
+	return ""
+}
+
+func (x *AppliedConfiguration) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *AppliedConfiguration) GetRejectedCommitId() string {
+	if x != nil {
+		return x.RejectedCommitId
+	}
+	return ""
+}
+
+func (x *AppliedConfiguration) GetErrors() []*ConfigurationError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ReportConfigurationErrorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommitId string                `protobuf:"bytes,1,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
+	Errors   []*ConfigurationError `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *ReportConfigurationErrorsRequest) Reset() {
+	*x = ReportConfigurationErrorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportConfigurationErrorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportConfigurationErrorsRequest) ProtoMessage() {}
+
+func (x *ReportConfigurationErrorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportConfigurationErrorsRequest.ProtoReflect.Descriptor instead.
+func (*ReportConfigurationErrorsRequest) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReportConfigurationErrorsRequest) GetCommitId() string {
+	if x != nil {
+		return x.CommitId
+	}
+	return ""
+}
+
+func (x *ReportConfigurationErrorsRequest) GetErrors() []*ConfigurationError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ReportConfigurationErrorsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReportConfigurationErrorsResponse) Reset() {
+	*x = ReportConfigurationErrorsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportConfigurationErrorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportConfigurationErrorsResponse) ProtoMessage() {}
+
+func (x *ReportConfigurationErrorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportConfigurationErrorsResponse.ProtoReflect.Descriptor instead.
+func (*ReportConfigurationErrorsResponse) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP(), []int{5}
+}
+
+type ReportAppliedConfigurationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AgentMeta            *modshared.AgentMeta  `protobuf:"bytes,1,opt,name=agent_meta,json=agentMeta,proto3" json:"agent_meta,omitempty"`
+	AppliedConfiguration *AppliedConfiguration `protobuf:"bytes,2,opt,name=applied_configuration,json=appliedConfiguration,proto3" json:"applied_configuration,omitempty"`
+}
+
+func (x *ReportAppliedConfigurationRequest) Reset() {
+	*x = ReportAppliedConfigurationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportAppliedConfigurationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportAppliedConfigurationRequest) ProtoMessage() {}
+
+func (x *ReportAppliedConfigurationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportAppliedConfigurationRequest.ProtoReflect.Descriptor instead.
+func (*ReportAppliedConfigurationRequest) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReportAppliedConfigurationRequest) GetAgentMeta() *modshared.AgentMeta {
+	if x != nil {
+		return x.AgentMeta
+	}
+	return nil
+}
+
+func (x *ReportAppliedConfigurationRequest) GetAppliedConfiguration() *AppliedConfiguration {
+	if x != nil {
+		return x.AppliedConfiguration
+	}
+	return nil
+}
+
+type ReportAppliedConfigurationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReportAppliedConfigurationResponse) Reset() {
+	*x = ReportAppliedConfigurationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportAppliedConfigurationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportAppliedConfigurationResponse) ProtoMessage() {}
+
+func (x *ReportAppliedConfigurationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportAppliedConfigurationResponse.ProtoReflect.Descriptor instead.
+func (*ReportAppliedConfigurationResponse) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP(), []int{7}
+}
+
+type ProjectUpdatedRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProjectId int64 `protobuf:"varint,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+}
+
+func (x *ProjectUpdatedRequest) Reset() {
+	*x = ProjectUpdatedRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectUpdatedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectUpdatedRequest) ProtoMessage() {}
+
+func (x *ProjectUpdatedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectUpdatedRequest.ProtoReflect.Descriptor instead.
+func (*ProjectUpdatedRequest) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProjectUpdatedRequest) GetProjectId() int64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+type ProjectUpdatedResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ProjectUpdatedResponse) Reset() {
+	*x = ProjectUpdatedResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectUpdatedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectUpdatedResponse) ProtoMessage() {}
+
+func (x *ProjectUpdatedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectUpdatedResponse.ProtoReflect.Descriptor instead.
+func (*ProjectUpdatedResponse) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP(), []int{9}
+}
+
 var File_internal_module_agent_configuration_rpc_rpc_proto protoreflect.FileDescriptor
 
 var file_internal_module_agent_configuration_rpc_rpc_proto_rawDesc = []byte{
@@ -157,34 +586,135 @@ var file_internal_module_agent_configuration_rpc_rpc_proto_rawDesc = []byte{
 	0x2f, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x73, 0x68, 0x61, 0x72, 0x65,
 	0x64, 0x2f, 0x6d, 0x6f, 0x64, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69,
-	0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x75, 0x0a, 0x14, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x12,
-	0x40, 0x0a, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65,
-	0x6e, 0x74, 0x2e, 0x6d, 0x6f, 0x64, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x41, 0x67, 0x65,
-	0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x09, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74,
-	0x61, 0x22, 0x8e, 0x01, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0d, 0x63,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e,
-	0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x63,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x09,
-	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42,
-	0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74,
-	0x49, 0x64, 0x32, 0xa6, 0x01, 0x0a, 0x12, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x8f, 0x01, 0x0a, 0x10, 0x47, 0x65,
-	0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a,
-	0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3b, 0x2e, 0x67, 0x69, 0x74,
+	0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf8, 0x01, 0x0a, 0x14, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64,
+	0x12, 0x40, 0x0a, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x6d, 0x6f, 0x64, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x09, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x4d, 0x65,
+	0x74, 0x61, 0x12, 0x6f, 0x0a, 0x15, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x5f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x3a, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x14, 0x61,
+	0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x72, 0x65, 0x66, 0x22, 0xdc, 0x01, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4f, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x24, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x12, 0x4c, 0x0a, 0x0e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x6d, 0x6f,
+	0x64, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x0d, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x22, 0x6e, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1f, 0x0a, 0x06, 0x6d, 0x6f,
+	0x64, 0x75, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72,
+	0x02, 0x10, 0x01, 0x52, 0x06, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42,
+	0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x22, 0xc7, 0x01, 0x0a, 0x14, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a,
+	0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x2c,
+	0x0a, 0x12, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x72, 0x65, 0x6a, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x12, 0x50, 0x0a, 0x06,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x67,
+	0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0xa4,
+	0x01, 0x0a, 0x20, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52,
+	0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x12, 0x5a, 0x0a, 0x06, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x67, 0x69, 0x74, 0x6c,
+	0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02, 0x08, 0x01, 0x52, 0x06, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x23, 0x0a, 0x21, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xe0, 0x01, 0x0a, 0x21, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x40, 0x0a, 0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x6d, 0x6f, 0x64, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2e, 0x41, 0x67,
+	0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x09, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x4d, 0x65,
+	0x74, 0x61, 0x12, 0x79, 0x0a, 0x15, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x5f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x3a, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0xfa,
+	0x42, 0x05, 0x8a, 0x01, 0x02, 0x10, 0x01, 0x52, 0x14, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x24, 0x0a,
+	0x22, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x3f, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0a,
+	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x20, 0x00, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x49, 0x64, 0x22, 0x18, 0x0a, 0x16, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x8b,
+	0x04, 0x0a, 0x12, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x8f, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x2e, 0x67, 0x69, 0x74,
 	0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f,
 	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x70,
 	0x63, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42, 0x60, 0x5a, 0x5e, 0x67,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3b, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0xae, 0x01, 0x0a, 0x19, 0x52, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x46, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x47, 0x2e,
+	0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0xb1, 0x01, 0x0a, 0x1a, 0x52, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x47, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x48, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x70,
+	0x70, 0x6c, 0x69, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xac, 0x01, 0x0a,
+	0x1a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x6f,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x8d, 0x01, 0x0a, 0x0e,
+	0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x3b,
+	0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3c, 0x2e, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x60, 0x5a, 0x5e, 0x67,
 	0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
 	0x2d, 0x6f, 0x72, 0x67, 0x2f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x74,
 	0x65, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d,
@@ -206,23 +736,44 @@ func file_internal_module_agent_configuration_rpc_rpc_proto_rawDescGZIP() []byte
 	return file_internal_module_agent_configuration_rpc_rpc_proto_rawDescData
 }
 
-var file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_internal_module_agent_configuration_rpc_rpc_proto_goTypes = []interface{}{
-	(*ConfigurationRequest)(nil),        // 0: gitlab.agent.agent_configuration.rpc.ConfigurationRequest
-	(*ConfigurationResponse)(nil),       // 1: gitlab.agent.agent_configuration.rpc.ConfigurationResponse
-	(*modshared.AgentMeta)(nil),         // 2: gitlab.agent.modshared.AgentMeta
-	(*agentcfg.AgentConfiguration)(nil), // 3: gitlab.agent.agentcfg.AgentConfiguration
+	(*ConfigurationRequest)(nil),               // 0: gitlab.agent.agent_configuration.rpc.ConfigurationRequest
+	(*ConfigurationResponse)(nil),              // 1: gitlab.agent.agent_configuration.rpc.ConfigurationResponse
+	(*ConfigurationError)(nil),                 // 2: gitlab.agent.agent_configuration.rpc.ConfigurationError
+	(*AppliedConfiguration)(nil),               // 3: gitlab.agent.agent_configuration.rpc.AppliedConfiguration
+	(*ReportConfigurationErrorsRequest)(nil),   // 4: gitlab.agent.agent_configuration.rpc.ReportConfigurationErrorsRequest
+	(*ReportConfigurationErrorsResponse)(nil),  // 5: gitlab.agent.agent_configuration.rpc.ReportConfigurationErrorsResponse
+	(*ReportAppliedConfigurationRequest)(nil),  // 6: gitlab.agent.agent_configuration.rpc.ReportAppliedConfigurationRequest
+	(*ReportAppliedConfigurationResponse)(nil), // 7: gitlab.agent.agent_configuration.rpc.ReportAppliedConfigurationResponse
+	(*ProjectUpdatedRequest)(nil),              // 8: gitlab.agent.agent_configuration.rpc.ProjectUpdatedRequest
+	(*ProjectUpdatedResponse)(nil),             // 9: gitlab.agent.agent_configuration.rpc.ProjectUpdatedResponse
+	(*modshared.AgentMeta)(nil),                // 10: gitlab.agent.modshared.AgentMeta
+	(*agentcfg.AgentConfiguration)(nil),        // 11: gitlab.agent.agentcfg.AgentConfiguration
+	(*modshared.AgentIdentity)(nil),            // 12: gitlab.agent.modshared.AgentIdentity
 }
 var file_internal_module_agent_configuration_rpc_rpc_proto_depIdxs = []int32{
-	2, // 0: gitlab.agent.agent_configuration.rpc.ConfigurationRequest.agent_meta:type_name -> gitlab.agent.modshared.AgentMeta
-	3, // 1: gitlab.agent.agent_configuration.rpc.ConfigurationResponse.configuration:type_name -> gitlab.agent.agentcfg.AgentConfiguration
-	0, // 2: gitlab.agent.agent_configuration.rpc.AgentConfiguration.GetConfiguration:input_type -> gitlab.agent.agent_configuration.rpc.ConfigurationRequest
-	1, // 3: gitlab.agent.agent_configuration.rpc.AgentConfiguration.GetConfiguration:output_type -> gitlab.agent.agent_configuration.rpc.ConfigurationResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	10, // 0: gitlab.agent.agent_configuration.rpc.ConfigurationRequest.agent_meta:type_name -> gitlab.agent.modshared.AgentMeta
+	3,  // 1: gitlab.agent.agent_configuration.rpc.ConfigurationRequest.applied_configuration:type_name -> gitlab.agent.agent_configuration.rpc.AppliedConfiguration
+	11, // 2: gitlab.agent.agent_configuration.rpc.ConfigurationResponse.configuration:type_name -> gitlab.agent.agentcfg.AgentConfiguration
+	12, // 3: gitlab.agent.agent_configuration.rpc.ConfigurationResponse.agent_identity:type_name -> gitlab.agent.modshared.AgentIdentity
+	2,  // 4: gitlab.agent.agent_configuration.rpc.AppliedConfiguration.errors:type_name -> gitlab.agent.agent_configuration.rpc.ConfigurationError
+	2,  // 5: gitlab.agent.agent_configuration.rpc.ReportConfigurationErrorsRequest.errors:type_name -> gitlab.agent.agent_configuration.rpc.ConfigurationError
+	10, // 6: gitlab.agent.agent_configuration.rpc.ReportAppliedConfigurationRequest.agent_meta:type_name -> gitlab.agent.modshared.AgentMeta
+	3,  // 7: gitlab.agent.agent_configuration.rpc.ReportAppliedConfigurationRequest.applied_configuration:type_name -> gitlab.agent.agent_configuration.rpc.AppliedConfiguration
+	0,  // 8: gitlab.agent.agent_configuration.rpc.AgentConfiguration.GetConfiguration:input_type -> gitlab.agent.agent_configuration.rpc.ConfigurationRequest
+	4,  // 9: gitlab.agent.agent_configuration.rpc.AgentConfiguration.ReportConfigurationErrors:input_type -> gitlab.agent.agent_configuration.rpc.ReportConfigurationErrorsRequest
+	6,  // 10: gitlab.agent.agent_configuration.rpc.AgentConfiguration.ReportAppliedConfiguration:input_type -> gitlab.agent.agent_configuration.rpc.ReportAppliedConfigurationRequest
+	8,  // 11: gitlab.agent.agent_configuration.rpc.ConfigurationNotifications.ProjectUpdated:input_type -> gitlab.agent.agent_configuration.rpc.ProjectUpdatedRequest
+	1,  // 12: gitlab.agent.agent_configuration.rpc.AgentConfiguration.GetConfiguration:output_type -> gitlab.agent.agent_configuration.rpc.ConfigurationResponse
+	5,  // 13: gitlab.agent.agent_configuration.rpc.AgentConfiguration.ReportConfigurationErrors:output_type -> gitlab.agent.agent_configuration.rpc.ReportConfigurationErrorsResponse
+	7,  // 14: gitlab.agent.agent_configuration.rpc.AgentConfiguration.ReportAppliedConfiguration:output_type -> gitlab.agent.agent_configuration.rpc.ReportAppliedConfigurationResponse
+	9,  // 15: gitlab.agent.agent_configuration.rpc.ConfigurationNotifications.ProjectUpdated:output_type -> gitlab.agent.agent_configuration.rpc.ProjectUpdatedResponse
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_internal_module_agent_configuration_rpc_rpc_proto_init() }
@@ -255,6 +806,102 @@ func file_internal_module_agent_configuration_rpc_rpc_proto_init() {
 				return nil
 			}
 		}
+		file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigurationError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppliedConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportConfigurationErrorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportConfigurationErrorsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportAppliedConfigurationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportAppliedConfigurationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectUpdatedRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_configuration_rpc_rpc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectUpdatedResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -262,9 +909,9 @@ func file_internal_module_agent_configuration_rpc_rpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_module_agent_configuration_rpc_rpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   10,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_internal_module_agent_configuration_rpc_rpc_proto_goTypes,
 		DependencyIndexes: file_internal_module_agent_configuration_rpc_rpc_proto_depIdxs,
@@ -289,6 +936,8 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type AgentConfigurationClient interface {
 	GetConfiguration(ctx context.Context, in *ConfigurationRequest, opts ...grpc.CallOption) (AgentConfiguration_GetConfigurationClient, error)
+	ReportConfigurationErrors(ctx context.Context, in *ReportConfigurationErrorsRequest, opts ...grpc.CallOption) (*ReportConfigurationErrorsResponse, error)
+	ReportAppliedConfiguration(ctx context.Context, in *ReportAppliedConfigurationRequest, opts ...grpc.CallOption) (*ReportAppliedConfigurationResponse, error)
 }
 
 type agentConfigurationClient struct {
@@ -331,9 +980,29 @@ func (x *agentConfigurationGetConfigurationClient) Recv() (*ConfigurationRespons
 	return m, nil
 }
 
+func (c *agentConfigurationClient) ReportConfigurationErrors(ctx context.Context, in *ReportConfigurationErrorsRequest, opts ...grpc.CallOption) (*ReportConfigurationErrorsResponse, error) {
+	out := new(ReportConfigurationErrorsResponse)
+	err := c.cc.Invoke(ctx, "/gitlab.agent.agent_configuration.rpc.AgentConfiguration/ReportConfigurationErrors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentConfigurationClient) ReportAppliedConfiguration(ctx context.Context, in *ReportAppliedConfigurationRequest, opts ...grpc.CallOption) (*ReportAppliedConfigurationResponse, error) {
+	out := new(ReportAppliedConfigurationResponse)
+	err := c.cc.Invoke(ctx, "/gitlab.agent.agent_configuration.rpc.AgentConfiguration/ReportAppliedConfiguration", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentConfigurationServer is the server API for AgentConfiguration service.
 type AgentConfigurationServer interface {
 	GetConfiguration(*ConfigurationRequest, AgentConfiguration_GetConfigurationServer) error
+	ReportConfigurationErrors(context.Context, *ReportConfigurationErrorsRequest) (*ReportConfigurationErrorsResponse, error)
+	ReportAppliedConfiguration(context.Context, *ReportAppliedConfigurationRequest) (*ReportAppliedConfigurationResponse, error)
 }
 
 // UnimplementedAgentConfigurationServer can be embedded to have forward compatible implementations.
@@ -343,6 +1012,12 @@ type UnimplementedAgentConfigurationServer struct {
 func (*UnimplementedAgentConfigurationServer) GetConfiguration(*ConfigurationRequest, AgentConfiguration_GetConfigurationServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetConfiguration not implemented")
 }
+func (*UnimplementedAgentConfigurationServer) ReportConfigurationErrors(context.Context, *ReportConfigurationErrorsRequest) (*ReportConfigurationErrorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportConfigurationErrors not implemented")
+}
+func (*UnimplementedAgentConfigurationServer) ReportAppliedConfiguration(context.Context, *ReportAppliedConfigurationRequest) (*ReportAppliedConfigurationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportAppliedConfiguration not implemented")
+}
 
 func RegisterAgentConfigurationServer(s *grpc.Server, srv AgentConfigurationServer) {
 	s.RegisterService(&_AgentConfiguration_serviceDesc, srv)
@@ -369,10 +1044,55 @@ func (x *agentConfigurationGetConfigurationServer) Send(m *ConfigurationResponse
 	return x.ServerStream.SendMsg(m)
 }
 
+func _AgentConfiguration_ReportConfigurationErrors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportConfigurationErrorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentConfigurationServer).ReportConfigurationErrors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitlab.agent.agent_configuration.rpc.AgentConfiguration/ReportConfigurationErrors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentConfigurationServer).ReportConfigurationErrors(ctx, req.(*ReportConfigurationErrorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentConfiguration_ReportAppliedConfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportAppliedConfigurationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentConfigurationServer).ReportAppliedConfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitlab.agent.agent_configuration.rpc.AgentConfiguration/ReportAppliedConfiguration",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentConfigurationServer).ReportAppliedConfiguration(ctx, req.(*ReportAppliedConfigurationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _AgentConfiguration_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "gitlab.agent.agent_configuration.rpc.AgentConfiguration",
 	HandlerType: (*AgentConfigurationServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReportConfigurationErrors",
+			Handler:    _AgentConfiguration_ReportConfigurationErrors_Handler,
+		},
+		{
+			MethodName: "ReportAppliedConfiguration",
+			Handler:    _AgentConfiguration_ReportAppliedConfiguration_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "GetConfiguration",
@@ -382,3 +1102,75 @@ var _AgentConfiguration_serviceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "internal/module/agent_configuration/rpc/rpc.proto",
 }
+
+// ConfigurationNotificationsClient is the client API for ConfigurationNotifications service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ConfigurationNotificationsClient interface {
+	ProjectUpdated(ctx context.Context, in *ProjectUpdatedRequest, opts ...grpc.CallOption) (*ProjectUpdatedResponse, error)
+}
+
+type configurationNotificationsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConfigurationNotificationsClient(cc grpc.ClientConnInterface) ConfigurationNotificationsClient {
+	return &configurationNotificationsClient{cc}
+}
+
+func (c *configurationNotificationsClient) ProjectUpdated(ctx context.Context, in *ProjectUpdatedRequest, opts ...grpc.CallOption) (*ProjectUpdatedResponse, error) {
+	out := new(ProjectUpdatedResponse)
+	err := c.cc.Invoke(ctx, "/gitlab.agent.agent_configuration.rpc.ConfigurationNotifications/ProjectUpdated", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfigurationNotificationsServer is the server API for ConfigurationNotifications service.
+type ConfigurationNotificationsServer interface {
+	ProjectUpdated(context.Context, *ProjectUpdatedRequest) (*ProjectUpdatedResponse, error)
+}
+
+// UnimplementedConfigurationNotificationsServer can be embedded to have forward compatible implementations.
+type UnimplementedConfigurationNotificationsServer struct {
+}
+
+func (*UnimplementedConfigurationNotificationsServer) ProjectUpdated(context.Context, *ProjectUpdatedRequest) (*ProjectUpdatedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProjectUpdated not implemented")
+}
+
+func RegisterConfigurationNotificationsServer(s *grpc.Server, srv ConfigurationNotificationsServer) {
+	s.RegisterService(&_ConfigurationNotifications_serviceDesc, srv)
+}
+
+func _ConfigurationNotifications_ProjectUpdated_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProjectUpdatedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigurationNotificationsServer).ProjectUpdated(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gitlab.agent.agent_configuration.rpc.ConfigurationNotifications/ProjectUpdated",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigurationNotificationsServer).ProjectUpdated(ctx, req.(*ProjectUpdatedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ConfigurationNotifications_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gitlab.agent.agent_configuration.rpc.ConfigurationNotifications",
+	HandlerType: (*ConfigurationNotificationsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ProjectUpdated",
+			Handler:    _ConfigurationNotifications_ProjectUpdated_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "internal/module/agent_configuration/rpc/rpc.proto",
+}
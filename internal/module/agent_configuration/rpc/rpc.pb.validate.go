@@ -56,6 +56,18 @@ func (m *ConfigurationRequest) Validate() error {
 		}
 	}
 
+	if v, ok := interface{}(m.GetAppliedConfiguration()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConfigurationRequestValidationError{
+				field:  "AppliedConfiguration",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for Ref
+
 	return nil
 }
 
@@ -140,6 +152,16 @@ func (m *ConfigurationResponse) Validate() error {
 		}
 	}
 
+	if v, ok := interface{}(m.GetAgentIdentity()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConfigurationResponseValidationError{
+				field:  "AgentIdentity",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -198,3 +220,643 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = ConfigurationResponseValidationError{}
+
+// Validate checks the field values on ConfigurationError with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *ConfigurationError) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetModule()) < 1 {
+		return ConfigurationErrorValidationError{
+			field:  "Module",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetMessage()) < 1 {
+		return ConfigurationErrorValidationError{
+			field:  "Message",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	// no validation rules for Local
+
+	return nil
+}
+
+// ConfigurationErrorValidationError is the validation error returned by
+// ConfigurationError.Validate if the designated constraints aren't met.
+type ConfigurationErrorValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConfigurationErrorValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConfigurationErrorValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConfigurationErrorValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConfigurationErrorValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConfigurationErrorValidationError) ErrorName() string {
+	return "ConfigurationErrorValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ConfigurationErrorValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConfigurationError.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConfigurationErrorValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConfigurationErrorValidationError{}
+
+// Validate checks the field values on AppliedConfiguration with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *AppliedConfiguration) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for CommitId
+
+	// no validation rules for Hash
+
+	// no validation rules for RejectedCommitId
+
+	for idx, item := range m.GetErrors() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AppliedConfigurationValidationError{
+					field:  fmt.Sprintf("Errors[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// AppliedConfigurationValidationError is the validation error returned by
+// AppliedConfiguration.Validate if the designated constraints aren't met.
+type AppliedConfigurationValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AppliedConfigurationValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AppliedConfigurationValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AppliedConfigurationValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AppliedConfigurationValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AppliedConfigurationValidationError) ErrorName() string {
+	return "AppliedConfigurationValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AppliedConfigurationValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAppliedConfiguration.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AppliedConfigurationValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AppliedConfigurationValidationError{}
+
+// Validate checks the field values on ReportConfigurationErrorsRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, an error is returned.
+func (m *ReportConfigurationErrorsRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetCommitId()) < 1 {
+		return ReportConfigurationErrorsRequestValidationError{
+			field:  "CommitId",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	if len(m.GetErrors()) < 1 {
+		return ReportConfigurationErrorsRequestValidationError{
+			field:  "Errors",
+			reason: "value must contain at least 1 item(s)",
+		}
+	}
+
+	for idx, item := range m.GetErrors() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ReportConfigurationErrorsRequestValidationError{
+					field:  fmt.Sprintf("Errors[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// ReportConfigurationErrorsRequestValidationError is the validation error
+// returned by ReportConfigurationErrorsRequest.Validate if the designated
+// constraints aren't met.
+type ReportConfigurationErrorsRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ReportConfigurationErrorsRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ReportConfigurationErrorsRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ReportConfigurationErrorsRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ReportConfigurationErrorsRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ReportConfigurationErrorsRequestValidationError) ErrorName() string {
+	return "ReportConfigurationErrorsRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ReportConfigurationErrorsRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sReportConfigurationErrorsRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ReportConfigurationErrorsRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ReportConfigurationErrorsRequestValidationError{}
+
+// Validate checks the field values on ReportConfigurationErrorsResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, an error is returned.
+func (m *ReportConfigurationErrorsResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// ReportConfigurationErrorsResponseValidationError is the validation error
+// returned by ReportConfigurationErrorsResponse.Validate if the designated
+// constraints aren't met.
+type ReportConfigurationErrorsResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ReportConfigurationErrorsResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ReportConfigurationErrorsResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ReportConfigurationErrorsResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ReportConfigurationErrorsResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ReportConfigurationErrorsResponseValidationError) ErrorName() string {
+	return "ReportConfigurationErrorsResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ReportConfigurationErrorsResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sReportConfigurationErrorsResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ReportConfigurationErrorsResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ReportConfigurationErrorsResponseValidationError{}
+
+// Validate checks the field values on ReportAppliedConfigurationRequest with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, an error is returned.
+func (m *ReportAppliedConfigurationRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if v, ok := interface{}(m.GetAgentMeta()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ReportAppliedConfigurationRequestValidationError{
+				field:  "AgentMeta",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if m.GetAppliedConfiguration() == nil {
+		return ReportAppliedConfigurationRequestValidationError{
+			field:  "AppliedConfiguration",
+			reason: "value is required",
+		}
+	}
+
+	if v, ok := interface{}(m.GetAppliedConfiguration()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ReportAppliedConfigurationRequestValidationError{
+				field:  "AppliedConfiguration",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// ReportAppliedConfigurationRequestValidationError is the validation error
+// returned by ReportAppliedConfigurationRequest.Validate if the designated
+// constraints aren't met.
+type ReportAppliedConfigurationRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ReportAppliedConfigurationRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ReportAppliedConfigurationRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ReportAppliedConfigurationRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ReportAppliedConfigurationRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ReportAppliedConfigurationRequestValidationError) ErrorName() string {
+	return "ReportAppliedConfigurationRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ReportAppliedConfigurationRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sReportAppliedConfigurationRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ReportAppliedConfigurationRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ReportAppliedConfigurationRequestValidationError{}
+
+// Validate checks the field values on ReportAppliedConfigurationResponse with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, an error is returned.
+func (m *ReportAppliedConfigurationResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// ReportAppliedConfigurationResponseValidationError is the validation error
+// returned by ReportAppliedConfigurationResponse.Validate if the designated
+// constraints aren't met.
+type ReportAppliedConfigurationResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ReportAppliedConfigurationResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ReportAppliedConfigurationResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ReportAppliedConfigurationResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ReportAppliedConfigurationResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ReportAppliedConfigurationResponseValidationError) ErrorName() string {
+	return "ReportAppliedConfigurationResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ReportAppliedConfigurationResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sReportAppliedConfigurationResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ReportAppliedConfigurationResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ReportAppliedConfigurationResponseValidationError{}
+
+// Validate checks the field values on ProjectUpdatedRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *ProjectUpdatedRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if m.GetProjectId() <= 0 {
+		return ProjectUpdatedRequestValidationError{
+			field:  "ProjectId",
+			reason: "value must be greater than 0",
+		}
+	}
+
+	return nil
+}
+
+// ProjectUpdatedRequestValidationError is the validation error returned by
+// ProjectUpdatedRequest.Validate if the designated constraints aren't met.
+type ProjectUpdatedRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectUpdatedRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectUpdatedRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectUpdatedRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectUpdatedRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectUpdatedRequestValidationError) ErrorName() string {
+	return "ProjectUpdatedRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectUpdatedRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectUpdatedRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectUpdatedRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectUpdatedRequestValidationError{}
+
+// Validate checks the field values on ProjectUpdatedResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *ProjectUpdatedResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// ProjectUpdatedResponseValidationError is the validation error returned by
+// ProjectUpdatedResponse.Validate if the designated constraints aren't met.
+type ProjectUpdatedResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectUpdatedResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectUpdatedResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectUpdatedResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectUpdatedResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectUpdatedResponseValidationError) ErrorName() string {
+	return "ProjectUpdatedResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectUpdatedResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectUpdatedResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectUpdatedResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectUpdatedResponseValidationError{}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab"
+)
+
+const (
+	configurationErrorsApiPath = "/api/v4/internal/kubernetes/agent_configuration_errors"
+)
+
+// configurationErrors is the payload of a configuration errors report.
+// GitLab stores the errors for the agent, identified by the token, and the commit.
+// CommitId is empty if the errors are not in a particular commit, e.g. if the configured ref does not exist.
+type configurationErrors struct {
+	CommitId string               `json:"commit_id"`
+	Errors   []configurationError `json:"errors"`
+}
+
+type configurationError struct {
+	// Module is the name of the agentk module that rejected the configuration. Empty for errors, found by kas.
+	Module  string `json:"module,omitempty"`
+	Message string `json:"message"`
+	// Local is true if the error is caused by the local configuration overrides of agentk, not by the commit.
+	Local bool `json:"local,omitempty"`
+}
+
+// reportConfigurationErrors sends errors in the agent's configuration at the commit to GitLab so that users can see them.
+func reportConfigurationErrors(ctx context.Context, client gitlab.ClientInterface, agentToken api.AgentToken, commitId string, errs []configurationError) error {
+	return client.DoJSON(ctx, http.MethodPost, configurationErrorsApiPath, nil, agentToken, &configurationErrors{
+		CommitId: commitId,
+		Errors:   errs,
+	}, nil)
+}
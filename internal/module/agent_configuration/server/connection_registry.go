@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_tracker"
+	"google.golang.org/protobuf/proto"
+)
+
+// connectionRegistry registers GetConfiguration connections with the agent tracker and keeps track of them so that
+// the configuration, reported by agentk, can be stored with the existing connection.
+type connectionRegistry struct {
+	registerer agent_tracker.Registerer
+	// mu is held while calling registerer so that an update cannot re-register a connection after it has been unregistered.
+	mu sync.Mutex
+	// connections maps agent id to connection id to the information about the connection.
+	connections map[int64]map[int64]*agent_tracker.ConnectedAgentInfo
+}
+
+func newConnectionRegistry(registerer agent_tracker.Registerer) *connectionRegistry {
+	return &connectionRegistry{
+		registerer:  registerer,
+		connections: make(map[int64]map[int64]*agent_tracker.ConnectedAgentInfo),
+	}
+}
+
+func (r *connectionRegistry) register(ctx context.Context, info *agent_tracker.ConnectedAgentInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conns := r.connections[info.AgentId]
+	if conns == nil {
+		conns = make(map[int64]*agent_tracker.ConnectedAgentInfo)
+		r.connections[info.AgentId] = conns
+	}
+	conns[info.ConnectionId] = info
+	r.registerer.RegisterConnection(ctx, info)
+}
+
+func (r *connectionRegistry) unregister(ctx context.Context, info *agent_tracker.ConnectedAgentInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	conns := r.connections[info.AgentId]
+	delete(conns, info.ConnectionId)
+	if len(conns) == 0 {
+		delete(r.connections, info.AgentId)
+	}
+	r.registerer.UnregisterConnection(ctx, info)
+}
+
+// updateAppliedConfiguration stores the applied configuration with the connections of the agent's Pod, identified
+// by its namespace and name. It returns the number of updated connections.
+func (r *connectionRegistry) updateAppliedConfiguration(ctx context.Context, agentId int64, podNamespace, podName string, applied *agent_tracker.AppliedConfiguration) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	updated := 0
+	for connectionId, info := range r.connections[agentId] {
+		meta := info.AgentMeta
+		if meta.GetPodNamespace() != podNamespace || meta.GetPodName() != podName {
+			continue
+		}
+		// Copy to not modify information that the tracker may be using concurrently.
+		info = proto.Clone(info).(*agent_tracker.ConnectedAgentInfo)
+		info.AppliedConfiguration = applied
+		r.connections[agentId][connectionId] = info
+		r.registerer.RegisterConnection(ctx, info)
+		updated++
+	}
+	return updated
+}
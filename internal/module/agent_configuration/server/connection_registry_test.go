@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_tracker"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_agent_tracker"
+)
+
+func TestUpdateAppliedConfigurationOnlyUpdatesConnectionsOfPod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	agentTracker := mock_agent_tracker.NewMockTracker(ctrl)
+	r := newConnectionRegistry(agentTracker)
+	pod1 := &agent_tracker.ConnectedAgentInfo{
+		AgentMeta: &modshared.AgentMeta{
+			PodNamespace: "ns",
+			PodName:      "pod1",
+		},
+		ConnectionId: 1,
+		AgentId:      10,
+	}
+	pod2 := &agent_tracker.ConnectedAgentInfo{
+		AgentMeta: &modshared.AgentMeta{
+			PodNamespace: "ns",
+			PodName:      "pod2",
+		},
+		ConnectionId: 2,
+		AgentId:      10,
+	}
+	applied := &agent_tracker.AppliedConfiguration{
+		CommitId: "c1",
+	}
+	gomock.InOrder(
+		agentTracker.EXPECT().RegisterConnection(gomock.Any(), pod1),
+		agentTracker.EXPECT().RegisterConnection(gomock.Any(), pod2),
+		agentTracker.EXPECT().
+			RegisterConnection(gomock.Any(), gomock.Any()).
+			Do(func(ctx context.Context, info *agent_tracker.ConnectedAgentInfo) {
+				assert.EqualValues(t, 1, info.ConnectionId)
+				assert.Same(t, applied, info.AppliedConfiguration)
+			}),
+		agentTracker.EXPECT().UnregisterConnection(gomock.Any(), pod1),
+	)
+	ctx := context.Background()
+	r.register(ctx, pod1)
+	r.register(ctx, pod2)
+	assert.Equal(t, 1, r.updateAppliedConfiguration(ctx, 10, "ns", "pod1", applied))
+	assert.Nil(t, pod1.AppliedConfiguration)                                     // registered information is not modified
+	assert.Zero(t, r.updateAppliedConfiguration(ctx, 20, "ns", "pod2", applied)) // another agent
+	r.unregister(ctx, pod1)
+	assert.Zero(t, r.updateAppliedConfiguration(ctx, 10, "ns", "pod1", applied)) // unregistered
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_tracker"
@@ -14,14 +15,20 @@ type Factory struct {
 func (f *Factory) New(config *modserver.Config) (modserver.Module, error) {
 	agent := config.Config.Agent
 	m := &module{
-		api:                          config.Api,
-		gitaly:                       config.Gitaly,
-		agentRegisterer:              f.AgentRegisterer,
+		api:          config.Api,
+		gitaly:       config.Gitaly,
+		gitLabClient: config.GitLabClient,
+		projectInfoClient: &projectinfo.Client{
+			GitLabClient: config.GitLabClient, // includes are only resolved for new commits, no need to cache project info
+		},
+		connections:                  newConnectionRegistry(f.AgentRegisterer),
+		notifier:                     newProjectNotifier(),
 		maxConfigurationFileSize:     int64(agent.Configuration.MaxConfigurationFileSize),
 		agentConfigurationPollPeriod: agent.Configuration.PollPeriod.AsDuration(),
 		maxConnectionAge:             agent.Listen.MaxConnectionAge.AsDuration(),
 	}
 	rpc.RegisterAgentConfigurationServer(config.AgentServer, m)
+	rpc.RegisterConfigurationNotificationsServer(config.ApiServer, m)
 	return m, nil
 }
 
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"gitlab.com/gitlab-org/gitaly/proto/go/gitalypb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxIncludedFiles is the maximum number of files that can be included, directly or indirectly, into
+	// the agent's configuration file.
+	maxIncludedFiles = 20
+)
+
+// configRepository is a commit of a repository configuration files are loaded from.
+type configRepository struct {
+	// project is the path of the project. Empty for the agent's configuration project.
+	project    string
+	commitId   string
+	gitalyInfo *api.GitalyInfo
+	repository *gitalypb.Repository
+}
+
+// configFileLocation identifies a configuration file.
+type configFileLocation struct {
+	project  string
+	commitId string
+	path     string
+}
+
+func (l configFileLocation) String() string {
+	if l.project == "" {
+		return l.path
+	}
+	return l.project + ":" + l.path
+}
+
+type projectRef struct {
+	project string
+	ref     string
+}
+
+var (
+	// errFileTooBig is returned by configSource.fetchFile if the file exceeds the size limit.
+	errFileTooBig = errors.New("file is too big")
+	// errSkipInclude is returned by configSource.projectRepository if files from the project cannot be included.
+	// Such includes are skipped.
+	errSkipInclude = errors.New("include skipped")
+)
+
+// configSource reads configuration files from repositories.
+type configSource interface {
+	// fetchFile returns the contents of the file at the commit of the repository. It returns nil if the file does not exist.
+	fetchFile(ctx context.Context, repo configRepository, filename string, sizeLimit int64) ([]byte, error)
+	// projectRepository returns the commit that the ref of the project points to.
+	projectRepository(ctx context.Context, project, ref string) (configRepository, error)
+}
+
+// includeResolver loads a configuration file and merges the files, included into it, recursively.
+// Files, included more than once, are only merged the first time.
+type includeResolver struct {
+	ctx    context.Context
+	source configSource
+	// sizeBudget is how many more bytes the loaded files may have in total.
+	sizeBudget int64
+	// stack holds the files that are being loaded, to detect cycles.
+	stack []configFileLocation
+	// loaded holds the files that have been loaded.
+	loaded       map[configFileLocation]struct{}
+	repositories map[projectRef]configRepository
+}
+
+func newIncludeResolver(ctx context.Context, source configSource, maxSize int64) *includeResolver {
+	return &includeResolver{
+		ctx:          ctx,
+		source:       source,
+		sizeBudget:   maxSize,
+		loaded:       make(map[configFileLocation]struct{}),
+		repositories: make(map[projectRef]configRepository),
+	}
+}
+
+// load fetches and parses the file and merges the included files into it.
+// Values from the file take precedence over included values.
+func (r *includeResolver) load(repo configRepository, filename string) (*agentcfg.ConfigurationFile, error) {
+	loc := configFileLocation{
+		project:  repo.project,
+		commitId: repo.commitId,
+		path:     filename,
+	}
+	for i, l := range r.stack {
+		if l == loc {
+			cycle := make([]string, 0, len(r.stack)-i+1)
+			for _, s := range r.stack[i:] {
+				cycle = append(cycle, s.String())
+			}
+			cycle = append(cycle, loc.String())
+			return nil, errz.NewUserErrorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	if _, ok := r.loaded[loc]; ok {
+		return &agentcfg.ConfigurationFile{}, nil
+	}
+	if len(r.loaded) > maxIncludedFiles { // the agent's configuration file is not counted
+		return nil, errz.NewUserErrorf("too many included files, at most %d files can be included", maxIncludedFiles)
+	}
+	r.loaded[loc] = struct{}{}
+	configFile, err := r.fetch(repo, loc)
+	if err != nil {
+		return nil, err
+	}
+	includes := configFile.Include
+	configFile.Include = nil
+	r.stack = append(r.stack, loc)
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+	result := &agentcfg.ConfigurationFile{}
+	for _, include := range includes {
+		includeRepo, includeFilename, err := r.resolveInclude(repo, filename, include)
+		if err != nil {
+			if errors.Is(err, errSkipInclude) {
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", loc, err) // wrap
+		}
+		included, err := r.load(includeRepo, includeFilename)
+		if err != nil {
+			return nil, err // don't wrap
+		}
+		proto.Merge(result, included)
+	}
+	proto.Merge(result, configFile)
+	return result, nil
+}
+
+func (r *includeResolver) fetch(repo configRepository, loc configFileLocation) (*agentcfg.ConfigurationFile, error) {
+	configYAML, err := r.source.fetchFile(r.ctx, repo, loc.path, r.sizeBudget)
+	if err != nil {
+		if errors.Is(err, errFileTooBig) {
+			return nil, errz.NewUserErrorf("configuration is too big, %s exceeds the size limit", loc)
+		}
+		return nil, err // don't wrap
+	}
+	if configYAML == nil {
+		return nil, errz.NewUserErrorf("configuration file not found: %s", loc)
+	}
+	r.sizeBudget -= int64(len(configYAML))
+	configFile, err := agent_configuration.ParseConfigurationFile(configYAML)
+	if err != nil {
+		return nil, errz.NewUserErrorWithCausef(err, "failed to parse %s", loc)
+	}
+	return configFile, nil
+}
+
+// resolveInclude returns the repository and the path of the included file.
+func (r *includeResolver) resolveInclude(repo configRepository, filename string, include *agentcfg.IncludeCF) (configRepository, string, error) {
+	switch {
+	case include.Local != "" && include.Project == "" && include.File == "" && include.Ref == "":
+		includeFilename := include.Local
+		if !strings.HasPrefix(includeFilename, "/") {
+			includeFilename = path.Join(path.Dir(filename), includeFilename)
+		}
+		includeFilename, ok := cleanRepositoryPath(includeFilename)
+		if !ok {
+			return configRepository{}, "", errz.NewUserErrorf("include: invalid path %s", include.Local)
+		}
+		return repo, includeFilename, nil
+	case include.Local == "" && include.Project != "" && include.File != "":
+		includeFilename, ok := cleanRepositoryPath(include.File)
+		if !ok {
+			return configRepository{}, "", errz.NewUserErrorf("include: invalid path %s", include.File)
+		}
+		includeRepo, err := r.projectRepository(include.Project, include.Ref)
+		if err != nil {
+			return configRepository{}, "", err
+		}
+		return includeRepo, includeFilename, nil
+	default:
+		return configRepository{}, "", errz.NewUserError("include: either local, or project and file must be set")
+	}
+}
+
+// projectRepository returns the commit that the ref of the project points to.
+func (r *includeResolver) projectRepository(project, ref string) (configRepository, error) {
+	key := projectRef{
+		project: project,
+		ref:     ref,
+	}
+	if repo, ok := r.repositories[key]; ok {
+		return repo, nil
+	}
+	repo, err := r.source.projectRepository(r.ctx, project, ref)
+	if err != nil {
+		return configRepository{}, err // don't wrap
+	}
+	r.repositories[key] = repo
+	return repo, nil
+}
+
+// gitalyConfigSource reads configuration files from Gitaly.
+type gitalyConfigSource struct {
+	gitaly            gitaly.PoolInterface
+	projectInfoClient *projectinfo.Client
+	agentToken        api.AgentToken
+}
+
+func (s *gitalyConfigSource) fetchFile(ctx context.Context, repo configRepository, filename string, sizeLimit int64) ([]byte, error) {
+	pf, err := s.gitaly.PathFetcher(ctx, repo.gitalyInfo)
+	if err != nil {
+		return nil, fmt.Errorf("PathFetcher: %w", err) // wrap
+	}
+	data, err := pf.FetchFile(ctx, repo.repository, []byte(repo.commitId), []byte(filename), sizeLimit)
+	if err != nil {
+		if isFileTooBig(err) {
+			return nil, errFileTooBig
+		}
+		return nil, fmt.Errorf("fetch agent configuration: %w", err) // wrap
+	}
+	return data, nil
+}
+
+func (s *gitalyConfigSource) projectRepository(ctx context.Context, project, ref string) (configRepository, error) {
+	projectInfo, err := s.projectInfoClient.GetProjectInfo(ctx, s.agentToken, project)
+	if err != nil {
+		if gitlab.IsForbidden(err) || isNotFound(err) {
+			return configRepository{}, errz.NewUserErrorf("include: project %s not found or the agent does not have access to it", project)
+		}
+		return configRepository{}, fmt.Errorf("GetProjectInfo: %w", err) // wrap
+	}
+	p, err := s.gitaly.Poller(ctx, &projectInfo.GitalyInfo)
+	if err != nil {
+		return configRepository{}, fmt.Errorf("Poller: %w", err) // wrap
+	}
+	info, err := p.Poll(ctx, &projectInfo.Repository, "", ref)
+	if err != nil {
+		var notFound *gitaly.RefNotFoundError
+		if errors.As(err, &notFound) {
+			return configRepository{}, errz.NewUserErrorf("include: project %s: ref %s not found", project, ref)
+		}
+		return configRepository{}, fmt.Errorf("include: project %s: %w", project, err) // wrap
+	}
+	return configRepository{
+		project:    project,
+		commitId:   info.CommitId,
+		gitalyInfo: &projectInfo.GitalyInfo,
+		repository: &projectInfo.Repository,
+	}, nil
+}
+
+// cleanRepositoryPath returns the path relative to the root of the repository.
+// Returns false if the path is outside of the repository.
+func cleanRepositoryPath(p string) (string, bool) {
+	cleaned := path.Clean(strings.TrimPrefix(p, "/"))
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false
+	}
+	return cleaned, true
+}
+
+func isNotFound(err error) bool {
+	var e *gitlab.ClientError
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// isFileTooBig returns true if Gitaly refused to return a file because it exceeds the size limit.
+func isFileTooBig(err error) bool {
+	var s interface {
+		GRPCStatus() *status.Status
+	}
+	return errors.As(err, &s) && s.GRPCStatus().Code() == codes.FailedPrecondition
+}
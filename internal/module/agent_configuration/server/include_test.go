@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_gitlab"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_internalgitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"gitlab.com/gitlab-org/gitaly/proto/go/gitalypb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const (
+	includeConfigFile = ".gitlab/agents/agent1/config.yaml"
+	sharedProject     = "group/shared"
+	sharedCommitId    = "f1d2d2f924e986ac86fdf7b36c94bcdf32beec15"
+)
+
+func TestIncludeLocal(t *testing.T) {
+	r := setupIncludeResolver(t, map[string]string{
+		includeConfigFile: `
+include:
+- local: ../../base/config.yaml
+gitops:
+  manifest_projects:
+  - id: app
+observability:
+  logging:
+    level: debug
+`,
+		".gitlab/base/config.yaml": `
+include:
+- local: /.gitlab/base/observability.yaml
+gitops:
+  manifest_projects:
+  - id: platform
+`,
+		".gitlab/base/observability.yaml": `
+observability:
+  logging:
+    level: warn
+`,
+	}, nil)
+	configFile, err := r.load(agentConfigRepository(), includeConfigFile)
+	require.NoError(t, err)
+	expected := &agentcfg.ConfigurationFile{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id: "platform",
+				},
+				{
+					Id: "app",
+				},
+			},
+		},
+		Observability: &agentcfg.ObservabilityCF{
+			Logging: &agentcfg.LoggingCF{
+				Level: agentcfg.LoggingLevelEnum_debug, // the including file takes precedence
+			},
+		},
+	}
+	assert.Empty(t, cmp.Diff(expected, configFile, protocmp.Transform()))
+}
+
+func TestIncludeFileOnce(t *testing.T) {
+	r := setupIncludeResolver(t, map[string]string{
+		includeConfigFile: `
+include:
+- local: a.yaml
+- local: b.yaml
+`,
+		".gitlab/agents/agent1/a.yaml": `
+include:
+- local: common.yaml
+`,
+		".gitlab/agents/agent1/b.yaml": `
+include:
+- local: common.yaml
+`,
+		".gitlab/agents/agent1/common.yaml": `
+gitops:
+  manifest_projects:
+  - id: common
+`,
+	}, nil)
+	configFile, err := r.load(agentConfigRepository(), includeConfigFile)
+	require.NoError(t, err)
+	assert.Len(t, configFile.Gitops.ManifestProjects, 1)
+}
+
+func TestIncludeProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	gitLabClient := mock_gitlab.NewMockClientInterface(ctrl)
+	projectInfo := &projectinfo.Response{
+		ProjectId: 234,
+		GitalyRepository: gitlab.GitalyRepository{
+			GlProjectPath: sharedProject,
+		},
+	}
+	gitLabClient.EXPECT().
+		DoJSON(gomock.Any(), http.MethodGet, projectinfo.ApiPath, gomock.Any(), mock_gitlab.AgentkToken, nil, gomock.Any()).
+		Do(func(ctx context.Context, method, path string, query interface{}, agentToken api.AgentToken, body, response interface{}) {
+			*response.(*projectinfo.Response) = *projectInfo
+		})
+	r := setupIncludeResolver(t, map[string]string{
+		includeConfigFile: `
+include:
+- project: group/shared
+  file: agents/base.yaml
+  ref: stable
+`,
+		sharedProject + ":agents/base.yaml": `
+include:
+- local: common.yaml
+`,
+		sharedProject + ":agents/common.yaml": `
+gitops:
+  manifest_projects:
+  - id: shared
+`,
+	}, gitLabClient)
+	p := mock_internalgitaly.NewMockPollerInterface(ctrl)
+	r.source.(*gitalyConfigSource).gitaly.(*mock_internalgitaly.MockPoolInterface).EXPECT().
+		Poller(gomock.Any(), gomock.Any()).
+		Return(p, nil)
+	p.EXPECT().
+		Poll(gomock.Any(), gomock.Any(), "", "stable").
+		Return(&gitaly.PollInfo{
+			UpdateAvailable: true,
+			CommitId:        sharedCommitId,
+		}, nil)
+	configFile, err := r.load(agentConfigRepository(), includeConfigFile)
+	require.NoError(t, err)
+	require.Len(t, configFile.Gitops.ManifestProjects, 1)
+	assert.Equal(t, "shared", configFile.Gitops.ManifestProjects[0].Id)
+}
+
+func TestIncludeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		maxSize     int64
+		expectedErr string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				includeConfigFile: `
+include:
+- local: a.yaml
+`,
+				".gitlab/agents/agent1/a.yaml": `
+include:
+- local: b.yaml
+`,
+				".gitlab/agents/agent1/b.yaml": `
+include:
+- local: a.yaml
+`,
+			},
+			expectedErr: "include cycle: .gitlab/agents/agent1/a.yaml -> .gitlab/agents/agent1/b.yaml -> .gitlab/agents/agent1/a.yaml",
+		},
+		{
+			name: "not found",
+			files: map[string]string{
+				includeConfigFile: `
+include:
+- local: a.yaml
+`,
+			},
+			expectedErr: "configuration file not found: .gitlab/agents/agent1/a.yaml",
+		},
+		{
+			name: "outside of repository",
+			files: map[string]string{
+				includeConfigFile: `
+include:
+- local: ../../../../a.yaml
+`,
+			},
+			expectedErr: ".gitlab/agents/agent1/config.yaml: include: invalid path ../../../../a.yaml",
+		},
+		{
+			name: "local and project",
+			files: map[string]string{
+				includeConfigFile: `
+include:
+- local: a.yaml
+  project: group/shared
+`,
+			},
+			expectedErr: ".gitlab/agents/agent1/config.yaml: include: either local, or project and file must be set",
+		},
+		{
+			name: "too big",
+			files: map[string]string{
+				includeConfigFile: `
+include:
+- local: a.yaml
+`,
+				".gitlab/agents/agent1/a.yaml": `
+gitops:
+  manifest_projects:
+  - id: app
+`,
+			},
+			maxSize:     40,
+			expectedErr: "configuration is too big, .gitlab/agents/agent1/a.yaml exceeds the size limit",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := setupIncludeResolver(t, tc.files, nil) // nolint: scopelint
+			if tc.maxSize != 0 {                        // nolint: scopelint
+				r.sizeBudget = tc.maxSize // nolint: scopelint
+			}
+			_, err := r.load(agentConfigRepository(), includeConfigFile)
+			require.Error(t, err)
+			assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+			assert.True(t, errors.As(err, new(*errz.UserError)))
+		})
+	}
+}
+
+func TestIncludeTooManyFiles(t *testing.T) {
+	files := map[string]string{}
+	config := "include:\n"
+	for i := 0; i <= maxIncludedFiles; i++ {
+		name := string(rune('a'+i)) + ".yaml"
+		config += "- local: " + name + "\n"
+		files[".gitlab/agents/agent1/"+name] = "{}"
+	}
+	files[includeConfigFile] = config
+	r := setupIncludeResolver(t, files, nil)
+	_, err := r.load(agentConfigRepository(), includeConfigFile)
+	assert.EqualError(t, err, "too many included files, at most 20 files can be included")
+}
+
+// setupIncludeResolver returns a resolver that fetches files from the map.
+// Keys are paths in the agent's configuration project or "<project>:<path>" for other projects.
+func setupIncludeResolver(t *testing.T, files map[string]string, gitLabClient gitlab.ClientInterface) *includeResolver {
+	ctrl := gomock.NewController(t)
+	gitalyPool := mock_internalgitaly.NewMockPoolInterface(ctrl)
+	pf := mock_internalgitaly.NewMockPathFetcherInterface(ctrl)
+	gitalyPool.EXPECT().
+		PathFetcher(gomock.Any(), gomock.Any()).
+		Return(pf, nil).
+		AnyTimes()
+	pf.EXPECT().
+		FetchFile(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, repo *gitalypb.Repository, rev, repoPath []byte, sizeLimit int64) ([]byte, error) {
+			key := string(repoPath)
+			if repo.GlProjectPath == sharedProject {
+				assert.Equal(t, sharedCommitId, string(rev))
+				key = sharedProject + ":" + key
+			} else {
+				assert.Equal(t, revision, string(rev))
+			}
+			data, ok := files[key]
+			if !ok {
+				return nil, nil
+			}
+			if int64(len(data)) > sizeLimit {
+				return nil, fmt.Errorf("TreeEntry: %w", status.Error(codes.FailedPrecondition, "object exceeded maximum size"))
+			}
+			return []byte(data), nil
+		}).
+		AnyTimes()
+	return newIncludeResolver(context.Background(), &gitalyConfigSource{
+		gitaly: gitalyPool,
+		projectInfoClient: &projectinfo.Client{
+			GitLabClient: gitLabClient,
+		},
+		agentToken: mock_gitlab.AgentkToken,
+	}, maxConfigurationFileSize)
+}
+
+func agentConfigRepository() configRepository {
+	agentInfo := agentInfoObj()
+	return configRepository{
+		commitId:   revision,
+		gitalyInfo: &agentInfo.GitalyInfo,
+		repository: &agentInfo.Repository,
+	}
+}
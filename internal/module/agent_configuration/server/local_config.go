@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+)
+
+// localConfigSource reads configuration files from a checked out repository in a local directory.
+// Files from other projects cannot be included and such includes are skipped.
+type localConfigSource struct {
+	root string
+	// skipped holds the projects, includes from which have been skipped.
+	skipped []string
+}
+
+func (s *localConfigSource) fetchFile(ctx context.Context, repo configRepository, filename string, sizeLimit int64) ([]byte, error) {
+	f, err := os.Open(filepath.Join(s.root, filepath.FromSlash(filename)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close() // nolint: errcheck
+	data, err := ioutil.ReadAll(io.LimitReader(f, sizeLimit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > sizeLimit {
+		return nil, errFileTooBig
+	}
+	return data, nil
+}
+
+func (s *localConfigSource) projectRepository(ctx context.Context, project, ref string) (configRepository, error) {
+	s.skipped = append(s.skipped, project)
+	return configRepository{}, errSkipInclude
+}
+
+// LoadLocalConfiguration loads the agent's configuration file from a checked out configuration repository the same
+// way kas does, resolving include directives. filename is relative to repositoryRoot.
+// Files cannot be included from other projects. Such includes are skipped and the projects are returned.
+// The default maximum configuration file size applies.
+func LoadLocalConfiguration(ctx context.Context, repositoryRoot, filename string) (*agentcfg.AgentConfiguration, []string /* skipped projects */, error) {
+	source := &localConfigSource{
+		root: repositoryRoot,
+	}
+	repoFilename, ok := cleanRepositoryPath(filepath.ToSlash(filename))
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is outside of the repository", filename)
+	}
+	r := newIncludeResolver(ctx, source, defaultAgentConfigurationMaxConfigurationFileSize)
+	configFile, err := r.load(configRepository{}, repoFilename)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = configFile.Validate()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid agent configuration: %w", err)
+	}
+	return agent_configuration.ToAgentConfiguration(configFile), source.skipped, nil
+}
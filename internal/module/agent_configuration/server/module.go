@@ -2,23 +2,32 @@ package server
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_tracker"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modserver"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/grpctool"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/mathz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type module struct {
 	api                          modserver.API
 	gitaly                       gitaly.PoolInterface
-	agentRegisterer              agent_tracker.Registerer
+	gitLabClient                 gitlab.ClientInterface
+	projectInfoClient            *projectinfo.Client
+	connections                  *connectionRegistry
+	notifier                     *projectNotifier
 	maxConfigurationFileSize     int64
 	agentConfigurationPollPeriod time.Duration
 	maxConnectionAge             time.Duration
@@ -39,17 +48,88 @@ func (m *module) GetConfiguration(req *rpc.ConfigurationRequest, server rpc.Agen
 		log:                      grpctool.LoggerFromContext(ctx),
 		api:                      m.api,
 		gitaly:                   m.gitaly,
-		agentRegisterer:          m.agentRegisterer,
+		gitLabClient:             m.gitLabClient,
+		projectInfoClient:        m.projectInfoClient,
+		connections:              m.connections,
+		notifier:                 m.notifier,
+		wakeup:                   make(chan struct{}, 1),
 		server:                   server,
 		agentToken:               api.AgentTokenFromContext(ctx),
 		maxConfigurationFileSize: m.maxConfigurationFileSize,
 		lastProcessedCommitId:    req.CommitId,
+		ref:                      req.Ref,
 		connectedAgentInfo: &agent_tracker.ConnectedAgentInfo{
-			AgentMeta:    req.AgentMeta,
-			ConnectedAt:  timestamppb.Now(),
-			ConnectionId: mathz.Int63(),
+			AgentMeta:            req.AgentMeta,
+			ConnectedAt:          timestamppb.Now(),
+			ConnectionId:         mathz.Int63(),
+			AppliedConfiguration: toTrackerAppliedConfiguration(req.AppliedConfiguration),
+			ConfigurationRef:     req.Ref,
 		},
 	}
 	defer p.Cleanup()
-	return m.api.PollImmediateUntil(ctx, m.agentConfigurationPollPeriod, m.maxConnectionAge, p.Attempt)
+	return m.api.PollImmediateUntilWithWakeup(ctx, m.agentConfigurationPollPeriod, m.maxConnectionAge, p.wakeup, p.Attempt)
+}
+
+func (m *module) ProjectUpdated(ctx context.Context, req *rpc.ProjectUpdatedRequest) (*rpc.ProjectUpdatedResponse, error) {
+	n := m.notifier.notify(req.ProjectId)
+	grpctool.LoggerFromContext(ctx).Debug("Config: project updated", logz.ProjectId(strconv.FormatInt(req.ProjectId, 10)), logz.U64Count(uint64(n)))
+	return &rpc.ProjectUpdatedResponse{}, nil
+}
+
+func (m *module) ReportConfigurationErrors(ctx context.Context, req *rpc.ReportConfigurationErrorsRequest) (*rpc.ReportConfigurationErrorsResponse, error) {
+	log := grpctool.LoggerFromContext(ctx).With(logz.CommitId(req.CommitId))
+	errs := make([]configurationError, 0, len(req.Errors))
+	for _, e := range req.Errors {
+		errs = append(errs, configurationError{
+			Module:  e.Module,
+			Message: e.Message,
+			Local:   e.Local,
+		})
+	}
+	err := reportConfigurationErrors(ctx, m.gitLabClient, api.AgentTokenFromContext(ctx), req.CommitId, errs)
+	switch {
+	case err == nil:
+		return &rpc.ReportConfigurationErrorsResponse{}, nil
+	case gitlab.IsForbidden(err):
+		return nil, status.Error(codes.PermissionDenied, "forbidden")
+	case gitlab.IsUnauthorized(err):
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	default:
+		m.api.HandleProcessingError(ctx, log, "Config: failed to report configuration errors", err)
+		return nil, status.Error(codes.Unavailable, "failed to report configuration errors")
+	}
+}
+
+func (m *module) ReportAppliedConfiguration(ctx context.Context, req *rpc.ReportAppliedConfigurationRequest) (*rpc.ReportAppliedConfigurationResponse, error) {
+	log := grpctool.LoggerFromContext(ctx)
+	agentInfo, err, retErr := m.api.GetAgentInfo(ctx, log, api.AgentTokenFromContext(ctx), false)
+	if retErr {
+		return nil, err // no wrap
+	}
+	n := m.connections.updateAppliedConfiguration(ctx, agentInfo.Id, req.AgentMeta.GetPodNamespace(), req.AgentMeta.GetPodName(),
+		toTrackerAppliedConfiguration(req.AppliedConfiguration))
+	log.Debug("Config: applied configuration reported", logz.AgentId(agentInfo.Id), logz.CommitId(req.AppliedConfiguration.CommitId), logz.U64Count(uint64(n)))
+	return &rpc.ReportAppliedConfigurationResponse{}, nil
+}
+
+// toTrackerAppliedConfiguration converts the applied configuration, reported by agentk, into the form it is stored in
+// by the agent tracker.
+func toTrackerAppliedConfiguration(applied *rpc.AppliedConfiguration) *agent_tracker.AppliedConfiguration {
+	if applied == nil {
+		return nil
+	}
+	errs := make([]*agent_tracker.ConfigurationError, 0, len(applied.Errors))
+	for _, e := range applied.Errors {
+		errs = append(errs, &agent_tracker.ConfigurationError{
+			Module:  e.Module,
+			Message: e.Message,
+			Local:   e.Local,
+		})
+	}
+	return &agent_tracker.AppliedConfiguration{
+		CommitId:         applied.CommitId,
+		Hash:             applied.Hash,
+		RejectedCommitId: applied.RejectedCommitId,
+		Errors:           errs,
+	}
 }
@@ -2,13 +2,16 @@ package server
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_tracker"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modserver"
@@ -21,6 +24,8 @@ import (
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"gitlab.com/gitlab-org/gitaly/proto/go/gitalypb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/testing/protocmp"
 	"sigs.k8s.io/yaml"
@@ -34,10 +39,11 @@ const (
 )
 
 var (
-	_ modserver.Module             = &module{}
-	_ modserver.Factory            = &Factory{}
-	_ modserver.ApplyDefaults      = ApplyDefaults
-	_ rpc.AgentConfigurationServer = &module{}
+	_ modserver.Module                     = &module{}
+	_ modserver.Factory                    = &Factory{}
+	_ modserver.ApplyDefaults              = ApplyDefaults
+	_ rpc.AgentConfigurationServer         = &module{}
+	_ rpc.ConfigurationNotificationsServer = &module{}
 )
 
 func TestGetConfiguration(t *testing.T) {
@@ -62,6 +68,12 @@ func TestGetConfiguration(t *testing.T) {
 				},
 			},
 			CommitId: revision,
+			AgentIdentity: &modshared.AgentIdentity{
+				Id:          agentInfo.Id,
+				Name:        agentInfo.Name,
+				ProjectId:   agentInfo.ProjectId,
+				ProjectPath: agentInfo.Repository.GlProjectPath,
+			},
 		}))
 	p := mock_internalgitaly.NewMockPollerInterface(ctrl)
 	pf := mock_internalgitaly.NewMockPathFetcherInterface(ctrl)
@@ -92,13 +104,21 @@ func TestGetConfiguration(t *testing.T) {
 func TestGetConfigurationResumeConnection(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	m, agentInfo, ctrl, gitalyPool := setupModule(t)
+	appliedConfig := &rpc.AppliedConfiguration{
+		CommitId: revision,
+		Hash:     "c0ffee",
+	}
+	m, agentInfo, ctrl, gitalyPool := setupModuleWithConnection(t, &agent_tracker.ConnectedAgentInfo{
+		AppliedConfiguration: toTrackerAppliedConfiguration(appliedConfig), // connection is registered with the applied configuration
+	})
 	resp := mock_rpc.NewMockAgentConfiguration_GetConfigurationServer(ctrl)
 	resp.EXPECT().
 		Context().
 		Return(mock_modserver.IncomingCtx(ctx, t, mock_gitlab.AgentkToken)).
 		MinTimes(1)
 	p := mock_internalgitaly.NewMockPollerInterface(ctrl)
+	pf := mock_internalgitaly.NewMockPathFetcherInterface(ctrl)
+	configFileName := agentConfigurationDirectory + "/" + agentInfo.Name + "/" + agentConfigurationFileName
 	gomock.InOrder(
 		gitalyPool.EXPECT().
 			Poller(gomock.Any(), &agentInfo.GitalyInfo).
@@ -109,32 +129,361 @@ func TestGetConfigurationResumeConnection(t *testing.T) {
 				UpdateAvailable: false,
 				CommitId:        revision,
 			}, nil),
+		// Configuration is fetched to find out what is included. Nothing is sent since nothing is included.
+		gitalyPool.EXPECT().
+			PathFetcher(gomock.Any(), &agentInfo.GitalyInfo).
+			Return(pf, nil),
+		pf.EXPECT().
+			FetchFile(gomock.Any(), &agentInfo.Repository, []byte(revision), []byte(configFileName), int64(maxConfigurationFileSize)).
+			Return(configToBytes(t, sampleConfig()), nil),
 	)
 	err := m.GetConfiguration(&rpc.ConfigurationRequest{
-		CommitId:  revision, // same commit id
+		CommitId:             revision, // same commit id
+		AgentMeta:            agentMeta(),
+		AppliedConfiguration: appliedConfig,
+	}, resp)
+	require.NoError(t, err)
+}
+
+func TestGetConfigurationFromRef(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m, agentInfo, ctrl, gitalyPool := setupModuleWithConnection(t, &agent_tracker.ConnectedAgentInfo{
+		ConfigurationRef: "canary",
+	})
+	resp := mock_rpc.NewMockAgentConfiguration_GetConfigurationServer(ctrl)
+	resp.EXPECT().
+		Context().
+		Return(mock_modserver.IncomingCtx(ctx, t, mock_gitlab.AgentkToken)).
+		MinTimes(1)
+	p := mock_internalgitaly.NewMockPollerInterface(ctrl)
+	pf := mock_internalgitaly.NewMockPathFetcherInterface(ctrl)
+	configFileName := agentConfigurationDirectory + "/" + agentInfo.Name + "/" + agentConfigurationFileName
+	gomock.InOrder(
+		gitalyPool.EXPECT().
+			Poller(gomock.Any(), &agentInfo.GitalyInfo).
+			Return(p, nil),
+		p.EXPECT().
+			Poll(gomock.Any(), &agentInfo.Repository, revision, "canary").
+			Return(&gitaly.PollInfo{
+				UpdateAvailable: false,
+				CommitId:        revision,
+			}, nil),
+		gitalyPool.EXPECT().
+			PathFetcher(gomock.Any(), &agentInfo.GitalyInfo).
+			Return(pf, nil),
+		pf.EXPECT().
+			FetchFile(gomock.Any(), &agentInfo.Repository, []byte(revision), []byte(configFileName), int64(maxConfigurationFileSize)).
+			Return(configToBytes(t, sampleConfig()), nil),
+	)
+	err := m.GetConfiguration(&rpc.ConfigurationRequest{
+		CommitId:  revision,
 		AgentMeta: agentMeta(),
+		Ref:       "canary",
 	}, resp)
 	require.NoError(t, err)
 }
 
-func setupModule(t *testing.T) (*module, *api.AgentInfo, *gomock.Controller, *mock_internalgitaly.MockPoolInterface) { // nolint: unparam
+func TestGetConfigurationReportsRefNotFound(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m, agentInfo, ctrl, gitalyPool := setupModuleWithConnection(t, &agent_tracker.ConnectedAgentInfo{
+		ConfigurationRef: "canary",
+	})
+	gitLabClient := mock_gitlab.NewMockClientInterface(ctrl)
+	m.gitLabClient = gitLabClient
+	resp := mock_rpc.NewMockAgentConfiguration_GetConfigurationServer(ctrl)
+	resp.EXPECT().
+		Context().
+		Return(mock_modserver.IncomingCtx(ctx, t, mock_gitlab.AgentkToken)).
+		MinTimes(1)
+	p := mock_internalgitaly.NewMockPollerInterface(ctrl)
+	gomock.InOrder(
+		gitalyPool.EXPECT().
+			Poller(gomock.Any(), &agentInfo.GitalyInfo).
+			Return(p, nil),
+		p.EXPECT().
+			Poll(gomock.Any(), &agentInfo.Repository, "", "canary").
+			Return(nil, &gitaly.RefNotFoundError{
+				RefName: "canary",
+			}),
+		m.api.(*mock_modserver.MockAPI).EXPECT().
+			HandleProcessingError(gomock.Any(), gomock.Any(), "Config: repository poll failed", matcher.ErrorEq("configuration ref canary not found")),
+		gitLabClient.EXPECT().
+			DoJSON(gomock.Any(), http.MethodPost, configurationErrorsApiPath, nil, mock_gitlab.AgentkToken, &configurationErrors{
+				Errors: []configurationError{
+					{
+						Message: "configuration ref canary not found",
+					},
+				},
+			}, nil),
+	)
+	err := m.GetConfiguration(&rpc.ConfigurationRequest{
+		AgentMeta: agentMeta(),
+		Ref:       "canary",
+	}, resp)
+	require.NoError(t, err)
+}
+
+func TestGetConfigurationReportsUserError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m, agentInfo, ctrl, gitalyPool := setupModule(t)
+	gitLabClient := mock_gitlab.NewMockClientInterface(ctrl)
+	m.gitLabClient = gitLabClient
+	resp := mock_rpc.NewMockAgentConfiguration_GetConfigurationServer(ctrl)
+	resp.EXPECT().
+		Context().
+		Return(mock_modserver.IncomingCtx(ctx, t, mock_gitlab.AgentkToken)).
+		MinTimes(1)
+	p := mock_internalgitaly.NewMockPollerInterface(ctrl)
+	pf := mock_internalgitaly.NewMockPathFetcherInterface(ctrl)
+	configFileName := agentConfigurationDirectory + "/" + agentInfo.Name + "/" + agentConfigurationFileName
+	gomock.InOrder(
+		gitalyPool.EXPECT().
+			Poller(gomock.Any(), &agentInfo.GitalyInfo).
+			Return(p, nil),
+		p.EXPECT().
+			Poll(gomock.Any(), &agentInfo.Repository, "", gitaly.DefaultBranch).
+			Return(&gitaly.PollInfo{
+				UpdateAvailable: true,
+				CommitId:        revision,
+			}, nil),
+		gitalyPool.EXPECT().
+			PathFetcher(gomock.Any(), &agentInfo.GitalyInfo).
+			Return(pf, nil),
+		pf.EXPECT().
+			FetchFile(gomock.Any(), &agentInfo.Repository, []byte(revision), []byte(configFileName), int64(maxConfigurationFileSize)).
+			Return(nil, nil),
+		m.api.(*mock_modserver.MockAPI).EXPECT().
+			HandleProcessingError(gomock.Any(), gomock.Any(), "Config: failed to fetch", gomock.Any()),
+		gitLabClient.EXPECT().
+			DoJSON(gomock.Any(), http.MethodPost, configurationErrorsApiPath, nil, mock_gitlab.AgentkToken, &configurationErrors{
+				CommitId: revision,
+				Errors: []configurationError{
+					{
+						Message: "configuration file not found: " + configFileName,
+					},
+				},
+			}, nil),
+	)
+	err := m.GetConfiguration(&rpc.ConfigurationRequest{
+		AgentMeta: agentMeta(),
+	}, resp)
+	require.NoError(t, err)
+}
+
+func TestProjectUpdatedWakesUpPollJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	ctrl := gomock.NewController(t)
-	mockApi := mock_modserver.NewMockAPIWithMockPoller(ctrl, 1)
+	mockApi := mock_modserver.NewMockAPI(ctrl)
 	gitalyPool := mock_internalgitaly.NewMockPoolInterface(ctrl)
 	agentTracker := mock_agent_tracker.NewMockTracker(ctrl)
 	m := &module{
 		api:                          mockApi,
-		agentRegisterer:              agentTracker,
+		connections:                  newConnectionRegistry(agentTracker),
+		notifier:                     newProjectNotifier(),
 		gitaly:                       gitalyPool,
 		maxConfigurationFileSize:     maxConfigurationFileSize,
 		agentConfigurationPollPeriod: 10 * time.Minute,
 	}
 	agentInfo := agentInfoObj()
-	connMatcher := matcher.ProtoEq(t, &agent_tracker.ConnectedAgentInfo{
+	agentInfo.ProjectId = 234
+	resp := mock_rpc.NewMockAgentConfiguration_GetConfigurationServer(ctrl)
+	resp.EXPECT().
+		Context().
+		Return(mock_modserver.IncomingCtx(ctx, t, mock_gitlab.AgentkToken)).
+		MinTimes(1)
+	p := mock_internalgitaly.NewMockPollerInterface(ctrl)
+	mockApi.EXPECT().
+		GetAgentInfo(gomock.Any(), gomock.Any(), mock_gitlab.AgentkToken, true).
+		Return(agentInfo, nil, false).
+		Times(2)
+	agentTracker.EXPECT().
+		RegisterConnection(gomock.Any(), gomock.Any())
+	agentTracker.EXPECT().
+		UnregisterConnection(gomock.Any(), gomock.Any())
+	gitalyPool.EXPECT().
+		Poller(gomock.Any(), &agentInfo.GitalyInfo).
+		Return(p, nil).
+		Times(2)
+	p.EXPECT().
+		Poll(gomock.Any(), &agentInfo.Repository, revision, gitaly.DefaultBranch).
+		Return(&gitaly.PollInfo{
+			UpdateAvailable: false,
+			CommitId:        revision,
+		}, nil).
+		Times(2)
+	// Configuration is fetched once to find out what is included
+	pf := mock_internalgitaly.NewMockPathFetcherInterface(ctrl)
+	gitalyPool.EXPECT().
+		PathFetcher(gomock.Any(), &agentInfo.GitalyInfo).
+		Return(pf, nil)
+	pf.EXPECT().
+		FetchFile(gomock.Any(), &agentInfo.Repository, []byte(revision), gomock.Any(), int64(maxConfigurationFileSize)).
+		Return(configToBytes(t, sampleConfig()), nil)
+	mockApi.EXPECT().
+		PollImmediateUntilWithWakeup(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, interval, connectionMaxAge time.Duration, wakeup <-chan struct{}, condition modserver.ConditionFunc) error {
+			done, err := condition()
+			require.NoError(t, err)
+			require.False(t, done)
+			// An update of another project does not wake up the job
+			_, err = m.ProjectUpdated(ctx, &rpc.ProjectUpdatedRequest{ProjectId: agentInfo.ProjectId + 1})
+			require.NoError(t, err)
+			assert.Empty(t, wakeup)
+			_, err = m.ProjectUpdated(ctx, &rpc.ProjectUpdatedRequest{ProjectId: agentInfo.ProjectId})
+			require.NoError(t, err)
+			<-wakeup
+			_, err = condition()
+			return err
+		})
+	err := m.GetConfiguration(&rpc.ConfigurationRequest{
+		CommitId:  revision,
 		AgentMeta: agentMeta(),
-		AgentId:   agentInfo.Id,
-		ProjectId: agentInfo.ProjectId,
-	}, protocmp.IgnoreFields(&agent_tracker.ConnectedAgentInfo{}, "connected_at", "connection_id"))
+	}, resp)
+	require.NoError(t, err)
+	assert.Empty(t, m.notifier.subscribers) // unsubscribed on cleanup
+}
+
+func TestReportConfigurationErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	gitLabClient := mock_gitlab.NewMockClientInterface(ctrl)
+	m := &module{
+		api:          mock_modserver.NewMockAPI(ctrl),
+		gitLabClient: gitLabClient,
+	}
+	gitLabClient.EXPECT().
+		DoJSON(gomock.Any(), http.MethodPost, configurationErrorsApiPath, nil, mock_gitlab.AgentkToken, &configurationErrors{
+			CommitId: revision,
+			Errors: []configurationError{
+				{
+					Module:  "gitops",
+					Message: "bad project",
+				},
+			},
+		}, nil)
+	_, err := m.ReportConfigurationErrors(mock_modserver.IncomingCtx(context.Background(), t, mock_gitlab.AgentkToken), &rpc.ReportConfigurationErrorsRequest{
+		CommitId: revision,
+		Errors: []*rpc.ConfigurationError{
+			{
+				Module:  "gitops",
+				Message: "bad project",
+			},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestReportAppliedConfiguration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockApi := mock_modserver.NewMockAPI(ctrl)
+	agentTracker := mock_agent_tracker.NewMockTracker(ctrl)
+	m := &module{
+		api:         mockApi,
+		connections: newConnectionRegistry(agentTracker),
+	}
+	agentInfo := agentInfoObj()
+	info := &agent_tracker.ConnectedAgentInfo{
+		AgentMeta:    agentMeta(),
+		ConnectionId: 123,
+		AgentId:      agentInfo.Id,
+		ProjectId:    agentInfo.ProjectId,
+	}
+	applied := &rpc.AppliedConfiguration{
+		CommitId:         revision,
+		Hash:             "c0ffee",
+		RejectedCommitId: "b0b0",
+		Errors: []*rpc.ConfigurationError{
+			{
+				Module:  "gitops",
+				Message: "bad project",
+			},
+		},
+	}
+	gomock.InOrder(
+		agentTracker.EXPECT().
+			RegisterConnection(gomock.Any(), info),
+		mockApi.EXPECT().
+			GetAgentInfo(gomock.Any(), gomock.Any(), mock_gitlab.AgentkToken, false).
+			Return(agentInfo, nil, false),
+		agentTracker.EXPECT().
+			RegisterConnection(gomock.Any(), matcher.ProtoEq(t, &agent_tracker.ConnectedAgentInfo{
+				AgentMeta:    agentMeta(),
+				ConnectionId: 123,
+				AgentId:      agentInfo.Id,
+				ProjectId:    agentInfo.ProjectId,
+				AppliedConfiguration: &agent_tracker.AppliedConfiguration{
+					CommitId:         revision,
+					Hash:             "c0ffee",
+					RejectedCommitId: "b0b0",
+					Errors: []*agent_tracker.ConfigurationError{
+						{
+							Module:  "gitops",
+							Message: "bad project",
+						},
+					},
+				},
+			})),
+	)
+	m.connections.register(context.Background(), info)
+	_, err := m.ReportAppliedConfiguration(mock_modserver.IncomingCtx(context.Background(), t, mock_gitlab.AgentkToken), &rpc.ReportAppliedConfigurationRequest{
+		AgentMeta:            agentMeta(),
+		AppliedConfiguration: applied,
+	})
+	require.NoError(t, err)
+}
+
+func TestReportConfigurationErrorsUnauthorized(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	gitLabClient := mock_gitlab.NewMockClientInterface(ctrl)
+	m := &module{
+		api:          mock_modserver.NewMockAPI(ctrl),
+		gitLabClient: gitLabClient,
+	}
+	gitLabClient.EXPECT().
+		DoJSON(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&gitlab.ClientError{
+			Kind:       gitlab.ErrorKindUnauthorized,
+			StatusCode: http.StatusUnauthorized,
+		})
+	_, err := m.ReportConfigurationErrors(mock_modserver.IncomingCtx(context.Background(), t, mock_gitlab.AgentkToken), &rpc.ReportConfigurationErrorsRequest{
+		CommitId: revision,
+		Errors: []*rpc.ConfigurationError{
+			{
+				Module:  "gitops",
+				Message: "bad project",
+			},
+		},
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func setupModule(t *testing.T) (*module, *api.AgentInfo, *gomock.Controller, *mock_internalgitaly.MockPoolInterface) { // nolint: unparam
+	return setupModuleWithConnection(t, &agent_tracker.ConnectedAgentInfo{})
+}
+
+// setupModuleWithConnection sets up a module that expects a connection with the given information to be registered.
+// Agent metadata and ids are set by this function.
+func setupModuleWithConnection(t *testing.T, conn *agent_tracker.ConnectedAgentInfo) (*module, *api.AgentInfo, *gomock.Controller, *mock_internalgitaly.MockPoolInterface) {
+	ctrl := gomock.NewController(t)
+	mockApi := mock_modserver.NewMockAPIWithMockWakeupPoller(ctrl, 1)
+	gitalyPool := mock_internalgitaly.NewMockPoolInterface(ctrl)
+	agentTracker := mock_agent_tracker.NewMockTracker(ctrl)
+	m := &module{
+		api:                          mockApi,
+		connections:                  newConnectionRegistry(agentTracker),
+		notifier:                     newProjectNotifier(),
+		gitaly:                       gitalyPool,
+		maxConfigurationFileSize:     maxConfigurationFileSize,
+		agentConfigurationPollPeriod: 10 * time.Minute,
+	}
+	agentInfo := agentInfoObj()
+	conn.AgentMeta = agentMeta()
+	conn.AgentId = agentInfo.Id
+	conn.ProjectId = agentInfo.ProjectId
+	connMatcher := matcher.ProtoEq(t, conn, protocmp.IgnoreFields(&agent_tracker.ConnectedAgentInfo{}, "connected_at", "connection_id"))
 	gomock.InOrder(
 		mockApi.EXPECT().
 			GetAgentInfo(gomock.Any(), gomock.Any(), mock_gitlab.AgentkToken, true).
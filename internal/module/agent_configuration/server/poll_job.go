@@ -1,22 +1,23 @@
 package server
 
 import (
-	"bytes"
 	"context"
-	"fmt"
+	"errors"
 	"path"
 
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_configuration/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/agent_tracker"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modserver"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/encoding/protojson"
-	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -25,17 +26,31 @@ const (
 )
 
 type pollJob struct {
-	ctx                      context.Context
-	log                      *zap.Logger
-	api                      modserver.API
-	gitaly                   gitaly.PoolInterface
-	agentRegisterer          agent_tracker.Registerer
+	ctx               context.Context
+	log               *zap.Logger
+	api               modserver.API
+	gitaly            gitaly.PoolInterface
+	gitLabClient      gitlab.ClientInterface
+	projectInfoClient *projectinfo.Client
+	connections       *connectionRegistry
+	notifier          *projectNotifier
+	// wakeup receives a value when the configuration project is updated.
+	wakeup                   chan struct{}
 	server                   rpc.AgentConfiguration_GetConfigurationServer
 	agentToken               api.AgentToken
 	maxConfigurationFileSize int64
 	lastProcessedCommitId    string
-	connectedAgentInfo       *agent_tracker.ConnectedAgentInfo
-	connectionRegistered     bool
+	// ref is the branch or tag to read the configuration from. Empty, i.e. gitaly.DefaultBranch, means the default branch.
+	ref                  string
+	connectedAgentInfo   *agent_tracker.ConnectedAgentInfo
+	connectionRegistered bool
+	// lastReportedCommitId is the last commit, errors in the configuration of which have been reported to GitLab.
+	lastReportedCommitId string
+	// refNotFoundReported is true if it has been reported to GitLab that ref does not exist.
+	refNotFoundReported bool
+	// includedRepositories holds the commits of other projects, files from which have been included into the last
+	// sent configuration. nil if no configuration has been sent on this connection yet.
+	includedRepositories map[projectRef]configRepository
 }
 
 func (j *pollJob) Attempt() (bool /*done*/, error) {
@@ -49,7 +64,8 @@ func (j *pollJob) Attempt() (bool /*done*/, error) {
 	if !j.connectionRegistered { // only register once
 		j.connectedAgentInfo.AgentId = agentInfo.Id
 		j.connectedAgentInfo.ProjectId = agentInfo.ProjectId
-		j.agentRegisterer.RegisterConnection(j.ctx, j.connectedAgentInfo)
+		j.connections.register(j.ctx, j.connectedAgentInfo)
+		j.notifier.subscribe(agentInfo.ProjectId, j.wakeup)
 		j.connectionRegistered = true
 	}
 	log := j.log.With(logz.AgentId(agentInfo.Id), logz.ProjectId(agentInfo.Repository.GlProjectPath)) // nolint:govet
@@ -58,83 +74,139 @@ func (j *pollJob) Attempt() (bool /*done*/, error) {
 		j.api.HandleProcessingError(j.ctx, log, "Config: Poller", err)
 		return false, nil // don't want to close the response stream, so report no error
 	}
-	info, err := p.Poll(j.ctx, &agentInfo.Repository, j.lastProcessedCommitId, gitaly.DefaultBranch)
+	info, err := p.Poll(j.ctx, &agentInfo.Repository, j.lastProcessedCommitId, j.ref)
 	if err != nil {
+		var notFound *gitaly.RefNotFoundError
+		if errors.As(err, &notFound) {
+			err = errz.NewUserErrorf("configuration ref %s not found", j.ref)
+		}
 		j.api.HandleProcessingError(j.ctx, log, "Config: repository poll failed", err)
+		if notFound != nil && !j.refNotFoundReported {
+			// There is no commit the error belongs to.
+			j.refNotFoundReported = j.reportErrors(log, "", err)
+		}
 		return false, nil // don't want to close the response stream, so report no error
 	}
-	if !info.UpdateAvailable {
+	j.refNotFoundReported = false
+	resumed := false
+	switch {
+	case info.UpdateAvailable:
+		log.Info("Config: new commit", logz.CommitId(info.CommitId))
+	case j.includedRepositories == nil:
+		// The connection has been resumed. Files from other projects might have changed since the configuration
+		// was sent on a previous connection so the configuration has to be fetched to find out what is included.
+		resumed = true
+	case j.includesChanged(log):
+		log.Info("Config: included files changed", logz.CommitId(info.CommitId))
+	default:
 		log.Debug("Config: no updates", logz.CommitId(j.lastProcessedCommitId))
 		return false, nil // don't want to close the response stream, so report no error
 	}
-	log.Info("Config: new commit", logz.CommitId(info.CommitId))
-	config, err := j.fetchConfiguration(j.ctx, agentInfo, info.CommitId)
+	config, included, err := j.fetchConfiguration(j.ctx, agentInfo, info.CommitId)
 	if err != nil {
 		j.api.HandleProcessingError(j.ctx, log, "Config: failed to fetch", err)
+		j.reportUserError(log, info.CommitId, err)
 		return false, nil // don't want to close the response stream, so report no error
 	}
+	if resumed && len(included) == 0 {
+		// Nothing is included from other projects, the agent has this configuration already.
+		log.Debug("Config: no updates", logz.CommitId(j.lastProcessedCommitId))
+		j.includedRepositories = included
+		return false, nil
+	}
 	err = j.server.Send(&rpc.ConfigurationResponse{
 		Configuration: config,
 		CommitId:      info.CommitId,
+		AgentIdentity: &modshared.AgentIdentity{
+			Id:          agentInfo.Id,
+			Name:        agentInfo.Name,
+			ProjectId:   agentInfo.ProjectId,
+			ProjectPath: agentInfo.Repository.GlProjectPath,
+		},
 	})
 	if err != nil {
 		return false, j.api.HandleSendError(log, "Config: failed to send config", err)
 	}
 	j.lastProcessedCommitId = info.CommitId
+	j.includedRepositories = included
 	return false, nil
 }
 
+// includesChanged returns true if a ref of a project, a file from which has been included, points to a different
+// commit now. Errors are treated as changes so that the configuration is fetched again and the error is reported.
+func (j *pollJob) includesChanged(log *zap.Logger) bool {
+	for ref, repo := range j.includedRepositories {
+		p, err := j.gitaly.Poller(j.ctx, repo.gitalyInfo)
+		if err != nil {
+			j.api.HandleProcessingError(j.ctx, log, "Config: Poller", err)
+			return true
+		}
+		info, err := p.Poll(j.ctx, repo.repository, repo.commitId, ref.ref)
+		if err != nil || info.UpdateAvailable {
+			return true
+		}
+	}
+	return false
+}
+
+// reportUserError sends err to GitLab if it is a user error.
+// Errors are only reported once for each commit because the same commit is fetched again on each poll.
+func (j *pollJob) reportUserError(log *zap.Logger, commitId string, err error) {
+	var ue *errz.UserError
+	if !errors.As(err, &ue) || j.lastReportedCommitId == commitId {
+		return
+	}
+	if j.reportErrors(log, commitId, err) {
+		j.lastReportedCommitId = commitId
+	}
+}
+
+// reportErrors sends err to GitLab and returns true on success.
+func (j *pollJob) reportErrors(log *zap.Logger, commitId string, err error) bool {
+	err = reportConfigurationErrors(j.ctx, j.gitLabClient, j.agentToken, commitId, []configurationError{
+		{
+			Message: err.Error(), // user errors may be wrapped to add context
+		},
+	})
+	if err != nil {
+		j.api.HandleProcessingError(j.ctx, log, "Config: failed to report configuration error", err)
+		return false
+	}
+	return true
+}
+
 func (j *pollJob) Cleanup() {
 	if !j.connectionRegistered {
 		return
 	}
-	j.agentRegisterer.UnregisterConnection(context.Background(), j.connectedAgentInfo)
+	j.notifier.unsubscribe(j.connectedAgentInfo.ProjectId, j.wakeup)
+	j.connections.unregister(context.Background(), j.connectedAgentInfo)
 }
 
 // fetchConfiguration fetches agent's configuration from a corresponding repository.
 // Assumes configuration is stored in ".gitlab/agents/<agent id>/config.yaml" file.
+// Files, included into the configuration file, are merged into it. The configuration file and all included files
+// together must not exceed the maximum configuration file size.
+// The commits of other projects, files from which have been included, are returned too.
 // fetchConfiguration returns a wrapped context.Canceled, context.DeadlineExceeded or gRPC error if ctx signals done and interrupts a running gRPC call.
-func (j *pollJob) fetchConfiguration(ctx context.Context, agentInfo *api.AgentInfo, revision string) (*agentcfg.AgentConfiguration, error) {
-	pf, err := j.gitaly.PathFetcher(ctx, &agentInfo.GitalyInfo)
-	if err != nil {
-		return nil, fmt.Errorf("PathFetcher: %w", err) // wrap
-	}
+func (j *pollJob) fetchConfiguration(ctx context.Context, agentInfo *api.AgentInfo, revision string) (*agentcfg.AgentConfiguration, map[projectRef]configRepository, error) {
 	filename := path.Join(agentConfigurationDirectory, agentInfo.Name, agentConfigurationFileName)
-	configYAML, err := pf.FetchFile(ctx, &agentInfo.Repository, []byte(revision), []byte(filename), j.maxConfigurationFileSize)
+	r := newIncludeResolver(ctx, &gitalyConfigSource{
+		gitaly:            j.gitaly,
+		projectInfoClient: j.projectInfoClient,
+		agentToken:        j.agentToken,
+	}, j.maxConfigurationFileSize)
+	configFile, err := r.load(configRepository{
+		commitId:   revision,
+		gitalyInfo: &agentInfo.GitalyInfo,
+		repository: &agentInfo.Repository,
+	}, filename)
 	if err != nil {
-		return nil, fmt.Errorf("fetch agent configuration: %w", err) // wrap
-	}
-	if configYAML == nil {
-		return nil, errz.NewUserErrorf("configuration file not found: %s", filename)
-	}
-	configFile, err := parseYAMLToConfiguration(configYAML)
-	if err != nil {
-		return nil, errz.NewUserErrorWithCause(err, "failed to parse agent configuration")
+		return nil, nil, err // don't wrap
 	}
 	err = configFile.Validate()
 	if err != nil {
-		return nil, errz.NewUserErrorWithCause(err, "invalid agent configuration")
-	}
-	return &agentcfg.AgentConfiguration{
-		Gitops:        configFile.Gitops,
-		Observability: configFile.Observability,
-		Cilium:        configFile.Cilium,
-	}, nil
-}
-
-func parseYAMLToConfiguration(configYAML []byte) (*agentcfg.ConfigurationFile, error) {
-	configJSON, err := yaml.YAMLToJSON(configYAML)
-	if err != nil {
-		return nil, fmt.Errorf("YAMLToJSON: %v", err)
-	}
-	configFile := &agentcfg.ConfigurationFile{}
-	if bytes.Equal(configJSON, []byte("null")) {
-		// Empty config
-		return configFile, nil
-	}
-	err = protojson.Unmarshal(configJSON, configFile)
-	if err != nil {
-		return nil, fmt.Errorf("protojson.Unmarshal: %v", err)
+		return nil, nil, errz.NewUserErrorWithCause(err, "invalid agent configuration")
 	}
-	return configFile, nil
+	return agent_configuration.ToAgentConfiguration(configFile), r.repositories, nil
 }
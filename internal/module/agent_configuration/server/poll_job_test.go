@@ -1,91 +1,88 @@
 package server
 
 import (
-	"strconv"
+	"context"
+	"errors"
+	"net/http"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
-	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
-	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/testing/protocmp"
-	"sigs.k8s.io/yaml"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_gitlab"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_internalgitaly"
+	"gitlab.com/gitlab-org/gitaly/proto/go/gitalypb"
+	"go.uber.org/zap/zaptest"
 )
 
-func TestEmptyConfig(t *testing.T) {
-	t.Run("comments", func(t *testing.T) {
-		data := []byte(`
-#gitops:
-#  manifest_projects:
-#  - id: "root/gitops-manifests"
-#    paths:
-#      - glob: "/bla/**"
-`)
-		assertEmpty(t, data)
-	})
-	t.Run("empty", func(t *testing.T) {
-		data := []byte("")
-		assertEmpty(t, data)
-	})
-	t.Run("newline", func(t *testing.T) {
-		data := []byte("\n")
-		assertEmpty(t, data)
-	})
-}
-
-func assertEmpty(t *testing.T, data []byte) {
-	config, err := parseYAMLToConfiguration(data)
-	require.NoError(t, err)
-	diff := cmp.Diff(config, &agentcfg.ConfigurationFile{}, protocmp.Transform()) // nolint: scopelint
-	assert.Empty(t, diff)
+func TestReportUserErrorOncePerCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	gitLabClient := mock_gitlab.NewMockClientInterface(ctrl)
+	j := &pollJob{
+		ctx:          context.Background(),
+		gitLabClient: gitLabClient,
+		agentToken:   mock_gitlab.AgentkToken,
+	}
+	userErr := errz.NewUserError("invalid agent configuration")
+	gomock.InOrder(
+		gitLabClient.EXPECT().
+			DoJSON(gomock.Any(), http.MethodPost, configurationErrorsApiPath, nil, mock_gitlab.AgentkToken, &configurationErrors{
+				CommitId: "commit1",
+				Errors: []configurationError{
+					{
+						Message: "invalid agent configuration",
+					},
+				},
+			}, nil),
+		gitLabClient.EXPECT().
+			DoJSON(gomock.Any(), http.MethodPost, configurationErrorsApiPath, nil, mock_gitlab.AgentkToken, gomock.Any(), nil),
+	)
+	log := zaptest.NewLogger(t)
+	j.reportUserError(log, "commit1", userErr)
+	j.reportUserError(log, "commit1", userErr)                      // same commit is not reported again
+	j.reportUserError(log, "commit2", errors.New("gitaly is down")) // not a user error
+	j.reportUserError(log, "commit2", userErr)
 }
 
-func TestYAMLToConfigurationAndBack(t *testing.T) {
-	testCases := []struct {
-		given, expected string
-	}{
-		{
-			given: `{}
-`, // empty config
-			expected: `{}
-`,
-		},
-		{
-			given: `gitops: {}
-`,
-			expected: `gitops: {}
-`,
+func TestIncludesChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	gitalyPool := mock_internalgitaly.NewMockPoolInterface(ctrl)
+	p := mock_internalgitaly.NewMockPollerInterface(ctrl)
+	gitalyInfo := &api.GitalyInfo{Address: "127.0.0.1:321321"}
+	repo := &gitalypb.Repository{GlProjectPath: sharedProject}
+	j := &pollJob{
+		ctx:    context.Background(),
+		gitaly: gitalyPool,
+		includedRepositories: map[projectRef]configRepository{
+			{project: sharedProject, ref: "stable"}: {
+				project:    sharedProject,
+				commitId:   sharedCommitId,
+				gitalyInfo: gitalyInfo,
+				repository: repo,
+			},
 		},
-		{
-			given: `gitops:
-  manifest_projects: []
-`,
-			expected: `gitops: {}
-`, // empty slice is omitted
-		},
-		{
-			expected: `gitops:
-  manifest_projects:
-  - id: gitlab-org/cluster-integration/gitlab-agent
-`,
-			given: `gitops:
-  manifest_projects:
-  - id: gitlab-org/cluster-integration/gitlab-agent
-`,
-		},
-	}
-
-	for i, tc := range testCases {
-		t.Run(strconv.Itoa(i), func(t *testing.T) {
-			config, err := parseYAMLToConfiguration([]byte(tc.given)) // nolint: scopelint
-			require.NoError(t, err)
-			configJson, err := protojson.Marshal(config)
-			require.NoError(t, err)
-			configYaml, err := yaml.JSONToYAML(configJson)
-			require.NoError(t, err)
-			diff := cmp.Diff(tc.expected, string(configYaml)) // nolint: scopelint
-			assert.Empty(t, diff)
-		})
 	}
+	gitalyPool.EXPECT().
+		Poller(gomock.Any(), gitalyInfo).
+		Return(p, nil).
+		Times(2)
+	gomock.InOrder(
+		p.EXPECT().
+			Poll(gomock.Any(), repo, sharedCommitId, "stable").
+			Return(&gitaly.PollInfo{
+				UpdateAvailable: false,
+				CommitId:        sharedCommitId,
+			}, nil),
+		p.EXPECT().
+			Poll(gomock.Any(), repo, sharedCommitId, "stable").
+			Return(&gitaly.PollInfo{
+				UpdateAvailable: true,
+				CommitId:        "new" + sharedCommitId,
+			}, nil),
+	)
+	log := zaptest.NewLogger(t)
+	assert.False(t, j.includesChanged(log))
+	assert.True(t, j.includesChanged(log))
 }
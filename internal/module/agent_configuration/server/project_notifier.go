@@ -0,0 +1,55 @@
+package server
+
+import (
+	"sync"
+)
+
+// projectNotifier wakes up poll jobs when the configuration project of their agent is updated.
+type projectNotifier struct {
+	mu sync.Mutex
+	// subscribers maps project id to the wakeup channels of the poll jobs for agents configured in that project.
+	subscribers map[int64]map[chan<- struct{}]struct{}
+}
+
+func newProjectNotifier() *projectNotifier {
+	return &projectNotifier{
+		subscribers: make(map[int64]map[chan<- struct{}]struct{}),
+	}
+}
+
+// subscribe registers wakeup to be notified about updates of the project.
+// wakeup must be a buffered channel. Notifications are dropped when there is one pending already.
+func (n *projectNotifier) subscribe(projectId int64, wakeup chan<- struct{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	subs := n.subscribers[projectId]
+	if subs == nil {
+		subs = make(map[chan<- struct{}]struct{})
+		n.subscribers[projectId] = subs
+	}
+	subs[wakeup] = struct{}{}
+}
+
+func (n *projectNotifier) unsubscribe(projectId int64, wakeup chan<- struct{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	subs := n.subscribers[projectId]
+	delete(subs, wakeup)
+	if len(subs) == 0 {
+		delete(n.subscribers, projectId)
+	}
+}
+
+// notify wakes up all subscribers of the project and returns how many there were.
+func (n *projectNotifier) notify(projectId int64) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	subs := n.subscribers[projectId]
+	for wakeup := range subs {
+		select {
+		case wakeup <- struct{}{}:
+		default: // a notification is already pending
+		}
+	}
+	return len(subs)
+}
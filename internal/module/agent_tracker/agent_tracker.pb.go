@@ -34,11 +34,13 @@ type ConnectedAgentInfo struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AgentMeta    *modshared.AgentMeta `protobuf:"bytes,1,opt,name=agent_meta,json=agentMeta,proto3" json:"agent_meta,omitempty"`
-	ConnectedAt  *timestamp.Timestamp `protobuf:"bytes,2,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
-	ConnectionId int64                `protobuf:"varint,3,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
-	AgentId      int64                `protobuf:"varint,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ProjectId    int64                `protobuf:"varint,5,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	AgentMeta            *modshared.AgentMeta  `protobuf:"bytes,1,opt,name=agent_meta,json=agentMeta,proto3" json:"agent_meta,omitempty"`
+	ConnectedAt          *timestamp.Timestamp  `protobuf:"bytes,2,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	ConnectionId         int64                 `protobuf:"varint,3,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	AgentId              int64                 `protobuf:"varint,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ProjectId            int64                 `protobuf:"varint,5,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	AppliedConfiguration *AppliedConfiguration `protobuf:"bytes,6,opt,name=applied_configuration,json=appliedConfiguration,proto3" json:"applied_configuration,omitempty"`
+	ConfigurationRef     string                `protobuf:"bytes,7,opt,name=configuration_ref,json=configurationRef,proto3" json:"configuration_ref,omitempty"`
 }
 
 func (x *ConnectedAgentInfo) Reset() {
@@ -108,6 +110,154 @@ func (x *ConnectedAgentInfo) GetProjectId() int64 {
 	return 0
 }
 
+func (x *ConnectedAgentInfo) GetAppliedConfiguration() *AppliedConfiguration {
+	if x != nil {
+		return x.AppliedConfiguration
+	}
+	return nil
+}
+
+func (x *ConnectedAgentInfo) GetConfigurationRef() string {
+	if x != nil {
+		return x.ConfigurationRef
+	}
+	return ""
+}
+
+type AppliedConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommitId         string                `protobuf:"bytes,1,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
+	Hash             string                `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	RejectedCommitId string                `protobuf:"bytes,3,opt,name=rejected_commit_id,json=rejectedCommitId,proto3" json:"rejected_commit_id,omitempty"`
+	Errors           []*ConfigurationError `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *AppliedConfiguration) Reset() {
+	*x = AppliedConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppliedConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppliedConfiguration) ProtoMessage() {}
+
+func (x *AppliedConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppliedConfiguration.ProtoReflect.Descriptor instead.
+func (*AppliedConfiguration) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_tracker_agent_tracker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AppliedConfiguration) GetCommitId() string {
+	if x != nil {
+		return x.CommitId
+	}
+	return ""
+}
+
+func (x *AppliedConfiguration) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *AppliedConfiguration) GetRejectedCommitId() string {
+	if x != nil {
+		return x.RejectedCommitId
+	}
+	return ""
+}
+
+func (x *AppliedConfiguration) GetErrors() []*ConfigurationError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ConfigurationError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Module  string `protobuf:"bytes,1,opt,name=module,proto3" json:"module,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Local   bool   `protobuf:"varint,3,opt,name=local,proto3" json:"local,omitempty"`
+}
+
+func (x *ConfigurationError) Reset() {
+	*x = ConfigurationError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigurationError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigurationError) ProtoMessage() {}
+
+func (x *ConfigurationError) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigurationError.ProtoReflect.Descriptor instead.
+func (*ConfigurationError) Descriptor() ([]byte, []int) {
+	return file_internal_module_agent_tracker_agent_tracker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConfigurationError) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *ConfigurationError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ConfigurationError) GetLocal() bool {
+	if x != nil {
+		return x.Local
+	}
+	return false
+}
+
 type ExpiringValue struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -120,7 +270,7 @@ type ExpiringValue struct {
 func (x *ExpiringValue) Reset() {
 	*x = ExpiringValue{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[1]
+		mi := &file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -133,7 +283,7 @@ func (x *ExpiringValue) String() string {
 func (*ExpiringValue) ProtoMessage() {}
 
 func (x *ExpiringValue) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[1]
+	mi := &file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -146,7 +296,7 @@ func (x *ExpiringValue) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExpiringValue.ProtoReflect.Descriptor instead.
 func (*ExpiringValue) Descriptor() ([]byte, []int) {
-	return file_internal_module_agent_tracker_agent_tracker_proto_rawDescGZIP(), []int{1}
+	return file_internal_module_agent_tracker_agent_tracker_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ExpiringValue) GetExpiresAt() *timestamp.Timestamp {
@@ -177,7 +327,7 @@ var file_internal_module_agent_tracker_agent_tracker_proto_rawDesc = []byte{
 	0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x29, 0x69, 0x6e, 0x74,
 	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x2f, 0x6d, 0x6f, 0x64,
 	0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f, 0x6d, 0x6f, 0x64, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf4, 0x01, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x88, 0x03, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
 	0x63, 0x74, 0x65, 0x64, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x40, 0x0a,
 	0x0a, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x21, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
@@ -192,21 +342,48 @@ var file_internal_module_agent_tracker_agent_tracker_proto_rawDesc = []byte{
 	0x6e, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
 	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1d,
 	0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x64, 0x22, 0x76, 0x0a,
-	0x0d, 0x45, 0x78, 0x70, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x39,
-	0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
-	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x56, 0x5a, 0x54, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d, 0x6f, 0x72, 0x67, 0x2f, 0x63,
-	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f,
-	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x2f,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x28, 0x03, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x49, 0x64, 0x12, 0x65, 0x0a,
+	0x15, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x67,
+	0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65,
+	0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x14,
+	0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x66, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x10, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x66, 0x22, 0xbd, 0x01, 0x0a, 0x14, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x2c, 0x0a, 0x12, 0x72,
+	0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x12, 0x46, 0x0a, 0x06, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x67, 0x69, 0x74, 0x6c,
+	0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x74,
+	0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x73, 0x22, 0x5c, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x75, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x22,
+	0x76, 0x0a, 0x0d, 0x45, 0x78, 0x70, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x39, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x2a, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x56, 0x5a, 0x54, 0x67, 0x69, 0x74, 0x6c, 0x61,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d, 0x6f, 0x72, 0x67,
+	0x2f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x6d, 0x6f, 0x64, 0x75, 0x6c,
+	0x65, 0x2f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x72, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -221,24 +398,28 @@ func file_internal_module_agent_tracker_agent_tracker_proto_rawDescGZIP() []byte
 	return file_internal_module_agent_tracker_agent_tracker_proto_rawDescData
 }
 
-var file_internal_module_agent_tracker_agent_tracker_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_internal_module_agent_tracker_agent_tracker_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_internal_module_agent_tracker_agent_tracker_proto_goTypes = []interface{}{
-	(*ConnectedAgentInfo)(nil),  // 0: gitlab.agent.agent_tracker.ConnectedAgentInfo
-	(*ExpiringValue)(nil),       // 1: gitlab.agent.agent_tracker.ExpiringValue
-	(*modshared.AgentMeta)(nil), // 2: gitlab.agent.modshared.AgentMeta
-	(*timestamp.Timestamp)(nil), // 3: google.protobuf.Timestamp
-	(*any.Any)(nil),             // 4: google.protobuf.Any
+	(*ConnectedAgentInfo)(nil),   // 0: gitlab.agent.agent_tracker.ConnectedAgentInfo
+	(*AppliedConfiguration)(nil), // 1: gitlab.agent.agent_tracker.AppliedConfiguration
+	(*ConfigurationError)(nil),   // 2: gitlab.agent.agent_tracker.ConfigurationError
+	(*ExpiringValue)(nil),        // 3: gitlab.agent.agent_tracker.ExpiringValue
+	(*modshared.AgentMeta)(nil),  // 4: gitlab.agent.modshared.AgentMeta
+	(*timestamp.Timestamp)(nil),  // 5: google.protobuf.Timestamp
+	(*any.Any)(nil),              // 6: google.protobuf.Any
 }
 var file_internal_module_agent_tracker_agent_tracker_proto_depIdxs = []int32{
-	2, // 0: gitlab.agent.agent_tracker.ConnectedAgentInfo.agent_meta:type_name -> gitlab.agent.modshared.AgentMeta
-	3, // 1: gitlab.agent.agent_tracker.ConnectedAgentInfo.connected_at:type_name -> google.protobuf.Timestamp
-	3, // 2: gitlab.agent.agent_tracker.ExpiringValue.expires_at:type_name -> google.protobuf.Timestamp
-	4, // 3: gitlab.agent.agent_tracker.ExpiringValue.value:type_name -> google.protobuf.Any
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	4, // 0: gitlab.agent.agent_tracker.ConnectedAgentInfo.agent_meta:type_name -> gitlab.agent.modshared.AgentMeta
+	5, // 1: gitlab.agent.agent_tracker.ConnectedAgentInfo.connected_at:type_name -> google.protobuf.Timestamp
+	1, // 2: gitlab.agent.agent_tracker.ConnectedAgentInfo.applied_configuration:type_name -> gitlab.agent.agent_tracker.AppliedConfiguration
+	2, // 3: gitlab.agent.agent_tracker.AppliedConfiguration.errors:type_name -> gitlab.agent.agent_tracker.ConfigurationError
+	5, // 4: gitlab.agent.agent_tracker.ExpiringValue.expires_at:type_name -> google.protobuf.Timestamp
+	6, // 5: gitlab.agent.agent_tracker.ExpiringValue.value:type_name -> google.protobuf.Any
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_internal_module_agent_tracker_agent_tracker_proto_init() }
@@ -260,6 +441,30 @@ func file_internal_module_agent_tracker_agent_tracker_proto_init() {
 			}
 		}
 		file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AppliedConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigurationError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_agent_tracker_agent_tracker_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ExpiringValue); i {
 			case 0:
 				return &v.state
@@ -278,7 +483,7 @@ func file_internal_module_agent_tracker_agent_tracker_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_module_agent_tracker_agent_tracker_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
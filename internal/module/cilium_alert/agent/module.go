@@ -34,6 +34,11 @@ func (m *module) Run(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguratio
 }
 
 func (m *module) DefaultAndValidateConfiguration(cfg *agentcfg.AgentConfiguration) error {
+	return DefaultAndValidateConfiguration(cfg)
+}
+
+// DefaultAndValidateConfiguration is a no-op, the module has no configuration to default or validate.
+func DefaultAndValidateConfiguration(cfg *agentcfg.AgentConfiguration) error {
 	return nil
 }
 
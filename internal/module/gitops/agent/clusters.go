@@ -0,0 +1,176 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	defaultKubeconfigSecretKey = "kubeconfig"
+	// kubeconfigSecretCheckPeriod is how often the kubeconfig Secret is checked for changes.
+	kubeconfigSecretCheckPeriod = time.Minute
+)
+
+// remoteCluster is a cluster, other than the one agentk runs in, credentials for which are in a kubeconfig Secret.
+type remoteCluster struct {
+	cluster *agentcfg.ClusterCF
+	// kubeClient is the client for the cluster agentk runs in. It is used to read the Secret.
+	kubeClient      kubernetes.Interface
+	secretNamespace string
+	// checkPeriod is how often the Secret is checked for changes.
+	checkPeriod time.Duration
+}
+
+// clusterClients are clients for the cluster objects are synchronized into.
+type clusterClients struct {
+	restConfig    *rest.Config
+	clientGetter  *kubeconfigClientGetter
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+}
+
+// load reads the kubeconfig Secret and constructs clients for the cluster.
+// The kubeconfig file is returned too, to detect changes of the Secret.
+func (c *remoteCluster) load(ctx context.Context) (*clusterClients, []byte, error) {
+	kubeconfig, err := c.readKubeconfig(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	clients, err := newClusterClients(kubeconfig, c.cluster.Context)
+	if err != nil {
+		return nil, nil, err
+	}
+	return clients, kubeconfig, nil
+}
+
+// waitForChange polls the kubeconfig Secret until the kubeconfig file in it differs from the given one.
+// It returns false if ctx is done. Errors are logged and the Secret is polled again.
+func (c *remoteCluster) waitForChange(ctx context.Context, log *zap.Logger, kubeconfig []byte) bool {
+	err := retry.PollImmediateUntil(ctx, c.checkPeriod, func() (bool /*done*/, error) {
+		current, err := c.readKubeconfig(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warn("Failed to check cluster credentials for changes", zap.Error(err))
+			}
+			return false, nil // nil error to keep polling
+		}
+		return !bytes.Equal(current, kubeconfig), nil
+	})
+	return err == nil
+}
+
+func (c *remoteCluster) readKubeconfig(ctx context.Context) ([]byte, error) {
+	secret, err := c.kubeClient.CoreV1().Secrets(c.secretNamespace).Get(ctx, c.cluster.KubeconfigSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("kubeconfig Secret %s: %v", c.cluster.KubeconfigSecret, err)
+	}
+	data, ok := secret.Data[c.cluster.KubeconfigSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig Secret %s: key %s not found", c.cluster.KubeconfigSecret, c.cluster.KubeconfigSecretKey)
+	}
+	return data, nil
+}
+
+func newClusterClients(kubeconfig []byte, kubeContext string) (*clusterClients, error) {
+	rawConfig, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %v", err)
+	}
+	if err = validateKubeconfig(rawConfig, kubeContext); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %v", err)
+	}
+	clientConfig := clientcmd.NewNonInteractiveClientConfig(*rawConfig, kubeContext, &clientcmd.ConfigOverrides{}, nil)
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes.NewForConfig: %v", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic.NewForConfig: %v", err)
+	}
+	return &clusterClients{
+		restConfig: restConfig,
+		clientGetter: &kubeconfigClientGetter{
+			clientConfig: clientConfig,
+			restConfig:   restConfig,
+			discovery:    memory.NewMemCacheClient(kubeClient.Discovery()),
+		},
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+	}, nil
+}
+
+// validateKubeconfig rejects the cluster and the user of the context if they run commands or read files.
+// The kubeconfig comes from a Secret and must not make agentk execute programs or read files from its container.
+func validateKubeconfig(config *clientcmdapi.Config, kubeContext string) error {
+	if kubeContext == "" {
+		kubeContext = config.CurrentContext
+	}
+	kctx, ok := config.Contexts[kubeContext]
+	if !ok {
+		return nil // let clientcmd report it
+	}
+	if user, ok := config.AuthInfos[kctx.AuthInfo]; ok {
+		switch {
+		case user.Exec != nil:
+			return fmt.Errorf("user %s: exec credential plugins are not allowed", kctx.AuthInfo)
+		case user.AuthProvider != nil:
+			return fmt.Errorf("user %s: auth providers are not allowed", kctx.AuthInfo)
+		case user.TokenFile != "":
+			return fmt.Errorf("user %s: tokenFile is not allowed, use token", kctx.AuthInfo)
+		case user.ClientCertificate != "":
+			return fmt.Errorf("user %s: client-certificate is not allowed, use client-certificate-data", kctx.AuthInfo)
+		case user.ClientKey != "":
+			return fmt.Errorf("user %s: client-key is not allowed, use client-key-data", kctx.AuthInfo)
+		}
+	}
+	if cluster, ok := config.Clusters[kctx.Cluster]; ok {
+		if cluster.CertificateAuthority != "" {
+			return fmt.Errorf("cluster %s: certificate-authority is not allowed, use certificate-authority-data", kctx.Cluster)
+		}
+	}
+	return nil
+}
+
+// kubeconfigClientGetter is a resource.RESTClientGetter for a kubeconfig file.
+type kubeconfigClientGetter struct {
+	clientConfig clientcmd.ClientConfig
+	restConfig   *rest.Config
+	discovery    discovery.CachedDiscoveryInterface
+}
+
+func (g *kubeconfigClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.restConfig, nil
+}
+
+func (g *kubeconfigClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return g.discovery, nil
+}
+
+func (g *kubeconfigClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	return restmapper.NewDeferredDiscoveryRESTMapper(g.discovery), nil
+}
+
+func (g *kubeconfigClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return g.clientConfig
+}
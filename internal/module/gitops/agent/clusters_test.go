@@ -0,0 +1,242 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: edge1
+  cluster:
+    server: https://edge1.example.com:6443
+- name: edge2
+  cluster:
+    server: https://edge2.example.com:6443
+users:
+- name: agent
+  user:
+    token: secret-token
+contexts:
+- name: edge1
+  context:
+    cluster: edge1
+    user: agent
+- name: edge2
+  context:
+    cluster: edge2
+    user: agent
+current-context: edge1
+`
+)
+
+func TestRemoteClusterLoad(t *testing.T) {
+	tests := []struct {
+		name           string
+		context        string
+		expectedServer string
+	}{
+		{
+			name:           "current context",
+			expectedServer: "https://edge1.example.com:6443",
+		},
+		{
+			name:           "explicit context",
+			context:        "edge2",
+			expectedServer: "https://edge2.example.com:6443",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &remoteCluster{
+				cluster: &agentcfg.ClusterCF{
+					Name:                "edge",
+					KubeconfigSecret:    "edge-kubeconfig",
+					KubeconfigSecretKey: defaultKubeconfigSecretKey,
+					Context:             tc.context, // nolint: scopelint
+				},
+				kubeClient:      fake.NewSimpleClientset(kubeconfigSecret("edge-kubeconfig", testKubeconfig)),
+				secretNamespace: "agent-ns",
+			}
+			clients, kubeconfig, err := c.load(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, testKubeconfig, string(kubeconfig))
+			assert.Equal(t, tc.expectedServer, clients.restConfig.Host) // nolint: scopelint
+			assert.Equal(t, "secret-token", clients.restConfig.BearerToken)
+			restConfig, err := clients.clientGetter.ToRESTConfig()
+			require.NoError(t, err)
+			assert.Same(t, clients.restConfig, restConfig)
+		})
+	}
+}
+
+func TestRemoteClusterLoadErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      *corev1.Secret
+		context     string
+		expectedErr string
+	}{
+		{
+			name:        "no secret",
+			secret:      kubeconfigSecret("other", testKubeconfig),
+			expectedErr: `kubeconfig Secret edge-kubeconfig: secrets "edge-kubeconfig" not found`,
+		},
+		{
+			name: "no key",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "edge-kubeconfig",
+					Namespace: "agent-ns",
+				},
+			},
+			expectedErr: "kubeconfig Secret edge-kubeconfig: key kubeconfig not found",
+		},
+		{
+			name:        "exec",
+			secret:      kubeconfigSecret("edge-kubeconfig", kubeconfigWithUser("exec:\n      apiVersion: client.authentication.k8s.io/v1beta1\n      command: sh")),
+			expectedErr: "invalid kubeconfig: user agent: exec credential plugins are not allowed",
+		},
+		{
+			name:        "auth provider",
+			secret:      kubeconfigSecret("edge-kubeconfig", kubeconfigWithUser("auth-provider:\n      name: gcp")),
+			expectedErr: "invalid kubeconfig: user agent: auth providers are not allowed",
+		},
+		{
+			name:        "token file",
+			secret:      kubeconfigSecret("edge-kubeconfig", kubeconfigWithUser("tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token")),
+			expectedErr: "invalid kubeconfig: user agent: tokenFile is not allowed, use token",
+		},
+		{
+			name:        "client certificate file",
+			secret:      kubeconfigSecret("edge-kubeconfig", kubeconfigWithUser("client-certificate: /etc/cert.pem")),
+			expectedErr: "invalid kubeconfig: user agent: client-certificate is not allowed, use client-certificate-data",
+		},
+		{
+			name:        "client key file",
+			secret:      kubeconfigSecret("edge-kubeconfig", kubeconfigWithUser("client-key: /etc/key.pem")),
+			expectedErr: "invalid kubeconfig: user agent: client-key is not allowed, use client-key-data",
+		},
+		{
+			name: "CA file",
+			secret: kubeconfigSecret("edge-kubeconfig", `apiVersion: v1
+kind: Config
+clusters:
+- name: edge1
+  cluster:
+    server: https://edge1.example.com:6443
+    certificate-authority: /etc/ca.pem
+users:
+- name: agent
+  user:
+    token: secret-token
+contexts:
+- name: edge1
+  context:
+    cluster: edge1
+    user: agent
+current-context: edge1
+`),
+			expectedErr: "invalid kubeconfig: cluster edge1: certificate-authority is not allowed, use certificate-authority-data",
+		},
+		{
+			name:        "unknown context",
+			secret:      kubeconfigSecret("edge-kubeconfig", testKubeconfig),
+			context:     "edge3",
+			expectedErr: "invalid kubeconfig: invalid configuration: [context was not found for specified context: edge3, cluster has no server defined]",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &remoteCluster{
+				cluster: &agentcfg.ClusterCF{
+					Name:                "edge",
+					KubeconfigSecret:    "edge-kubeconfig",
+					KubeconfigSecretKey: defaultKubeconfigSecretKey,
+					Context:             tc.context, // nolint: scopelint
+				},
+				kubeClient:      fake.NewSimpleClientset(tc.secret), // nolint: scopelint
+				secretNamespace: "agent-ns",
+			}
+			_, _, err := c.load(context.Background())
+			assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+		})
+	}
+}
+
+func TestRemoteClusterWaitForChange(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(kubeconfigSecret("edge-kubeconfig", testKubeconfig))
+	c := &remoteCluster{
+		cluster: &agentcfg.ClusterCF{
+			Name:                "edge",
+			KubeconfigSecret:    "edge-kubeconfig",
+			KubeconfigSecretKey: defaultKubeconfigSecretKey,
+		},
+		kubeClient:      kubeClient,
+		secretNamespace: "agent-ns",
+		checkPeriod:     10 * time.Millisecond,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, kubeconfig, err := c.load(ctx)
+	require.NoError(t, err)
+	checks := 0
+	kubeClient.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		checks++
+		if checks == 3 { // credentials are rotated
+			return true, kubeconfigSecret("edge-kubeconfig", kubeconfigWithUser("token: new-token")), nil
+		}
+		return false, nil, nil
+	})
+	assert.True(t, c.waitForChange(ctx, zaptest.NewLogger(t), kubeconfig))
+	assert.Equal(t, 3, checks)
+
+	cancel()
+	assert.False(t, c.waitForChange(ctx, zaptest.NewLogger(t), kubeconfig))
+}
+
+// kubeconfigWithUser returns a kubeconfig file with the given fields of the user, indented by 4 spaces.
+func kubeconfigWithUser(user string) string {
+	return `apiVersion: v1
+kind: Config
+clusters:
+- name: edge1
+  cluster:
+    server: https://edge1.example.com:6443
+users:
+- name: agent
+  user:
+    ` + user + `
+contexts:
+- name: edge1
+  context:
+    cluster: edge1
+    user: agent
+current-context: edge1
+`
+}
+
+func kubeconfigSecret(name, kubeconfig string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "agent-ns",
+		},
+		Data: map[string][]byte{
+			defaultKubeconfigSecretKey: []byte(kubeconfig),
+		},
+	}
+}
@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDependsOnTimeout = 10 * time.Minute
+)
+
+// projectStates tracks which manifest projects from the configuration file are synchronized and healthy.
+// Workers of projects with dependencies use it to wait for their dependencies before synchronizing a commit.
+type projectStates struct {
+	mu     sync.Mutex
+	states map[string]projectState // project id -> state
+	// changed is closed and replaced each time the state of a project changes.
+	changed chan struct{}
+}
+
+type projectState struct {
+	ready bool
+	// reason is why the project is not ready, e.g. the phase or the health status.
+	reason string
+}
+
+func newProjectStates() *projectStates {
+	return &projectStates{
+		states:  make(map[string]projectState),
+		changed: make(chan struct{}),
+	}
+}
+
+// setReady records whether the project has been synchronized successfully and is healthy.
+// reason describes why the project is not ready and is ignored if it is ready.
+func (p *projectStates) setReady(projectId string, ready bool, reason string) {
+	if ready {
+		reason = ""
+	}
+	state := projectState{
+		ready:  ready,
+		reason: reason,
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if old, ok := p.states[projectId]; ok && old == state {
+		return
+	}
+	p.states[projectId] = state
+	p.notifyLocked()
+}
+
+// remove forgets the project. Projects that are not known are not ready.
+func (p *projectStates) remove(projectId string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.states[projectId]; !ok {
+		return
+	}
+	delete(p.states, projectId)
+	p.notifyLocked()
+}
+
+func (p *projectStates) notifyLocked() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// waitFor blocks until all the projects are ready, ctx is done or the timeout expires.
+// onWaiting is called with the projects that are not ready each time the set or their state changes.
+// Projects are described as "id (reason)". A *dependenciesNotReadyError is returned on timeout.
+func (p *projectStates) waitFor(ctx context.Context, projectIds []string, timeout time.Duration, onWaiting func(notReady []string)) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var lastNotReady []string
+	for {
+		p.mu.Lock()
+		notReady := p.notReadyLocked(projectIds)
+		changed := p.changed
+		p.mu.Unlock()
+		if len(notReady) == 0 {
+			return nil
+		}
+		if !stringSlicesEqual(notReady, lastNotReady) {
+			onWaiting(notReady)
+			lastNotReady = notReady
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return &dependenciesNotReadyError{
+				timeout:  timeout,
+				notReady: notReady,
+			}
+		case <-changed:
+		}
+	}
+}
+
+func (p *projectStates) notReadyLocked(projectIds []string) []string {
+	var notReady []string
+	for _, id := range projectIds {
+		state, ok := p.states[id]
+		switch {
+		case !ok:
+			notReady = append(notReady, id+" (not started)")
+		case !state.ready:
+			notReady = append(notReady, fmt.Sprintf("%s (%s)", id, state.reason))
+		}
+	}
+	return notReady
+}
+
+// dependenciesNotReadyError is returned when dependencies have not become ready in time.
+type dependenciesNotReadyError struct {
+	timeout  time.Duration
+	notReady []string
+}
+
+func (e *dependenciesNotReadyError) Error() string {
+	return fmt.Sprintf("dependencies are not ready after %s: %s", e.timeout, strings.Join(e.notReady, ", "))
+}
+
+func isStringInSlice(s string, slice []string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestProjectStatesWaitFor(t *testing.T) {
+	states := newProjectStates()
+	states.setReady("a", true, "")
+	states.setReady("c", false, "Progressing")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	waiting := make(chan []string, 10)
+	var wg wait.Group
+	defer wg.Wait()
+	var err error
+	wg.Start(func() {
+		err = states.waitFor(ctx, []string{"a", "b", "c"}, time.Minute, func(notReady []string) {
+			waiting <- notReady
+		})
+	})
+	assert.Equal(t, []string{"b (not started)", "c (Progressing)"}, <-waiting)
+	states.setReady("b", true, "")
+	assert.Equal(t, []string{"c (Progressing)"}, <-waiting)
+	states.setReady("b", true, "") // no change
+	states.setReady("c", true, "")
+	wg.Wait()
+	require.NoError(t, err)
+	assert.Empty(t, waiting)
+}
+
+func TestProjectStatesWaitForCanceled(t *testing.T) {
+	states := newProjectStates()
+	states.setReady("a", true, "")
+	states.remove("a")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var notReady []string
+	err := states.waitFor(ctx, []string{"a"}, time.Minute, func(n []string) {
+		notReady = n
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []string{"a (not started)"}, notReady)
+}
+
+func TestProjectStatesWaitForTimeout(t *testing.T) {
+	states := newProjectStates()
+	states.setReady("a", true, "")
+	states.setReady("b", false, "Progressing")
+	err := states.waitFor(context.Background(), []string{"a", "b"}, 10*time.Millisecond, func(n []string) {})
+	var notReadyErr *dependenciesNotReadyError
+	require.True(t, errors.As(err, &notReadyErr))
+	assert.EqualError(t, err, "dependencies are not ready after 10ms: b (Progressing)")
+}
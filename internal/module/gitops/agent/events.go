@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/cache"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// eventComponent is the source component of Kubernetes Events, emitted by the GitOps module.
+	eventComponent = "gitlab-agent"
+
+	eventReasonApplied    = "Applied"
+	eventReasonPruned     = "Pruned"
+	eventReasonSynced     = "Synced"
+	eventReasonSyncFailed = "SyncFailed"
+
+	// maxEventMessageLength is the maximum length of the message of an Event. Kubernetes limits it to 1024 bytes.
+	maxEventMessageLength = 1024
+)
+
+// objectEventRecorder emits Kubernetes Events for objects, changed by a synchronization.
+// All methods are safe to call on a nil instance, they are no-op then.
+type objectEventRecorder struct {
+	recorder record.EventRecorder
+	// objectsCache is where live objects are read from to reference them by UID. nil until the cluster cache has
+	// been created.
+	objectsCache liveObjectsCache
+	projectId    string
+}
+
+// setObjectsCache sets the cluster cache to read live objects from. Must be called before synchronization starts.
+func (r *objectEventRecorder) setObjectsCache(objectsCache liveObjectsCache) {
+	if r == nil {
+		return
+	}
+	r.objectsCache = objectsCache
+}
+
+// syncFinished emits an Event for each object that has been created, updated, pruned or failed to synchronize.
+// Objects that have not been changed are skipped to not flood the namespace with Events.
+func (r *objectEventRecorder) syncFinished(commitId string, results []common.ResourceSyncResult) {
+	if r == nil {
+		return
+	}
+	live := r.liveObjects(results)
+	for _, res := range results {
+		if res.HookType != "" {
+			continue
+		}
+		var eventType, reason, action string
+		switch res.Status {
+		case common.ResultCodeSynced:
+			if strings.HasSuffix(res.Message, " unchanged") {
+				continue
+			}
+			eventType, reason, action = corev1.EventTypeNormal, eventReasonApplied, "Applied"
+		case common.ResultCodePruned:
+			eventType, reason, action = corev1.EventTypeNormal, eventReasonPruned, "Pruned"
+		case common.ResultCodeSyncFailed:
+			eventType, reason, action = corev1.EventTypeWarning, eventReasonSyncFailed, "Failed to apply"
+		default: // prune skipped
+			continue
+		}
+		ref := r.reference(res, live[res.ResourceKey])
+		r.recorder.Event(ref, eventType, reason, eventMessage(fmt.Sprintf("%s by GitLab agent from project %s commit %s: %s", action, r.projectId, commitId, res.Message)))
+	}
+}
+
+// liveObjects reads the synchronized objects from the cluster cache. No requests are made to the API server.
+func (r *objectEventRecorder) liveObjects(results []common.ResourceSyncResult) map[kube.ResourceKey]*cache.Resource {
+	if r.objectsCache == nil {
+		return nil
+	}
+	wanted := make(map[kube.ResourceKey]struct{}, len(results))
+	for _, res := range results {
+		if res.HookType == "" && res.Status != common.ResultCodePruned {
+			wanted[res.ResourceKey] = struct{}{}
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+	return r.objectsCache.FindResources("", func(res *cache.Resource) bool {
+		_, ok := wanted[res.ResourceKey()]
+		return ok
+	})
+}
+
+// reference constructs a reference to the object for the Event.
+// The UID of the live object is included when the object is in the cluster cache so that `kubectl describe` shows
+// the Event. Objects that are not cached yet, e.g. because the watch has not seen a new object, are referenced
+// without the UID.
+func (r *objectEventRecorder) reference(res common.ResourceSyncResult, live *cache.Resource) *corev1.ObjectReference {
+	ref := objectReference(res.ResourceKey, res.Version)
+	if res.Status == common.ResultCodePruned || live == nil {
+		return ref
+	}
+	ref.APIVersion = live.Ref.APIVersion
+	ref.UID = live.Ref.UID
+	ref.ResourceVersion = live.ResourceVersion
+	return ref
+}
+
+func objectReference(key kube.ResourceKey, version string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:       key.Kind,
+		APIVersion: schema.GroupVersion{Group: key.Group, Version: version}.String(),
+		Namespace:  key.Namespace,
+		Name:       key.Name,
+	}
+}
+
+func eventMessage(msg string) string {
+	if len(msg) > maxEventMessageLength {
+		return msg[:maxEventMessageLength]
+	}
+	return msg
+}
@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestObjectEventRecorder(t *testing.T) {
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetNamespace("ns")
+	cm.SetName("cm")
+	cm.SetUID("uid1")
+	recorder := record.NewFakeRecorder(10)
+	r := &objectEventRecorder{
+		recorder:     recorder,
+		objectsCache: newFakeLiveObjectsCache(cm),
+		projectId:    "group/project",
+	}
+	r.syncFinished(gitHash1, []common.ResourceSyncResult{
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "cm"),
+			Version:     "v1",
+			Status:      common.ResultCodeSynced,
+			Message:     "configmap/cm configured",
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "same"),
+			Version:     "v1",
+			Status:      common.ResultCodeSynced,
+			Message:     "configmap/same unchanged",
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "old"),
+			Version:     "v1",
+			Status:      common.ResultCodePruned,
+			Message:     "pruned",
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "bad"),
+			Version:     "v1",
+			Status:      common.ResultCodeSyncFailed,
+			Message:     "invalid",
+		},
+	})
+	close(recorder.Events)
+	var events []string
+	for e := range recorder.Events {
+		events = append(events, e)
+	}
+	assert.Equal(t, []string{
+		"Normal Applied Applied by GitLab agent from project group/project commit " + gitHash1 + ": configmap/cm configured",
+		"Normal Pruned Pruned by GitLab agent from project group/project commit " + gitHash1 + ": pruned",
+		"Warning SyncFailed Failed to apply by GitLab agent from project group/project commit " + gitHash1 + ": invalid",
+	}, events)
+}
+
+func TestObjectEventRecorderReference(t *testing.T) {
+	cm := &unstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetNamespace("ns")
+	cm.SetName("cm")
+	cm.SetUID("uid1")
+	cm.SetResourceVersion("5")
+	r := &objectEventRecorder{
+		objectsCache: newFakeLiveObjectsCache(cm),
+	}
+	results := []common.ResourceSyncResult{
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "cm"),
+			Version:     "v1",
+			Status:      common.ResultCodeSynced,
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "new"),
+			Version:     "v1",
+			Status:      common.ResultCodeSynced,
+		},
+		{
+			ResourceKey: kube.NewResourceKey("apps", "Deployment", "ns", "app"),
+			Version:     "v1",
+			Status:      common.ResultCodePruned,
+		},
+	}
+	live := r.liveObjects(results)
+	assert.Len(t, live, 1)
+	ref := r.reference(results[0], live[results[0].ResourceKey])
+	assert.Equal(t, &corev1.ObjectReference{
+		Kind:            "ConfigMap",
+		APIVersion:      "v1",
+		Namespace:       "ns",
+		Name:            "cm",
+		UID:             "uid1",
+		ResourceVersion: "5",
+	}, ref)
+
+	// not cached yet
+	ref = r.reference(results[1], live[results[1].ResourceKey])
+	assert.Equal(t, &corev1.ObjectReference{
+		Kind:       "ConfigMap",
+		APIVersion: "v1",
+		Namespace:  "ns",
+		Name:       "new",
+	}, ref)
+
+	ref = r.reference(results[2], live[results[2].ResourceKey])
+	assert.Equal(t, &corev1.ObjectReference{
+		Kind:       "Deployment",
+		APIVersion: "apps/v1",
+		Namespace:  "ns",
+		Name:       "app",
+	}, ref)
+}
+
+func TestObjectEventRecorderNil(t *testing.T) {
+	var r *objectEventRecorder
+	r.syncFinished(gitHash1, []common.ResourceSyncResult{
+		{
+			Status: common.ResultCodeSynced,
+		},
+	})
+}
@@ -2,11 +2,18 @@ package agent
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modagent"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 )
 
 type Factory struct {
@@ -18,17 +25,62 @@ func (f *Factory) New(config *modagent.Config) (modagent.Module, error) {
 	if err != nil {
 		return nil, fmt.Errorf("ToRESTConfig: %v", err)
 	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes.NewForConfig: %v", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("dynamic.NewForConfig: %v", err)
+	}
+	restMapper, err := config.K8sClientGetter.ToRESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("ToRESTMapper: %v", err)
+	}
+	eventBroadcaster := record.NewBroadcaster()
+	metrics := newGitopsMetrics()
 	return &module{
-		log: config.Log,
+		log:              config.Log,
+		eventBroadcaster: eventBroadcaster,
+		eventSink: &typedcorev1.EventSinkImpl{
+			Interface: kubeClient.CoreV1().Events(""),
+		},
+		metrics:    metrics,
+		registerer: config.Registerer,
 		workerFactory: &defaultGitopsWorkerFactory{
-			log: config.Log,
-			engineFactory: &defaultGitopsEngineFactory{
-				kubeClientConfig: restConfig,
+			log:             config.Log,
+			engineFactory:   &defaultGitopsEngineFactory{},
+			restConfig:      restConfig,
+			k8sClientGetter: config.K8sClientGetter,
+			kubeClient:      kubeClient,
+			dynamicClient:   dynamicClient,
+			restMapper:      restMapper,
+			projectStates:   newProjectStates(),
+			metrics:         metrics,
+			eventRecorder: eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{
+				Component: eventComponent,
+			}),
+			agentMeta:      config.AgentMeta,
+			agentNamespace: config.AgentMeta.PodNamespace,
+			httpClient: &http.Client{
+				Transport: &http.Transport{
+					Proxy:                 http.ProxyFromEnvironment,
+					MaxIdleConns:          10,
+					IdleConnTimeout:       90 * time.Second,
+					TLSHandshakeTimeout:   10 * time.Second,
+					ResponseHeaderTimeout: 20 * time.Second,
+					ExpectContinueTimeout: 1 * time.Second,
+				},
 			},
-			k8sClientGetter:                    config.K8sClientGetter,
 			getObjectsToSynchronizeRetryPeriod: f.GetObjectsToSynchronizeRetryPeriod,
 			gitopsClient:                       rpc.NewGitopsClient(config.KasConn),
 		},
+		manifestProjectWatcher: &manifestProjectWatcher{
+			log:            config.Log,
+			dynamicClient:  dynamicClient,
+			discovery:      kubeClient.Discovery(),
+			apiCheckPeriod: manifestProjectAPICheckPeriod,
+		},
 	}, nil
 }
 
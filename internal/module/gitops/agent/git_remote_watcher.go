@@ -0,0 +1,328 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultGitRemotePollPeriod = 20 * time.Second
+	// gitRemoteListTimeout bounds listing references of remotes that don't support Git protocol v2. go-git cannot
+	// cancel it.
+	gitRemoteListTimeout = time.Minute
+
+	gitRemoteDefaultSSHUser = "git"
+
+	secretKeyUsername   = "username"
+	secretKeyPassword   = "password"
+	secretKeyIdentity   = "identity"
+	secretKeyKnownHosts = "known_hosts"
+)
+
+// gitRemoteWatcher fetches manifests directly from a Git repository, bypassing gitlab-kas.
+// It implements rpc.ObjectsToSynchronizeWatcherInterface.
+type gitRemoteWatcher struct {
+	log        *zap.Logger
+	remote     *agentcfg.GitRemoteCF
+	httpClient *http.Client
+	kubeClient kubernetes.Interface
+	// secretNamespace is the namespace to read the credentials Secret from.
+	secretNamespace string
+	pollPeriod      time.Duration
+
+	// clone is the last shallow clone of the remote, made if it does not support partial clones. nil if there is none.
+	clone *git.Repository
+	// cloneHash is the commit clone has been made at.
+	cloneHash plumbing.Hash
+}
+
+func (w *gitRemoteWatcher) Watch(ctx context.Context, req *rpc.ObjectsToSynchronizeRequest, callback rpc.ObjectsToSynchronizeCallback) error {
+	var lastRefHash plumbing.Hash
+	retry.JitterUntil(ctx, w.pollPeriod, func(ctx context.Context) {
+		auth, err := w.auth(ctx)
+		if err != nil {
+			w.log.Error("Failed to get credentials for Git repository", zap.Error(err))
+			return
+		}
+		refName, refHash, err := w.resolveRef(ctx, auth)
+		if err != nil {
+			w.log.Error("Failed to list references of Git repository", zap.Error(err))
+			return
+		}
+		if refHash == lastRefHash {
+			w.log.Debug("Git repository: no updates")
+			return
+		}
+		objs, err := w.fetch(ctx, auth, refName, refHash, req.Paths)
+		if err != nil {
+			w.log.Error("Failed to fetch manifests from Git repository", zap.Error(err))
+			return
+		}
+		callback(ctx, objs)
+		lastRefHash = refHash
+	})
+	return nil
+}
+
+// fetch fetches manifests from the commit. Only the wanted files are fetched if the remote supports partial clones
+// over Git protocol v2. Otherwise, a shallow clone of the whole reference is made. The clone is kept so that it is
+// only made once for each commit.
+func (w *gitRemoteWatcher) fetch(ctx context.Context, auth transport.AuthMethod, refName plumbing.ReferenceName, refHash plumbing.Hash, paths []*agentcfg.PathCF) (rpc.ObjectsToSynchronizeData, error) {
+	up, err := newGitUploadPackV2(w.remote.Url, auth, w.httpClient)
+	switch {
+	case err == nil:
+		objs, err := sparseFetch(ctx, up, refHash, paths) // nolint: govet
+		if !isProtocolNotSupported(err) {
+			return objs, err
+		}
+	case !isProtocolNotSupported(err):
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	if w.clone != nil && w.cloneHash == refHash {
+		return objectsFromCommit(w.clone, refHash, paths)
+	}
+	w.log.Debug("Git repository does not support partial clone, cloning all files")
+	w.clone = nil // release memory before cloning
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           w.remote.Url,
+		Auth:          auth,
+		ReferenceName: refName,
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags,
+	})
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	w.clone = repo
+	w.cloneHash = head.Hash()
+	return objectsFromCommit(repo, head.Hash(), paths)
+}
+
+// resolveRef finds the reference to synchronize and the hash it points to.
+func (w *gitRemoteWatcher) resolveRef(ctx context.Context, auth transport.AuthMethod) (plumbing.ReferenceName, plumbing.Hash, error) {
+	refs, err := w.listRefs(ctx, auth)
+	if err != nil {
+		return "", plumbing.ZeroHash, err
+	}
+	return findRef(refs, w.remote.Ref)
+}
+
+// listRefs lists references over Git protocol v2, which can be canceled using ctx. go-git is used if the remote
+// does not support it. go-git does not accept a context so the call is abandoned when ctx is done or the timeout
+// elapses. It finishes in the background then.
+func (w *gitRemoteWatcher) listRefs(ctx context.Context, auth transport.AuthMethod) ([]*plumbing.Reference, error) {
+	up, err := newGitUploadPackV2(w.remote.Url, auth, w.httpClient)
+	switch {
+	case err == nil:
+		refs, err := listRefs(ctx, up) // nolint: govet
+		if !isProtocolNotSupported(err) {
+			return refs, err
+		}
+	case !isProtocolNotSupported(err):
+		return nil, err
+	}
+	type result struct {
+		refs []*plumbing.Reference
+		err  error
+	}
+	res := make(chan result, 1) // buffered so that the goroutine can exit when nobody is waiting for it
+	go func() {
+		remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{
+			Name: git.DefaultRemoteName,
+			URLs: []string{w.remote.Url},
+		})
+		refs, err := remote.List(&git.ListOptions{ // nolint: govet
+			Auth: auth,
+		})
+		res <- result{refs: refs, err: err}
+	}()
+	timer := time.NewTimer(gitRemoteListTimeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, fmt.Errorf("listing references timed out after %s", gitRemoteListTimeout)
+	case r := <-res:
+		return r.refs, r.err
+	}
+}
+
+// isProtocolNotSupported returns true if err means that go-git has to be used instead of Git protocol v2.
+func isProtocolNotSupported(err error) bool {
+	return errors.Is(err, errGitProtocolV2NotSupported) || errors.Is(err, errPartialCloneNotSupported)
+}
+
+func (w *gitRemoteWatcher) auth(ctx context.Context) (transport.AuthMethod, error) {
+	if w.remote.CredentialsSecret == "" {
+		return nil, nil
+	}
+	secret, err := w.kubeClient.CoreV1().Secrets(w.secretNamespace).Get(ctx, w.remote.CredentialsSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := transport.NewEndpoint(w.remote.Url)
+	if err != nil {
+		return nil, err
+	}
+	switch endpoint.Protocol {
+	case "ssh":
+		identity := secret.Data[secretKeyIdentity]
+		if len(identity) == 0 {
+			return nil, fmt.Errorf("secret %s has no %q key", w.remote.CredentialsSecret, secretKeyIdentity)
+		}
+		user := endpoint.User
+		if user == "" {
+			user = gitRemoteDefaultSSHUser
+		}
+		auth, err := gitssh.NewPublicKeys(user, identity, "")
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: invalid %q: %v", w.remote.CredentialsSecret, secretKeyIdentity, err)
+		}
+		auth.HostKeyCallback, err = knownHostsCallback(secret.Data[secretKeyKnownHosts])
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: invalid %q: %v", w.remote.CredentialsSecret, secretKeyKnownHosts, err)
+		}
+		return auth, nil
+	default:
+		return &githttp.BasicAuth{
+			Username: string(secret.Data[secretKeyUsername]),
+			Password: string(secret.Data[secretKeyPassword]),
+		}, nil
+	}
+}
+
+// findRef finds the named branch or tag in the list of references.
+// If the name is empty, the reference HEAD points to is returned.
+func findRef(refs []*plumbing.Reference, name string) (plumbing.ReferenceName, plumbing.Hash, error) {
+	byName := make(map[plumbing.ReferenceName]*plumbing.Reference, len(refs))
+	for _, ref := range refs {
+		byName[ref.Name()] = ref
+	}
+	var wanted *plumbing.Reference
+	if name == "" {
+		wanted = byName[plumbing.HEAD]
+		if wanted == nil {
+			return "", plumbing.ZeroHash, errors.New("default branch not found")
+		}
+		if wanted.Type() == plumbing.SymbolicReference {
+			wanted = byName[wanted.Target()]
+			if wanted == nil {
+				return "", plumbing.ZeroHash, errors.New("default branch not found")
+			}
+		}
+	} else {
+		wanted = byName[plumbing.NewBranchReferenceName(name)]
+		if wanted == nil {
+			wanted = byName[plumbing.NewTagReferenceName(name)]
+		}
+		if wanted == nil {
+			return "", plumbing.ZeroHash, fmt.Errorf("ref %q not found", name)
+		}
+	}
+	return wanted.Name(), wanted.Hash(), nil
+}
+
+// objectsFromCommit reads manifest files from the commit's tree.
+func objectsFromCommit(repo *git.Repository, commitHash plumbing.Hash, paths []*agentcfg.PathCF) (rpc.ObjectsToSynchronizeData, error) {
+	commit, err := repo.CommitObject(commitHash)
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	c := newManifestCollector(paths)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		wanted, err := c.Wants(f.Name, f.Size)
+		if err != nil || !wanted {
+			return err
+		}
+		data, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		c.Add(f.Name, []byte(data))
+		return nil
+	})
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	return rpc.ObjectsToSynchronizeData{
+		CommitId: commitHash.String(),
+		Sources:  c.sources,
+	}, nil
+}
+
+type knownHost struct {
+	hosts []string
+	key   ssh.PublicKey
+}
+
+// knownHostsCallback constructs a callback that only accepts host keys listed in the known_hosts data.
+// Hashed host names are not supported.
+func knownHostsCallback(data []byte) (ssh.HostKeyCallback, error) {
+	var knownHosts []knownHost
+	for len(data) > 0 {
+		var (
+			hosts []string
+			key   ssh.PublicKey
+			err   error
+		)
+		_, hosts, key, _, data, err = ssh.ParseKnownHosts(data)
+		if err != nil {
+			if errors.Is(err, io.EOF) { // only comments and empty lines left
+				break
+			}
+			return nil, err
+		}
+		knownHosts = append(knownHosts, knownHost{
+			hosts: hosts,
+			key:   key,
+		})
+	}
+	if len(knownHosts) == 0 {
+		return nil, errors.New("no known hosts")
+	}
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		normalized := knownhosts.Normalize(hostname)
+		keyBytes := key.Marshal()
+		for _, kh := range knownHosts {
+			for _, host := range kh.hosts {
+				if knownhosts.Normalize(host) == normalized && bytes.Equal(kh.key.Marshal(), keyBytes) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("host key for %s is not in known hosts", hostname)
+	}, nil
+}
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	gitHash1 = "507ebc6de9bcac25628aa7afd52802a91a0685d8"
+	gitHash2 = "28aa7afd52802a91a0685d8507ebc6de9bcac256"
+)
+
+func TestFindRef(t *testing.T) {
+	refs := []*plumbing.Reference{
+		plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main")),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), plumbing.NewHash(gitHash1)),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.0.0"), plumbing.NewHash(gitHash2)),
+	}
+	t.Run("default branch", func(t *testing.T) {
+		name, hash, err := findRef(refs, "")
+		require.NoError(t, err)
+		assert.Equal(t, plumbing.NewBranchReferenceName("main"), name)
+		assert.Equal(t, gitHash1, hash.String())
+	})
+	t.Run("branch", func(t *testing.T) {
+		name, hash, err := findRef(refs, "main")
+		require.NoError(t, err)
+		assert.Equal(t, plumbing.NewBranchReferenceName("main"), name)
+		assert.Equal(t, gitHash1, hash.String())
+	})
+	t.Run("tag", func(t *testing.T) {
+		name, hash, err := findRef(refs, "v1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, plumbing.NewTagReferenceName("v1.0.0"), name)
+		assert.Equal(t, gitHash2, hash.String())
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, _, err := findRef(refs, "bla")
+		assert.EqualError(t, err, `ref "bla" not found`)
+	})
+}
+
+func TestGitRemoteWatcherListRefsIsCanceled(t *testing.T) {
+	// The remote accepts connections, but never responds.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close() // nolint: errcheck
+	conns := make(chan net.Conn, 1)
+	go func() {
+		conn, err := l.Accept() // nolint: govet
+		if err == nil {
+			conns <- conn
+		}
+	}()
+	defer func() {
+		select {
+		case conn := <-conns:
+			_ = conn.Close() // unblock go-git
+		default:
+		}
+	}()
+	w := &gitRemoteWatcher{
+		remote: &agentcfg.GitRemoteCF{
+			Url: "http://" + l.Addr().String() + "/repo.git", // not https, go-git is used
+		},
+		httpClient: http.DefaultClient,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = w.listRefs(ctx, nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestObjectsFromCommit(t *testing.T) {
+	repo, commitHash := newTestGitRepo(t)
+
+	objs, err := objectsFromCommit(repo, commitHash, []*agentcfg.PathCF{
+		{
+			Glob: defaultGitOpsManifestPathGlob,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, commitHash.String(), objs.CommitId)
+	assert.ElementsMatch(t, []rpc.ObjectSource{
+		{
+			Name: "manifest.yaml",
+			Data: []byte("a: b"),
+		},
+		{
+			Name: "dir/manifest.yml",
+			Data: []byte("c: d"),
+		},
+	}, objs.Sources)
+
+	objs, err = objectsFromCommit(repo, commitHash, []*agentcfg.PathCF{
+		{
+			Glob: "/*.yaml",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []rpc.ObjectSource{
+		{
+			Name: "manifest.yaml",
+			Data: []byte("a: b"),
+		},
+	}, objs.Sources)
+}
+
+// newTestGitRepo creates an in-memory repository with a single commit.
+func newTestGitRepo(t *testing.T) (*git.Repository, plumbing.Hash) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	files := map[string]string{
+		"manifest.yaml":        "a: b",
+		"dir/manifest.yml":     "c: d",
+		"dir/readme.txt":       "bla",
+		".hidden/manifest.yml": "e: f",
+	}
+	for name, data := range files {
+		f, err := wt.Filesystem.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		_, err = wt.Add(name)
+		require.NoError(t, err)
+	}
+	commitHash, err := wt.Commit("commit", &git.CommitOptions{
+		Author: &object.Signature{
+			Name: "test",
+			When: time.Now(),
+		},
+	})
+	require.NoError(t, err)
+	return repo, commitHash
+}
+
+func TestKnownHostsCallback(t *testing.T) {
+	key1 := newSSHPublicKey(t)
+	key2 := newSSHPublicKey(t)
+	knownHosts := "# comment\n" + knownHostsLine("gitea.example.com", key1) + knownHostsLine("[ssh.example.com]:2222", key2)
+	cb, err := knownHostsCallback([]byte(knownHosts))
+	require.NoError(t, err)
+	addr := &net.TCPAddr{}
+	assert.NoError(t, cb("gitea.example.com:22", addr, key1))
+	assert.NoError(t, cb("ssh.example.com:2222", addr, key2))
+	assert.EqualError(t, cb("gitea.example.com:22", addr, key2), "host key for gitea.example.com:22 is not in known hosts")
+	assert.EqualError(t, cb("github.com:22", addr, key1), "host key for github.com:22 is not in known hosts")
+
+	_, err = knownHostsCallback([]byte("# only a comment\n"))
+	assert.EqualError(t, err, "no known hosts")
+}
+
+func newSSHPublicKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return key
+}
+
+func knownHostsLine(host string, key ssh.PublicKey) string {
+	return host + " " + string(ssh.MarshalAuthorizedKey(key))
+}
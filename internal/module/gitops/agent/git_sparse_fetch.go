@@ -0,0 +1,639 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"golang.org/x/crypto/ssh"
+)
+
+// This file implements a sparse fetch of a single commit using a partial clone over Git protocol v2.
+// See https://git-scm.com/docs/protocol-v2 and https://git-scm.com/docs/partial-clone.
+// go-git does not support partial clones, hence the protocol is spoken directly.
+
+const (
+	gitProtocolHeader  = "Git-Protocol"
+	gitProtocolEnvVar  = "GIT_PROTOCOL"
+	gitProtocolVersion = "version=2"
+
+	// maxGitPackSize is the maximum size of a packfile received from a Git remote.
+	maxGitPackSize = 64 * 1024 * 1024
+
+	pktLineFlush       = "0000"
+	pktLineDelim       = "0001"
+	pktLineResponseEnd = "0002"
+
+	sidebandData     = 1
+	sidebandProgress = 2
+	sidebandError    = 3
+)
+
+var (
+	// errGitProtocolV2NotSupported is returned if the remote, the URL or the authentication method does not support
+	// Git protocol v2.
+	errGitProtocolV2NotSupported = errors.New("remote does not support Git protocol v2")
+	// errPartialCloneNotSupported is returned if the remote does not support partial clones over Git protocol v2.
+	errPartialCloneNotSupported = errors.New("remote does not support partial clone over Git protocol v2")
+	errPktLineSpecial           = errors.New("special pkt-line")
+)
+
+// gitUploadPackV2 sends Git protocol v2 commands to the upload-pack service of a remote repository.
+type gitUploadPackV2 interface {
+	// capabilities returns the capabilities advertised by the remote.
+	// errGitProtocolV2NotSupported is returned if the remote does not support protocol v2.
+	capabilities(ctx context.Context) ([]string, error)
+	// command sends a command request and returns the response. The caller must close the response.
+	command(ctx context.Context, request []byte) (io.ReadCloser, error)
+}
+
+// newGitUploadPackV2 returns errGitProtocolV2NotSupported if the URL or the authentication method is not supported.
+// httpClient is used for HTTPS remotes.
+func newGitUploadPackV2(url string, auth transport.AuthMethod, httpClient *http.Client) (gitUploadPackV2, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, err
+	}
+	switch endpoint.Protocol {
+	case "https":
+		up := &httpUploadPack{
+			url:    strings.TrimSuffix(url, "/"),
+			client: httpClient,
+		}
+		switch a := auth.(type) {
+		case nil:
+		case *githttp.BasicAuth:
+			up.auth = a
+		default:
+			return nil, errGitProtocolV2NotSupported
+		}
+		return up, nil
+	case "ssh":
+		a, ok := auth.(*gitssh.PublicKeys)
+		if !ok {
+			return nil, errGitProtocolV2NotSupported
+		}
+		config, err := a.ClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		port := endpoint.Port
+		if port == 0 {
+			port = 22
+		}
+		return &sshUploadPack{
+			address: net.JoinHostPort(endpoint.Host, strconv.Itoa(port)),
+			path:    endpoint.Path,
+			config:  config,
+		}, nil
+	default:
+		return nil, errGitProtocolV2NotSupported
+	}
+}
+
+// listRefs lists branches, tags and HEAD of the remote using the ls-refs command.
+// Tags are peeled so that they point to commits.
+func listRefs(ctx context.Context, up gitUploadPackV2) (retRefs []*plumbing.Reference, retErr error) {
+	caps, err := up.capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !hasCapability(caps, "ls-refs") {
+		return nil, errGitProtocolV2NotSupported
+	}
+	var req bytes.Buffer
+	writePktLine(&req, "command=ls-refs\n")
+	req.WriteString(pktLineDelim)
+	writePktLine(&req, "symrefs\n")
+	writePktLine(&req, "peel\n")
+	writePktLine(&req, "ref-prefix HEAD\n")
+	writePktLine(&req, "ref-prefix refs/heads/\n")
+	writePktLine(&req, "ref-prefix refs/tags/\n")
+	req.WriteString(pktLineFlush)
+
+	resp, err := up.command(ctx, req.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := resp.Close() // nolint: govet
+		if retErr == nil {
+			retErr = err
+		}
+	}()
+	return readRefs(bufio.NewReader(resp))
+}
+
+// readRefs reads an ls-refs response.
+func readRefs(r io.Reader) ([]*plumbing.Reference, error) {
+	var refs []*plumbing.Reference
+	for {
+		line, err := readPktLine(r)
+		switch {
+		case err == nil:
+		case errors.Is(err, errPktLineSpecial):
+			if string(line) == pktLineFlush {
+				return refs, nil
+			}
+			return nil, fmt.Errorf("unexpected pkt-line %s in ls-refs response", line)
+		default:
+			return nil, err
+		}
+		s := strings.TrimSuffix(string(line), "\n")
+		if msg, ok := strictCut(s, "ERR "); ok {
+			return nil, fmt.Errorf("remote error: %s", msg)
+		}
+		fields := strings.Fields(s)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid ls-refs line %q", s)
+		}
+		hash := plumbing.NewHash(fields[0])
+		name := plumbing.ReferenceName(fields[1])
+		for _, attr := range fields[2:] {
+			if target, ok := strictCut(attr, "symref-target:"); ok && name == plumbing.HEAD {
+				refs = append(refs, plumbing.NewSymbolicReference(name, plumbing.ReferenceName(target)))
+				name = "" // the symbolic reference replaces the hash reference
+			}
+			if peeled, ok := strictCut(attr, "peeled:"); ok {
+				hash = plumbing.NewHash(peeled)
+			}
+		}
+		if name != "" {
+			refs = append(refs, plumbing.NewHashReference(name, hash))
+		}
+	}
+}
+
+func hasCapability(caps []string, name string) bool {
+	for _, c := range caps {
+		if c == name || strings.HasPrefix(c, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// sparseFetch fetches the commit and only the files from it that match paths.
+// The commit and its trees are fetched first, without any blobs. Then only the blobs of the wanted files are fetched.
+func sparseFetch(ctx context.Context, up gitUploadPackV2, commitHash plumbing.Hash, paths []*agentcfg.PathCF) (rpc.ObjectsToSynchronizeData, error) {
+	caps, err := up.capabilities(ctx)
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	if !fetchSupportsPartialClone(caps) {
+		return rpc.ObjectsToSynchronizeData{}, errPartialCloneNotSupported
+	}
+	storage := memory.NewStorage()
+	err = fetchPack(ctx, up, storage, []plumbing.Hash{commitHash}, true)
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, fmt.Errorf("fetch commit: %w", err)
+	}
+	commit, err := object.GetCommit(storage, commitHash)
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	c := newManifestCollector(paths)
+	files, err := wantedFiles(c, tree)
+	if err != nil {
+		return rpc.ObjectsToSynchronizeData{}, err
+	}
+	if len(files) > 0 {
+		blobs := make([]plumbing.Hash, 0, len(files))
+		seen := make(map[plumbing.Hash]struct{}, len(files))
+		for _, f := range files {
+			if _, ok := seen[f.Hash]; ok {
+				continue
+			}
+			seen[f.Hash] = struct{}{}
+			blobs = append(blobs, f.Hash)
+		}
+		err = fetchPack(ctx, up, storage, blobs, false)
+		if err != nil {
+			return rpc.ObjectsToSynchronizeData{}, fmt.Errorf("fetch files: %w", err)
+		}
+	}
+	for _, f := range files {
+		blob, err := object.GetBlob(storage, f.Hash)
+		if err != nil {
+			return rpc.ObjectsToSynchronizeData{}, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if _, err = c.Wants(f.Name, blob.Size); err != nil { // check size limits now that the size is known
+			return rpc.ObjectsToSynchronizeData{}, err
+		}
+		data, err := readBlob(blob)
+		if err != nil {
+			return rpc.ObjectsToSynchronizeData{}, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		c.Add(f.Name, data)
+	}
+	return rpc.ObjectsToSynchronizeData{
+		CommitId: commitHash.String(),
+		Sources:  c.sources,
+	}, nil
+}
+
+// wantedFiles walks the tree and returns the files that c wants. Blobs are not accessed.
+func wantedFiles(c *manifestCollector, tree *object.Tree) ([]object.TreeEntry, error) {
+	var files []object.TreeEntry
+	w := object.NewTreeWalker(tree, true, nil)
+	defer w.Close()
+	for {
+		name, entry, err := w.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return files, nil
+			}
+			return nil, err
+		}
+		if !entry.Mode.IsFile() {
+			continue
+		}
+		wanted, err := c.Wants(name, 0) // size is checked once the blob has been fetched
+		if err != nil {
+			return nil, err
+		}
+		if !wanted {
+			continue
+		}
+		if len(files) == maxNumberOfManifestFiles {
+			return nil, fmt.Errorf("maximum number of manifest files limit reached: %d", maxNumberOfManifestFiles)
+		}
+		entry.Name = name // full path
+		files = append(files, entry)
+	}
+}
+
+func readBlob(blob *object.Blob) ([]byte, error) {
+	r, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() // nolint: errcheck
+	return ioutil.ReadAll(r)
+}
+
+// fetchSupportsPartialClone checks if the fetch command supports the features needed for a partial shallow clone.
+func fetchSupportsPartialClone(caps []string) bool {
+	for _, c := range caps {
+		features, ok := strictCut(c, "fetch=")
+		if !ok {
+			continue
+		}
+		var shallow, filter bool
+		for _, f := range strings.Fields(features) {
+			switch f {
+			case "shallow":
+				shallow = true
+			case "filter":
+				filter = true
+			}
+		}
+		return shallow && filter
+	}
+	return false
+}
+
+// fetchPack fetches the wanted objects into storage.
+// If commitOnly is true, wanted objects must be commits and only the commits and their trees are fetched, without
+// history and blobs. Otherwise, only the wanted objects are fetched.
+func fetchPack(ctx context.Context, up gitUploadPackV2, storage *memory.Storage, wants []plumbing.Hash, commitOnly bool) (retErr error) {
+	var req bytes.Buffer
+	writePktLine(&req, "command=fetch\n")
+	req.WriteString(pktLineDelim)
+	for _, want := range wants {
+		writePktLine(&req, "want "+want.String()+"\n")
+	}
+	if commitOnly {
+		writePktLine(&req, "deepen 1\n")
+		writePktLine(&req, "filter blob:none\n")
+	}
+	writePktLine(&req, "no-progress\n")
+	writePktLine(&req, "done\n")
+	req.WriteString(pktLineFlush)
+
+	resp, err := up.command(ctx, req.Bytes())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := resp.Close() // nolint: govet
+		if retErr == nil {
+			retErr = err
+		}
+	}()
+	var pack bytes.Buffer
+	err = readPackfile(bufio.NewReader(resp), &limitedWriter{w: &pack, remaining: maxGitPackSize})
+	if err != nil {
+		return err
+	}
+	return packfile.UpdateObjectStorage(storage, &pack)
+}
+
+// readPackfile reads a fetch response and writes the packfile from it into w.
+func readPackfile(r io.Reader, w io.Writer) error {
+	for {
+		line, err := readPktLine(r)
+		switch {
+		case err == nil:
+		case errors.Is(err, errPktLineSpecial):
+			if string(line) == pktLineFlush {
+				return errors.New("no packfile in the response")
+			}
+			continue // section delimiter
+		default:
+			return err
+		}
+		s := string(line)
+		if msg, ok := strictCut(s, "ERR "); ok {
+			return fmt.Errorf("remote error: %s", strings.TrimSpace(msg))
+		}
+		if s == "packfile\n" {
+			return readSideband(r, w)
+		}
+		// Other sections, e.g. shallow-info, are not needed.
+	}
+}
+
+func readSideband(r io.Reader, w io.Writer) error {
+	for {
+		line, err := readPktLine(r)
+		switch {
+		case err == nil:
+		case errors.Is(err, errPktLineSpecial):
+			if string(line) == pktLineFlush {
+				return nil
+			}
+			return fmt.Errorf("unexpected pkt-line %s in packfile section", line)
+		default:
+			return err
+		}
+		if len(line) == 0 {
+			continue
+		}
+		switch line[0] {
+		case sidebandData:
+			if _, err = w.Write(line[1:]); err != nil {
+				return err
+			}
+		case sidebandProgress:
+		case sidebandError:
+			return fmt.Errorf("remote error: %s", strings.TrimSpace(string(line[1:])))
+		default:
+			return fmt.Errorf("invalid sideband %d", line[0])
+		}
+	}
+}
+
+// readCapabilities reads the capability advertisement.
+// errGitProtocolV2NotSupported is returned if the remote responded with a protocol other than v2.
+func readCapabilities(r io.Reader) ([]string, error) {
+	var caps []string
+	versionSeen := false
+	for {
+		line, err := readPktLine(r)
+		switch {
+		case err == nil:
+		case errors.Is(err, errPktLineSpecial):
+			if !versionSeen { // flush after the "# service=..." line of smart HTTP
+				continue
+			}
+			return caps, nil
+		default:
+			return nil, err
+		}
+		s := strings.TrimSuffix(string(line), "\n")
+		switch {
+		case versionSeen:
+			caps = append(caps, s)
+		case strings.HasPrefix(s, "# service="):
+		case s == "version 2":
+			versionSeen = true
+		default: // protocol v0 or v1 reference advertisement
+			return nil, errGitProtocolV2NotSupported
+		}
+	}
+}
+
+// readPktLine reads a pkt-line. For special pkt-lines (flush, delimiter, response end) it returns errPktLineSpecial
+// and the pkt-line itself.
+func readPktLine(r io.Reader) ([]byte, error) {
+	var lenHex [4]byte
+	if _, err := io.ReadFull(r, lenHex[:]); err != nil {
+		return nil, fmt.Errorf("read pkt-line length: %w", err)
+	}
+	switch string(lenHex[:]) {
+	case pktLineFlush, pktLineDelim, pktLineResponseEnd:
+		return lenHex[:], errPktLineSpecial
+	}
+	length, err := strconv.ParseUint(string(lenHex[:]), 16, 16)
+	if err != nil || length < 4 {
+		return nil, fmt.Errorf("invalid pkt-line length %q", lenHex)
+	}
+	line := make([]byte, length-4)
+	if _, err = io.ReadFull(r, line); err != nil {
+		return nil, fmt.Errorf("read pkt-line: %w", err)
+	}
+	return line, nil
+}
+
+func writePktLine(b *bytes.Buffer, s string) {
+	fmt.Fprintf(b, "%04x%s", len(s)+4, s)
+}
+
+// strictCut returns the part of s after prefix and true if s starts with prefix.
+func strictCut(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
+
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	l.remaining -= int64(len(p))
+	if l.remaining < 0 {
+		return 0, fmt.Errorf("packfile is bigger than the maximum size: %d", maxGitPackSize)
+	}
+	return l.w.Write(p)
+}
+
+// httpUploadPack speaks Git protocol v2 over smart HTTP.
+type httpUploadPack struct {
+	url    string
+	auth   *githttp.BasicAuth
+	client *http.Client
+}
+
+func (u *httpUploadPack) capabilities(ctx context.Context) (retCaps []string, retErr error) {
+	resp, err := u.do(ctx, http.MethodGet, "/info/refs?service=git-upload-pack", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := resp.Close() // nolint: govet
+		if retErr == nil {
+			retErr = err
+		}
+	}()
+	return readCapabilities(bufio.NewReader(resp))
+}
+
+func (u *httpUploadPack) command(ctx context.Context, request []byte) (io.ReadCloser, error) {
+	return u.do(ctx, http.MethodPost, "/git-upload-pack", request)
+}
+
+func (u *httpUploadPack) do(ctx context.Context, method, path string, body []byte) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(gitProtocolHeader, gitProtocolVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-git-upload-pack-request")
+		req.Header.Set("Accept", "application/x-git-upload-pack-result")
+	}
+	if u.auth != nil {
+		req.SetBasicAuth(u.auth.Username, u.auth.Password)
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: unexpected status code %d", method, path, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// sshUploadPack speaks Git protocol v2 over SSH. Each command is sent in a new SSH connection.
+type sshUploadPack struct {
+	address string
+	path    string
+	config  *ssh.ClientConfig
+}
+
+func (u *sshUploadPack) capabilities(ctx context.Context) ([]string, error) {
+	s, caps, err := u.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return caps, s.Close()
+}
+
+func (u *sshUploadPack) command(ctx context.Context, request []byte) (io.ReadCloser, error) {
+	s, _, err := u.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.stdin.Write(request); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// open connects, starts upload-pack and reads the capability advertisement.
+func (u *sshUploadPack) open(ctx context.Context) (*sshUploadPackSession, []string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", u.address)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, u.address, u.config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	s := &sshUploadPackSession{
+		client: ssh.NewClient(c, chans, reqs),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = s.client.Close() // unblock reads and writes
+		case <-s.done:
+		}
+	}()
+	caps, err := s.start(u.path)
+	if err != nil {
+		_ = s.Close()
+		return nil, nil, err
+	}
+	return s, caps, nil
+}
+
+type sshUploadPackSession struct {
+	client *ssh.Client
+	stdin  io.Writer
+	stdout io.Reader
+	done   chan struct{}
+}
+
+func (s *sshUploadPackSession) start(path string) ([]string, error) {
+	session, err := s.client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	// Servers that don't accept the variable respond with protocol v0, which is detected below.
+	_ = session.Setenv(gitProtocolEnvVar, gitProtocolVersion)
+	s.stdin, err = session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	s.stdout = bufio.NewReader(stdout)
+	err = session.Start("git-upload-pack " + shellQuote(path))
+	if err != nil {
+		return nil, err
+	}
+	return readCapabilities(s.stdout)
+}
+
+// shellQuote quotes s for a POSIX shell the same way Git does: s is wrapped in single quotes and each single quote
+// in it is escaped by closing the quoted string, adding a backslash-escaped quote and opening a new quoted string.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func (s *sshUploadPackSession) Read(p []byte) (int, error) {
+	return s.stdout.Read(p)
+}
+
+func (s *sshUploadPackSession) Close() error {
+	close(s.done)
+	err := s.client.Close()
+	if errors.Is(err, net.ErrClosed) {
+		return nil // closed because ctx is done
+	}
+	return err
+}
@@ -0,0 +1,256 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+)
+
+func TestSparseFetch_FetchesOnlyWantedFiles(t *testing.T) {
+	repo, commitHash := newTestGitRepo(t)
+	srv := &fakeUploadPackV2{
+		t:            t,
+		repo:         repo,
+		fetchFeature: "fetch=shallow filter",
+	}
+	s := httptest.NewServer(srv)
+	defer s.Close()
+	up := &httpUploadPack{
+		url:    s.URL + "/repo.git",
+		client: s.Client(),
+	}
+
+	objs, err := sparseFetch(context.Background(), up, commitHash, []*agentcfg.PathCF{
+		{
+			Glob: defaultGitOpsManifestPathGlob,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, commitHash.String(), objs.CommitId)
+	assert.ElementsMatch(t, []rpc.ObjectSource{
+		{
+			Name: "manifest.yaml",
+			Data: []byte("a: b"),
+		},
+		{
+			Name: "dir/manifest.yml",
+			Data: []byte("c: d"),
+		},
+	}, objs.Sources)
+
+	commit, err := repo.CommitObject(commitHash)
+	require.NoError(t, err)
+	tree, err := commit.Tree()
+	require.NoError(t, err)
+	var expectedBlobs []plumbing.Hash
+	for _, name := range []string{"manifest.yaml", "dir/manifest.yml"} {
+		f, err := tree.File(name)
+		require.NoError(t, err)
+		expectedBlobs = append(expectedBlobs, f.Hash)
+	}
+	assert.ElementsMatch(t, expectedBlobs, srv.fetchedBlobs())
+}
+
+func TestSparseFetch_NoFilterSupport(t *testing.T) {
+	repo, commitHash := newTestGitRepo(t)
+	s := httptest.NewServer(&fakeUploadPackV2{
+		t:            t,
+		repo:         repo,
+		fetchFeature: "fetch=shallow",
+	})
+	defer s.Close()
+	up := &httpUploadPack{
+		url:    s.URL + "/repo.git",
+		client: s.Client(),
+	}
+
+	_, err := sparseFetch(context.Background(), up, commitHash, nil)
+	assert.True(t, errors.Is(err, errPartialCloneNotSupported))
+}
+
+func TestReadCapabilities_ProtocolV0(t *testing.T) {
+	var b bytes.Buffer
+	writePktLine(&b, "# service=git-upload-pack\n")
+	b.WriteString(pktLineFlush)
+	writePktLine(&b, gitHash1+" HEAD\x00multi_ack side-band-64k\n")
+	b.WriteString(pktLineFlush)
+
+	_, err := readCapabilities(&b)
+	assert.True(t, errors.Is(err, errGitProtocolV2NotSupported))
+}
+
+func TestNewGitUploadPackV2_UnsupportedAuth(t *testing.T) {
+	_, err := newGitUploadPackV2("git@gitlab.com:org/repo.git", nil, http.DefaultClient)
+	assert.True(t, errors.Is(err, errGitProtocolV2NotSupported))
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, `'/org/repo.git'`, shellQuote("/org/repo.git"))
+	assert.Equal(t, `'/org/'\''; rm -rf /; '\''.git'`, shellQuote("/org/'; rm -rf /; '.git"))
+}
+
+func TestListRefs(t *testing.T) {
+	repo, commitHash := newTestGitRepo(t)
+	s := httptest.NewServer(&fakeUploadPackV2{
+		t:            t,
+		repo:         repo,
+		fetchFeature: "fetch=shallow filter",
+		refs: []string{
+			commitHash.String() + " HEAD symref-target:refs/heads/main",
+			commitHash.String() + " refs/heads/main",
+			gitHash2 + " refs/tags/v1.0.0 peeled:" + gitHash1,
+		},
+	})
+	defer s.Close()
+	up := &httpUploadPack{
+		url:    s.URL + "/repo.git",
+		client: s.Client(),
+	}
+
+	refs, err := listRefs(context.Background(), up)
+	require.NoError(t, err)
+	assert.Equal(t, []*plumbing.Reference{
+		plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main")),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), commitHash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.0.0"), plumbing.NewHash(gitHash1)),
+	}, refs)
+}
+
+// fakeUploadPackV2 is a minimal smart HTTP Git protocol v2 server.
+type fakeUploadPackV2 struct {
+	t            *testing.T
+	repo         *git.Repository
+	fetchFeature string
+	// refs are the lines of the ls-refs response. ls-refs is not advertised if empty.
+	refs []string
+
+	mu    sync.Mutex
+	blobs []plumbing.Hash
+}
+
+func (s *fakeUploadPackV2) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	assert.Equal(s.t, gitProtocolVersion, r.Header.Get(gitProtocolHeader))
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repo.git/info/refs":
+		var b bytes.Buffer
+		writePktLine(&b, "# service=git-upload-pack\n")
+		b.WriteString(pktLineFlush)
+		writePktLine(&b, "version 2\n")
+		writePktLine(&b, s.fetchFeature+"\n")
+		if len(s.refs) > 0 {
+			writePktLine(&b, "ls-refs\n")
+		}
+		b.WriteString(pktLineFlush)
+		_, _ = w.Write(b.Bytes())
+	case r.Method == http.MethodPost && r.URL.Path == "/repo.git/git-upload-pack":
+		s.command(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeUploadPackV2) command(w http.ResponseWriter, r *http.Request) {
+	line, err := readPktLine(r.Body)
+	require.NoError(s.t, err)
+	switch string(line) {
+	case "command=fetch\n":
+		s.fetch(w, r)
+	case "command=ls-refs\n":
+		var b bytes.Buffer
+		for _, ref := range s.refs {
+			writePktLine(&b, ref+"\n")
+		}
+		b.WriteString(pktLineFlush)
+		_, _ = w.Write(b.Bytes())
+	default:
+		s.t.Errorf("unexpected command %q", line)
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (s *fakeUploadPackV2) fetch(w http.ResponseWriter, r *http.Request) {
+	var (
+		wants  []plumbing.Hash
+		filter bool
+	)
+	for {
+		line, err := readPktLine(r.Body)
+		if errors.Is(err, errPktLineSpecial) {
+			if string(line) == pktLineFlush {
+				break
+			}
+			continue
+		}
+		require.NoError(s.t, err)
+		l := strings.TrimSuffix(string(line), "\n")
+		if want, ok := strictCut(l, "want "); ok {
+			wants = append(wants, plumbing.NewHash(want))
+		}
+		if l == "filter blob:none" {
+			filter = true
+		}
+	}
+	var hashes []plumbing.Hash
+	if filter {
+		for _, want := range wants {
+			hashes = append(hashes, s.commitAndTrees(want)...)
+		}
+	} else {
+		hashes = wants
+		s.mu.Lock()
+		s.blobs = append(s.blobs, wants...)
+		s.mu.Unlock()
+	}
+	var pack bytes.Buffer
+	_, err := packfile.NewEncoder(&pack, s.repo.Storer, false).Encode(hashes, 10)
+	require.NoError(s.t, err)
+
+	var b bytes.Buffer
+	writePktLine(&b, "packfile\n")
+	for pack.Len() > 0 {
+		writePktLine(&b, "\x01"+string(pack.Next(1000)))
+	}
+	b.WriteString(pktLineFlush)
+	_, _ = w.Write(b.Bytes())
+}
+
+func (s *fakeUploadPackV2) commitAndTrees(commitHash plumbing.Hash) []plumbing.Hash {
+	commit, err := s.repo.CommitObject(commitHash)
+	require.NoError(s.t, err)
+	tree, err := commit.Tree()
+	require.NoError(s.t, err)
+	hashes := []plumbing.Hash{commitHash, tree.Hash}
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		_, entry, err := walker.Next()
+		if err != nil {
+			break
+		}
+		if entry.Mode == filemode.Dir {
+			hashes = append(hashes, entry.Hash)
+		}
+	}
+	return hashes
+}
+
+func (s *fakeUploadPackV2) fetchedBlobs() []plumbing.Hash {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blobs
+}
@@ -0,0 +1,184 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitLabAPIClient calls the GitLab REST API using a personal, project or group access token.
+// See https://docs.gitlab.com/ee/api/.
+type gitLabAPIClient struct {
+	httpClient *http.Client
+	// baseUrl is the URL of the GitLab instance, e.g. https://gitlab.example.com
+	baseUrl string
+	token   string
+}
+
+type gitLabProject struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+type gitLabBranch struct {
+	Commit struct {
+		Id string `json:"id"`
+	} `json:"commit"`
+}
+
+type gitLabFile struct {
+	LastCommitId string `json:"last_commit_id"`
+}
+
+type gitLabCommitAction struct {
+	Action   string `json:"action"`
+	FilePath string `json:"file_path"`
+	Content  string `json:"content"`
+	// LastCommitId is the last known commit that changed the file. The commit fails if the file has been changed since.
+	LastCommitId string `json:"last_commit_id,omitempty"`
+}
+
+// gitLabCreateCommit is the request of the Commits API.
+// See https://docs.gitlab.com/ee/api/commits.html#create-a-commit-with-multiple-files-and-actions.
+type gitLabCreateCommit struct {
+	Branch        string               `json:"branch"`
+	StartSha      string               `json:"start_sha,omitempty"`
+	Force         bool                 `json:"force,omitempty"`
+	CommitMessage string               `json:"commit_message"`
+	Actions       []gitLabCommitAction `json:"actions"`
+}
+
+// gitLabCreateMergeRequest is the request of the Merge requests API.
+// See https://docs.gitlab.com/ee/api/merge_requests.html#create-mr.
+type gitLabCreateMergeRequest struct {
+	SourceBranch       string `json:"source_branch"`
+	TargetBranch       string `json:"target_branch"`
+	Title              string `json:"title"`
+	Description        string `json:"description"`
+	RemoveSourceBranch bool   `json:"remove_source_branch"`
+}
+
+type gitLabMergeRequest struct {
+	Iid   int64  `json:"iid"`
+	State string `json:"state"`
+}
+
+// gitLabAPIError is returned for unexpected status codes.
+type gitLabAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *gitLabAPIError) Error() string {
+	return fmt.Sprintf("GitLab API: unexpected status code %d: %s", e.StatusCode, e.Message)
+}
+
+func (c *gitLabAPIClient) GetProject(ctx context.Context, projectId string) (*gitLabProject, error) {
+	var project gitLabProject
+	err := c.do(ctx, http.MethodGet, c.projectPath(projectId), nil, nil, &project)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (c *gitLabAPIClient) GetBranch(ctx context.Context, projectId, branch string) (*gitLabBranch, error) {
+	var b gitLabBranch
+	err := c.do(ctx, http.MethodGet, c.projectPath(projectId)+"/repository/branches/"+url.PathEscape(branch), nil, nil, &b)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// GetFile gets the metadata of a file at the given ref.
+func (c *gitLabAPIClient) GetFile(ctx context.Context, projectId, ref, filePath string) (*gitLabFile, error) {
+	var f gitLabFile
+	query := url.Values{
+		"ref": []string{ref},
+	}
+	err := c.do(ctx, http.MethodGet, c.projectPath(projectId)+"/repository/files/"+url.PathEscape(filePath), query, nil, &f)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (c *gitLabAPIClient) CreateCommit(ctx context.Context, projectId string, commit *gitLabCreateCommit) error {
+	return c.do(ctx, http.MethodPost, c.projectPath(projectId)+"/repository/commits", nil, commit, nil)
+}
+
+// ListMergeRequests lists merge requests in any state that have the given source branch.
+func (c *gitLabAPIClient) ListMergeRequests(ctx context.Context, projectId, sourceBranch string) ([]gitLabMergeRequest, error) {
+	var mrs []gitLabMergeRequest
+	query := url.Values{
+		"source_branch": []string{sourceBranch},
+		"state":         []string{"all"},
+	}
+	err := c.do(ctx, http.MethodGet, c.projectPath(projectId)+"/merge_requests", query, nil, &mrs)
+	if err != nil {
+		return nil, err
+	}
+	return mrs, nil
+}
+
+func (c *gitLabAPIClient) CreateMergeRequest(ctx context.Context, projectId string, mr *gitLabCreateMergeRequest) (*gitLabMergeRequest, error) {
+	var created gitLabMergeRequest
+	err := c.do(ctx, http.MethodPost, c.projectPath(projectId)+"/merge_requests", nil, mr, &created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *gitLabAPIClient) projectPath(projectId string) string {
+	return "/api/v4/projects/" + url.PathEscape(projectId)
+}
+
+func (c *gitLabAPIClient) do(ctx context.Context, method, path string, query url.Values, body, response interface{}) error {
+	u := strings.TrimSuffix(c.baseUrl, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var bodyReader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("GitLab API: request encode: %v", err)
+		}
+		bodyReader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitLab API: %v", err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &gitLabAPIError{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(msg)),
+		}
+	}
+	if response == nil {
+		return nil
+	}
+	if err = json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("GitLab API: response decode: %v", err)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package agent
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/cache"
@@ -9,52 +11,157 @@ import (
 	"github.com/ash2k/stager"
 	"github.com/go-logr/zapr"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
 	engineRunRetryPeriod = 10 * time.Second
+	// serviceAccountUsernamePrefix is the prefix of user names of service accounts, followed by "<namespace>:<name>".
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
 )
 
 type GitopsEngineFactory interface {
-	New(engineOpts []engine.Option, cacheOpts []cache.UpdateSettingsFunc) engine.GitOpsEngine
+	New(kubeClientConfig *rest.Config, engineOpts []engine.Option, clusterCache cache.ClusterCache) engine.GitOpsEngine
 }
 
 type GitopsWorkerFactory interface {
-	New(project *agentcfg.ManifestProjectCF) GitopsWorker
+	// New constructs a worker for the project.
+	// namespace is the namespace of the ManifestProject object the project is declared with. Empty namespace
+	// means the project is from the configuration file.
+	// allowedNamespaces restricts the worker to these namespaces. Empty list means no restriction.
+	// cluster is the cluster to synchronize objects into. nil means the cluster agentk runs in.
+	// serviceAccount is the service account in namespace to synchronize objects as. Empty means agentk's own identity.
+	New(project *agentcfg.ManifestProjectCF, namespace string, allowedNamespaces []string, cluster *agentcfg.ClusterCF, serviceAccount string) GitopsWorker
 }
 
 type GitopsWorker interface {
 	Run(ctx context.Context)
+	// Cleanup removes what the worker has created in the cluster for the project, other than the synchronized objects.
+	// It is called after Run has returned if the project has been removed from the configuration, not when the
+	// worker is stopped on shutdown or restarted with an updated configuration.
+	Cleanup(ctx context.Context)
 }
 
 type gitopsWorker struct {
+	// branch to synchronize. Empty means the default branch.
+	branch        string
 	objWatcher    rpc.ObjectsToSynchronizeWatcherInterface
 	engineFactory GitopsEngineFactory
+	// restConfig is the configuration to access the cluster agentk runs in.
+	restConfig *rest.Config
+	// remoteCluster is nil if objects are synchronized into the cluster agentk runs in.
+	remoteCluster *remoteCluster
+	// imageUpdater is nil if image update automation is not configured.
+	imageUpdater *imageUpdater
+	// impersonate is the user name of the service account to synchronize objects as.
+	// Empty means agentk's own identity is used.
+	impersonate string
 	synchronizerConfig
 }
 
 func (d *gitopsWorker) Run(ctx context.Context) {
+	defer d.metrics.delete()
+	if d.remoteCluster == nil {
+		restConfig := d.restConfig
+		if d.impersonate != "" {
+			var err error
+			restConfig, err = d.impersonateServiceAccount()
+			if err != nil {
+				d.log.Error("Failed to set up service account impersonation", zap.Error(err))
+				return
+			}
+		}
+		d.run(ctx, restConfig)
+		return
+	}
+	for {
+		clients, kubeconfig, ok := d.loadRemoteCluster(ctx)
+		if !ok {
+			// context is done
+			return
+		}
+		d.k8sClientGetter = clients.clientGetter
+		if d.permissionChecker != nil {
+			d.permissionChecker.kubeClient = clients.kubeClient
+		}
+		restMapper, _ := clients.clientGetter.ToRESTMapper() // never fails
+		d.statusReporter.setObjectsRESTMapper(restMapper)
+		runCtx, cancel := context.WithCancel(ctx)
+		var wg wait.Group
+		wg.Start(func() {
+			if d.remoteCluster.waitForChange(runCtx, d.log, kubeconfig) {
+				d.log.Info("Cluster credentials have changed, restarting synchronization")
+				cancel()
+			}
+		})
+		d.run(runCtx, clients.restConfig)
+		cancel()
+		wg.Wait()
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// impersonateServiceAccount returns the configuration to access the cluster as the service account.
+// The permission checker is switched to check permissions of the service account.
+func (d *gitopsWorker) impersonateServiceAccount() (*rest.Config, error) {
+	restConfig := rest.CopyConfig(d.restConfig)
+	restConfig.Impersonate = rest.ImpersonationConfig{
+		UserName: d.impersonate,
+	}
+	if d.permissionChecker != nil {
+		kubeClient, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("kubernetes.NewForConfig: %v", err)
+		}
+		d.permissionChecker.kubeClient = kubeClient
+	}
+	return restConfig, nil
+}
+
+func (d *gitopsWorker) Cleanup(ctx context.Context) {
+	d.statusReporter.cleanup(ctx)
+}
+
+// run synchronizes objects into the cluster until ctx is done.
+func (d *gitopsWorker) run(ctx context.Context, restConfig *rest.Config) {
 	l := zapr.NewLogger(d.log)
+	cacheOpts := []cache.UpdateSettingsFunc{
+		cache.SetPopulateResourceInfoHandler(populateResourceInfoHandler),
+		cache.SetSettings(cache.Settings{
+			ResourcesFilter: resourcesFilter{
+				resourceInclusions: d.project.ResourceInclusions,
+				resourceExclusions: d.project.ResourceExclusions,
+			},
+		}),
+		cache.SetLogr(l),
+	}
+	if len(d.allowedNamespaces) > 0 {
+		// Only objects in the namespaces are seen by the engine, cluster-scoped objects cannot be synchronized.
+		cacheOpts = append(cacheOpts, cache.SetNamespaces(d.allowedNamespaces))
+	}
+	clusterCache := cache.NewClusterCache(restConfig, cacheOpts...)
+	// Status of managed objects is read from the cache the engine maintains.
+	d.statusReporter.setObjectsCache(clusterCache)
+	d.objectEventRecorder.setObjectsCache(clusterCache)
 	eng := d.engineFactory.New(
+		restConfig,
 		[]engine.Option{
 			engine.WithLogr(l),
 		},
-		[]cache.UpdateSettingsFunc{
-			cache.SetPopulateResourceInfoHandler(populateResourceInfoHandler),
-			cache.SetSettings(cache.Settings{
-				ResourcesFilter: resourcesFilter{
-					resourceInclusions: d.project.ResourceInclusions,
-					resourceExclusions: d.project.ResourceExclusions,
-				},
-			}),
-			cache.SetLogr(l),
-		},
+		clusterCache,
 	)
 	var stopEngine engine.StopFunc
 	err := retry.PollImmediateUntil(ctx, engineRunRetryPeriod, func() (bool /*done*/, error) {
@@ -74,56 +181,227 @@ func (d *gitopsWorker) Run(ctx context.Context) {
 	s := newSynchronizer(d.synchronizerConfig, eng)
 	st := stager.New()
 	stage := st.NextStage()
+	stage.Go(func(ctx context.Context) error {
+		d.statusReporter.run(ctx)
+		return nil
+	})
 	stage.Go(func(ctx context.Context) error {
 		s.run(ctx)
 		return nil
 	})
+	if d.imageUpdater != nil {
+		stage.Go(func(ctx context.Context) error {
+			d.imageUpdater.run(ctx)
+			return nil
+		})
+	}
 	stage = st.NextStage()
 	stage.Go(func(ctx context.Context) error {
 		req := &rpc.ObjectsToSynchronizeRequest{
-			ProjectId: d.project.Id,
-			Paths:     d.project.Paths,
+			ProjectId:           d.project.Id,
+			Paths:               d.project.Paths,
+			SemverTagConstraint: d.project.SemverTagConstraint,
+			Branch:              d.branch,
 		}
 		return d.objWatcher.Watch(ctx, req, func(ctx context.Context, data rpc.ObjectsToSynchronizeData) {
-			s.setDesiredState(ctx, data)
+			if d.imageUpdater != nil {
+				d.imageUpdater.setManifests(data)
+			}
+			rendered, err := renderObjectsToSynchronize(ctx, d.project, data)
+			if err != nil {
+				d.log.Error("Failed to render GitOps objects", zap.Error(err), logz.CommitId(data.CommitId))
+				d.statusReporter.syncFailed(data.CommitId, err)
+				return
+			}
+			s.setDesiredState(ctx, rendered)
 		})
 	})
 	_ = st.Run(ctx) // no errors possible
 }
 
+// loadRemoteCluster constructs clients for the remote cluster, retrying until it succeeds or ctx is done.
+// The kubeconfig file is returned too.
+func (d *gitopsWorker) loadRemoteCluster(ctx context.Context) (*clusterClients, []byte, bool) {
+	var (
+		clients    *clusterClients
+		kubeconfig []byte
+	)
+	err := retry.PollImmediateUntil(ctx, engineRunRetryPeriod, func() (bool /*done*/, error) {
+		var err error
+		clients, kubeconfig, err = d.remoteCluster.load(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.log.Error("Failed to load cluster credentials", zap.Error(err))
+				d.statusReporter.syncFailed("", err)
+			}
+			return false, nil // nil error to keep polling
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, nil, false
+	}
+	return clients, kubeconfig, true
+}
+
 type defaultGitopsEngineFactory struct {
-	kubeClientConfig *rest.Config
 }
 
-func (f *defaultGitopsEngineFactory) New(engineOpts []engine.Option, cacheOpts []cache.UpdateSettingsFunc) engine.GitOpsEngine {
-	return engine.NewEngine(
-		f.kubeClientConfig,
-		cache.NewClusterCache(f.kubeClientConfig, cacheOpts...),
-		engineOpts...,
-	)
+func (f *defaultGitopsEngineFactory) New(kubeClientConfig *rest.Config, engineOpts []engine.Option, clusterCache cache.ClusterCache) engine.GitOpsEngine {
+	return engine.NewEngine(kubeClientConfig, clusterCache, engineOpts...)
 }
 
 type defaultGitopsWorkerFactory struct {
 	log                                *zap.Logger
 	engineFactory                      GitopsEngineFactory
+	restConfig                         *rest.Config
 	k8sClientGetter                    resource.RESTClientGetter
+	kubeClient                         kubernetes.Interface
+	dynamicClient                      dynamic.Interface
+	restMapper                         meta.RESTMapper
+	eventRecorder                      record.EventRecorder
+	projectStates                      *projectStates
+	metrics                            *gitopsMetrics
+	agentMeta                          *modshared.AgentMeta
+	agentNamespace                     string
+	httpClient                         *http.Client
 	getObjectsToSynchronizeRetryPeriod time.Duration
 	gitopsClient                       rpc.GitopsClient
 }
 
-func (m *defaultGitopsWorkerFactory) New(project *agentcfg.ManifestProjectCF) GitopsWorker {
+func (m *defaultGitopsWorkerFactory) New(project *agentcfg.ManifestProjectCF, namespace string, allowedNamespaces []string, cluster *agentcfg.ClusterCF, serviceAccount string) GitopsWorker {
+	if project.PreviewEnvironments != nil {
+		l := m.log.With(logz.ProjectId(project.Id))
+		return &previewWorker{
+			log:     l,
+			project: project,
+			branchesWatcher: &rpc.BranchesWatcher{
+				Log:          l,
+				GitopsClient: m.gitopsClient,
+				RetryPeriod:  m.getObjectsToSynchronizeRetryPeriod,
+			},
+			kubeClient: m.kubeClient,
+			newBranchWorker: func(project *agentcfg.ManifestProjectCF, branch string) GitopsWorker {
+				return m.newForBranch(project, namespace, allowedNamespaces, cluster, "", branch)
+			},
+		}
+	}
+	return m.newForBranch(project, namespace, allowedNamespaces, cluster, serviceAccount, "")
+}
+
+func (m *defaultGitopsWorkerFactory) newForBranch(project *agentcfg.ManifestProjectCF, namespace string, allowedNamespaces []string, cluster *agentcfg.ClusterCF, serviceAccount, branch string) GitopsWorker {
 	l := m.log.With(logz.ProjectId(project.Id))
-	return &gitopsWorker{
-		objWatcher: &rpc.ObjectsToSynchronizeWatcher{
+	if branch != "" {
+		l = l.With(logz.GitBranch(branch))
+	}
+	// The variables ConfigMap and the status object live in the namespace of the ManifestProject object, if any.
+	objectsNamespace := m.agentNamespace
+	if namespace != "" {
+		l = l.With(logz.Namespace(namespace))
+		objectsNamespace = namespace
+	}
+	var objWatcher rpc.ObjectsToSynchronizeWatcherInterface
+	switch {
+	case project.GitRemote != nil:
+		objWatcher = &gitRemoteWatcher{
+			log:             l,
+			remote:          project.GitRemote,
+			httpClient:      m.httpClient,
+			kubeClient:      m.kubeClient,
+			secretNamespace: objectsNamespace,
+			pollPeriod:      defaultGitRemotePollPeriod,
+		}
+	case project.OciArtifact != nil:
+		objWatcher = &ociWatcher{
+			log:             l,
+			artifact:        project.OciArtifact,
+			httpClient:      m.httpClient,
+			kubeClient:      m.kubeClient,
+			secretNamespace: objectsNamespace,
+			pollPeriod:      defaultOciArtifactPollPeriod,
+		}
+	default:
+		objWatcher = &rpc.ObjectsToSynchronizeWatcher{
 			Log:          l,
 			GitopsClient: m.gitopsClient,
 			RetryPeriod:  m.getObjectsToSynchronizeRetryPeriod,
-		},
+		}
+	}
+	var varSubstitutor *variableSubstitutor
+	if project.Variables != nil {
+		varSubstitutor = &variableSubstitutor{
+			project:            project,
+			agentMeta:          m.agentMeta,
+			kubeClient:         m.kubeClient,
+			configMapNamespace: objectsNamespace,
+		}
+	}
+	var imgUpdater *imageUpdater
+	if project.ImageUpdateAutomation != nil {
+		imgUpdater = &imageUpdater{
+			log:             l,
+			projectId:       project.Id,
+			automation:      project.ImageUpdateAutomation,
+			httpClient:      m.httpClient,
+			kubeClient:      m.kubeClient,
+			secretNamespace: objectsNamespace,
+			pollPeriod:      defaultImageUpdatePollPeriod,
+		}
+	}
+	statusReporter := newGitopsStatusReporter(l, m.dynamicClient, m.restMapper, m.eventRecorder, objectsNamespace, project.Id, branch)
+	projectMetrics := m.metrics.forProject(project.Id, namespace, branch)
+	statusReporter.metrics = projectMetrics
+	statusReporter.discovery = m.kubeClient.Discovery()
+	if namespace == "" && branch == "" {
+		// Only projects from the configuration file can be referenced in depends_on.
+		// Preview environments are not synchronizing the project itself.
+		statusReporter.projectStates = m.projectStates
+	}
+	var states *projectStates
+	if len(project.DependsOn) > 0 {
+		states = m.projectStates
+	}
+	var remote *remoteCluster
+	eventRecorder := &objectEventRecorder{
+		recorder:  m.eventRecorder,
+		projectId: project.Id,
+	}
+	if cluster != nil {
+		remote = &remoteCluster{
+			cluster:         cluster,
+			kubeClient:      m.kubeClient,
+			secretNamespace: m.agentNamespace,
+			checkPeriod:     kubeconfigSecretCheckPeriod,
+		}
+		// Events are recorded in the cluster agentk runs in, they cannot reference objects in another cluster.
+		eventRecorder = nil
+	}
+	var impersonate string
+	if serviceAccount != "" {
+		impersonate = serviceAccountUsernamePrefix + namespace + ":" + serviceAccount
+	}
+	return &gitopsWorker{
+		branch:        branch,
+		impersonate:   impersonate,
+		objWatcher:    objWatcher,
 		engineFactory: m.engineFactory,
+		restConfig:    m.restConfig,
+		remoteCluster: remote,
+		imageUpdater:  imgUpdater,
 		synchronizerConfig: synchronizerConfig{
-			log:             l,
-			project:         project,
-			k8sClientGetter: m.k8sClientGetter,
+			log:                 l,
+			project:             project,
+			k8sClientGetter:     m.k8sClientGetter,
+			allowedNamespaces:   allowedNamespaces,
+			variableSubstitutor: varSubstitutor,
+			statusReporter:      statusReporter,
+			objectEventRecorder: eventRecorder,
+			metrics:             projectMetrics,
+			projectStates:       states,
+			permissionChecker: &permissionChecker{
+				log:        l,
+				kubeClient: m.kubeClient,
+			},
 		},
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/kube_testing"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/matcher"
@@ -18,7 +19,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 )
 
 const (
@@ -177,7 +182,7 @@ func setupWorker(t *testing.T) (*gitopsWorker, *MockGitOpsEngine, *mock_rpc.Mock
 	})
 	gomock.InOrder(
 		engineFactory.EXPECT().
-			New(gomock.Any(), gomock.Any()).
+			New(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(engine),
 		engine.EXPECT().
 			Run().
@@ -257,3 +262,29 @@ func testNs1() *corev1.Namespace {
 		},
 	}
 }
+
+func TestImpersonatesServiceAccount(t *testing.T) {
+	f := &defaultGitopsWorkerFactory{
+		log:           zaptest.NewLogger(t),
+		restConfig:    &rest.Config{Host: "https://kube.example.com", BearerToken: "agent-token"},
+		kubeClient:    fake.NewSimpleClientset(),
+		dynamicClient: fakedynamic.NewSimpleDynamicClient(runtime.NewScheme()),
+		metrics:       newGitopsMetrics(),
+	}
+	project := &agentcfg.ManifestProjectCF{
+		Id:               "team1/app",
+		DefaultNamespace: "ns1",
+	}
+	w := f.New(project, "ns1", []string{"ns1"}, nil, "deployer").(*gitopsWorker)
+	assert.Equal(t, "system:serviceaccount:ns1:deployer", w.impersonate)
+	agentKubeClient := w.permissionChecker.kubeClient
+	restConfig, err := w.impersonateServiceAccount()
+	require.NoError(t, err)
+	assert.Equal(t, "system:serviceaccount:ns1:deployer", restConfig.Impersonate.UserName)
+	assert.Equal(t, "agent-token", restConfig.BearerToken)
+	assert.Empty(t, f.restConfig.Impersonate.UserName) // not modified
+	assert.NotSame(t, agentKubeClient, w.permissionChecker.kubeClient)
+
+	w = f.New(project, "", nil, nil, "").(*gitopsWorker)
+	assert.Empty(t, w.impersonate)
+}
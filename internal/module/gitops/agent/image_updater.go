@@ -0,0 +1,344 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/oci"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultImageUpdatePollPeriod = time.Minute
+	imageUpdateCommitMessage     = "Update images\n\n"
+	imageUpdateMergeRequestTitle = "Update images"
+	// imageUpdateBranchPrefix is the prefix of branches with proposed updates.
+	imageUpdateBranchPrefix  = "gitlab-agent/image-updates-"
+	imageUpdateBranchHashLen = 16
+
+	secretKeyToken = "token"
+)
+
+var (
+	// imageReferenceRegex matches image references with a tag in YAML or JSON "image" fields.
+	// Submatches: 1 - field name and opening quote, 2 - image, 3 - tag, 4 - closing quote and separator.
+	// References with a digest do not match.
+	imageReferenceRegex = regexp.MustCompile(`(?m)((?:^|[\s{,-])["']?image["']?:[ \t]*["']?)([\w][\w.-]*(?::[0-9]+)?(?:/[\w][\w.-]*)*):([\w][\w.-]{0,127})(["']?(?:[\s,}]|$))`)
+)
+
+// imageUpdater watches container registries for new tags of configured images and commits
+// updated image references back to the manifest project using the GitLab API.
+type imageUpdater struct {
+	log             *zap.Logger
+	projectId       string
+	automation      *agentcfg.ImageUpdateAutomationCF
+	httpClient      *http.Client
+	kubeClient      kubernetes.Interface
+	secretNamespace string
+	pollPeriod      time.Duration
+
+	mu sync.Mutex
+	// data is the last known state of the manifest project.
+	data *rpc.ObjectsToSynchronizeData
+}
+
+type imageUpdate struct {
+	Image string
+	Tag   string
+}
+
+// setManifests records the latest manifests that have been fetched from the manifest project.
+func (u *imageUpdater) setManifests(data rpc.ObjectsToSynchronizeData) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.data = &data
+}
+
+func (u *imageUpdater) run(ctx context.Context) {
+	retry.JitterUntil(ctx, u.pollPeriod, func(ctx context.Context) {
+		err := u.update(ctx)
+		if err != nil {
+			u.log.Error("Image update automation failed", zap.Error(err))
+		}
+	})
+}
+
+// update commits or proposes updates for the last known state of the manifest project.
+// It does not keep any state between runs. Instead, it relies on the state in GitLab to not make the same update twice:
+// - A direct commit is only made if the branch still points at the commit the manifests have been fetched from.
+// - A merge request is proposed from a branch, which name is derived from the updates. No merge request is
+// created if there is one, in any state, from that branch already.
+func (u *imageUpdater) update(ctx context.Context) error {
+	u.mu.Lock()
+	data := u.data
+	u.mu.Unlock()
+	if data == nil {
+		return nil // manifests have not been fetched yet
+	}
+	var updates []imageUpdate
+	for _, policy := range u.automation.Images {
+		tag, err := u.latestTag(ctx, policy)
+		if err != nil {
+			return fmt.Errorf("image %s: %v", policy.Image, err)
+		}
+		if tag == "" {
+			u.log.Debug("No tags match the image policy", zap.String("image", policy.Image))
+			continue
+		}
+		updates = append(updates, imageUpdate{
+			Image: policy.Image,
+			Tag:   tag,
+		})
+	}
+	actions := imageUpdateActions(data.Sources, updates)
+	if len(actions) == 0 {
+		return nil
+	}
+	gl, err := u.gitLabClient(ctx)
+	if err != nil {
+		return err
+	}
+	project, err := gl.GetProject(ctx, u.projectId)
+	if err != nil {
+		return fmt.Errorf("get project: %w", err)
+	}
+	msg := imageUpdateMessage(updates)
+	if u.automation.CreateMergeRequest {
+		return u.proposeUpdates(ctx, gl, data.CommitId, project.DefaultBranch, updates, msg, actions)
+	}
+	return u.commitUpdates(ctx, gl, data.CommitId, project.DefaultBranch, msg, actions)
+}
+
+func (u *imageUpdater) commitUpdates(ctx context.Context, gl *gitLabAPIClient, lastCommitId, branch, msg string, actions []gitLabCommitAction) error {
+	b, err := gl.GetBranch(ctx, u.projectId, branch)
+	if err != nil {
+		return fmt.Errorf("get branch: %w", err)
+	}
+	if b.Commit.Id != lastCommitId {
+		// The branch has moved, e.g. because of the previous commit. Try again once the new commit has been fetched.
+		u.log.Debug("Manifest project has been updated, will retry image updates after fetching it", zap.String("commit_id", b.Commit.Id))
+		return nil
+	}
+	// The branch may still move between the check above and the commit. start_sha cannot be used to detect that
+	// because GitLab rejects it for an existing branch unless force is set, which would overwrite the branch.
+	// Instead, each file is guarded by the last commit that changed it, so the commit fails if any of the files
+	// has been changed since the manifests have been fetched.
+	for i := range actions {
+		f, err := gl.GetFile(ctx, u.projectId, lastCommitId, actions[i].FilePath)
+		if err != nil {
+			return fmt.Errorf("get file %s: %w", actions[i].FilePath, err)
+		}
+		actions[i].LastCommitId = f.LastCommitId
+	}
+	err = gl.CreateCommit(ctx, u.projectId, &gitLabCreateCommit{
+		Branch:        branch,
+		CommitMessage: imageUpdateCommitMessage + msg,
+		Actions:       actions,
+	})
+	if err != nil {
+		return fmt.Errorf("create commit: %w", err)
+	}
+	u.log.Info("Committed image updates", zap.Int("files", len(actions)), zap.String("last_commit_id", lastCommitId))
+	return nil
+}
+
+func (u *imageUpdater) proposeUpdates(ctx context.Context, gl *gitLabAPIClient, lastCommitId, targetBranch string, updates []imageUpdate, msg string, actions []gitLabCommitAction) error {
+	branch := imageUpdateBranchName(updates)
+	mrs, err := gl.ListMergeRequests(ctx, u.projectId, branch)
+	if err != nil {
+		return fmt.Errorf("list merge requests: %w", err)
+	}
+	if len(mrs) > 0 {
+		u.log.Debug("Image updates have been proposed already", zap.String("branch", branch), zap.Int64("merge_request_iid", mrs[0].Iid))
+		return nil
+	}
+	// The branch may exist if a previous attempt failed to create the merge request. Force re-creates it.
+	err = gl.CreateCommit(ctx, u.projectId, &gitLabCreateCommit{
+		Branch:        branch,
+		StartSha:      lastCommitId,
+		Force:         true,
+		CommitMessage: imageUpdateCommitMessage + msg,
+		Actions:       actions,
+	})
+	if err != nil {
+		return fmt.Errorf("create commit: %w", err)
+	}
+	mr, err := gl.CreateMergeRequest(ctx, u.projectId, &gitLabCreateMergeRequest{
+		SourceBranch:       branch,
+		TargetBranch:       targetBranch,
+		Title:              imageUpdateMergeRequestTitle,
+		Description:        msg,
+		RemoveSourceBranch: true,
+	})
+	if err != nil {
+		var apiErr *gitLabAPIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			return nil // created concurrently
+		}
+		return fmt.Errorf("create merge request: %w", err)
+	}
+	u.log.Info("Proposed image updates", zap.Int("files", len(actions)), zap.Int64("merge_request_iid", mr.Iid))
+	return nil
+}
+
+// gitLabClient returns a client for GitLab's REST API that uses the token from the configured Secret.
+// modagent.API.MakeGitLabRequest cannot be used here because it only reaches the internal API for agent modules,
+// which does not expose the Commits and Merge requests APIs.
+func (u *imageUpdater) gitLabClient(ctx context.Context) (*gitLabAPIClient, error) {
+	secret, err := u.kubeClient.CoreV1().Secrets(u.secretNamespace).Get(ctx, u.automation.TokenSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("GitLab token: %v", err)
+	}
+	token := secret.Data[secretKeyToken]
+	if len(token) == 0 {
+		return nil, fmt.Errorf("secret %s has no %q key", u.automation.TokenSecret, secretKeyToken)
+	}
+	return &gitLabAPIClient{
+		httpClient: u.httpClient,
+		baseUrl:    u.automation.GitlabUrl,
+		token:      string(token),
+	}, nil
+}
+
+func (u *imageUpdater) latestTag(ctx context.Context, policy *agentcfg.ImagePolicyCF) (string, error) {
+	ref, err := oci.ParseReference(policy.Image)
+	if err != nil {
+		return "", err // should never happen as the image is validated in DefaultAndValidateConfiguration()
+	}
+	client := &oci.Client{
+		HTTPClient: u.httpClient,
+	}
+	if policy.CredentialsSecret != "" {
+		secret, err := u.kubeClient.CoreV1().Secrets(u.secretNamespace).Get(ctx, policy.CredentialsSecret, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("credentials: %v", err)
+		}
+		client.Username = string(secret.Data[secretKeyUsername])
+		client.Password = string(secret.Data[secretKeyPassword])
+	}
+	tags, err := client.ListTags(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return selectTag(policy, tags)
+}
+
+// selectTag returns the tag that the policy selects from the given tags or an empty string if there are no suitable tags.
+func selectTag(policy *agentcfg.ImagePolicyCF, tags []string) (string, error) {
+	if policy.SemverRange != "" {
+		constraint, err := semver.NewConstraint(policy.SemverRange)
+		if err != nil {
+			return "", err // should never happen as the range is validated in DefaultAndValidateConfiguration()
+		}
+		var latestTag string
+		var latest *semver.Version
+		for _, tag := range tags {
+			v, err := semver.NewVersion(tag)
+			if err != nil {
+				continue // not a semver tag
+			}
+			if !constraint.Check(v) {
+				continue
+			}
+			if latest == nil || v.GreaterThan(latest) {
+				latest = v
+				latestTag = tag
+			}
+		}
+		return latestTag, nil
+	}
+	re, err := regexp.Compile(policy.TagRegex)
+	if err != nil {
+		return "", err // should never happen as the regex is validated in DefaultAndValidateConfiguration()
+	}
+	var latestTag string
+	for _, tag := range tags {
+		if re.MatchString(tag) && tag > latestTag {
+			latestTag = tag
+		}
+	}
+	return latestTag, nil
+}
+
+// imageUpdateActions returns update actions for files that reference images with tags that differ from the updates.
+func imageUpdateActions(sources []rpc.ObjectSource, updates []imageUpdate) []gitLabCommitAction {
+	if len(updates) == 0 {
+		return nil
+	}
+	var actions []gitLabCommitAction
+	for _, source := range sources {
+		data := source.Data
+		changed := false
+		for _, update := range updates {
+			var updated bool
+			data, updated = updateImageReferences(data, update.Image, update.Tag)
+			changed = changed || updated
+		}
+		if changed {
+			actions = append(actions, gitLabCommitAction{
+				Action:   "update",
+				FilePath: source.Name,
+				Content:  string(data),
+			})
+		}
+	}
+	return actions
+}
+
+// updateImageReferences replaces the tag in image references to image in YAML or JSON data.
+// References with a digest are not updated.
+func updateImageReferences(data []byte, image, tag string) ([]byte, bool /* updated */) {
+	updated := false
+	replacement := []byte("${1}${2}:" + tag + "${4}")
+	result := imageReferenceRegex.ReplaceAllFunc(data, func(match []byte) []byte {
+		sub := imageReferenceRegex.FindSubmatch(match)
+		if string(sub[2]) != image || string(sub[3]) == tag {
+			return match
+		}
+		updated = true
+		return imageReferenceRegex.Expand(nil, replacement, match, imageReferenceRegex.FindSubmatchIndex(match))
+	})
+	return result, updated
+}
+
+// imageUpdateMessage returns a list of the updates for commit messages and merge request descriptions.
+func imageUpdateMessage(updates []imageUpdate) string {
+	var msg strings.Builder
+	for _, update := range updates {
+		fmt.Fprintf(&msg, "- %s:%s\n", update.Image, update.Tag)
+	}
+	return msg.String()
+}
+
+// imageUpdateBranchName returns a deterministic branch name for the updates.
+// The same updates are always proposed from the same branch.
+func imageUpdateBranchName(updates []imageUpdate) string {
+	sorted := make([]imageUpdate, len(updates))
+	copy(sorted, updates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Image < sorted[j].Image
+	})
+	h := sha256.New()
+	for _, update := range sorted {
+		h.Write([]byte(update.Image)) // nolint: errcheck
+		h.Write([]byte{0})            // nolint: errcheck
+		h.Write([]byte(update.Tag))   // nolint: errcheck
+		h.Write([]byte{0})            // nolint: errcheck
+	}
+	return imageUpdateBranchPrefix + hex.EncodeToString(h.Sum(nil))[:imageUpdateBranchHashLen]
+}
@@ -0,0 +1,270 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUpdateImageReferences(t *testing.T) {
+	const image = "registry.gitlab.com/group/app"
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name:     "yaml",
+			data:     "containers:\n- name: app\n  image: registry.gitlab.com/group/app:v1.0.0\n- image: registry.gitlab.com/group/other:v1.0.0\n",
+			expected: "containers:\n- name: app\n  image: registry.gitlab.com/group/app:v1.2.0\n- image: registry.gitlab.com/group/other:v1.0.0\n",
+		},
+		{
+			name:     "yaml list item quoted",
+			data:     "containers:\n- image: 'registry.gitlab.com/group/app:v1.0.0' # comment\n",
+			expected: "containers:\n- image: 'registry.gitlab.com/group/app:v1.2.0' # comment\n",
+		},
+		{
+			name:     "json",
+			data:     `{"containers":[{"name":"app","image":"registry.gitlab.com/group/app:v1.0.0"}]}`,
+			expected: `{"containers":[{"name":"app","image":"registry.gitlab.com/group/app:v1.2.0"}]}`,
+		},
+		{
+			name:     "longer image name",
+			data:     "image: registry.gitlab.com/group/app-worker:v1.0.0\n",
+			expected: "image: registry.gitlab.com/group/app-worker:v1.0.0\n",
+		},
+		{
+			name:     "digest",
+			data:     "image: registry.gitlab.com/group/app:v1.0.0@sha256:0000000000000000000000000000000000000000000000000000000000000000\n",
+			expected: "image: registry.gitlab.com/group/app:v1.0.0@sha256:0000000000000000000000000000000000000000000000000000000000000000\n",
+		},
+		{
+			name:     "up to date",
+			data:     "image: registry.gitlab.com/group/app:v1.2.0\n",
+			expected: "image: registry.gitlab.com/group/app:v1.2.0\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, updated := updateImageReferences([]byte(tc.data), image, "v1.2.0") // nolint: scopelint
+			assert.Equal(t, tc.expected, string(actual))                               // nolint: scopelint
+			assert.Equal(t, tc.data != tc.expected, updated)                           // nolint: scopelint
+		})
+	}
+}
+
+func TestSelectTag(t *testing.T) {
+	tags := []string{"latest", "v1.0.0", "v1.10.0", "v1.9.0", "v2.0.0", "main-20201101120000", "main-20201102120000"}
+	tag, err := selectTag(&agentcfg.ImagePolicyCF{SemverRange: "~1"}, tags)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.10.0", tag)
+
+	tag, err = selectTag(&agentcfg.ImagePolicyCF{TagRegex: "^main-[0-9]{14}$"}, tags)
+	require.NoError(t, err)
+	assert.Equal(t, "main-20201102120000", tag)
+
+	tag, err = selectTag(&agentcfg.ImagePolicyCF{SemverRange: ">= 3"}, tags)
+	require.NoError(t, err)
+	assert.Empty(t, tag)
+}
+
+// appFileCommitId is the last commit that changed app.yaml.
+const appFileCommitId = "0685d8507ebc6de9bcac25628aa7afd52802a91a"
+
+func TestImageUpdater_CommitsUpdates(t *testing.T) {
+	gl, updater := setupImageUpdater(t, false)
+	ctx := context.Background()
+	require.NoError(t, updater.update(ctx)) // no manifests yet
+	gl.branchCommitId = gitHash1
+	updater.setManifests(gl.manifests())
+	require.NoError(t, updater.update(ctx))
+	require.Len(t, gl.commits, 1)
+	assert.Equal(t, gitLabCreateCommit{
+		Branch:        "main",
+		CommitMessage: imageUpdateCommitMessage + "- " + gl.image + ":v1.1.0\n",
+		Actions: []gitLabCommitAction{
+			{
+				Action:       "update",
+				FilePath:     "app.yaml",
+				Content:      "image: " + gl.image + ":v1.1.0\n",
+				LastCommitId: appFileCommitId,
+			},
+		},
+	}, gl.commits[0])
+
+	gl.branchCommitId = gitHash2            // the commit above
+	require.NoError(t, updater.update(ctx)) // stale manifests are not committed again
+	assert.Len(t, gl.commits, 1)
+}
+
+func TestImageUpdater_ProposesUpdatesOnce(t *testing.T) {
+	gl, updater := setupImageUpdater(t, true)
+	ctx := context.Background()
+	updater.setManifests(gl.manifests())
+	require.NoError(t, updater.update(ctx))
+	branch := imageUpdateBranchName([]imageUpdate{{Image: gl.image, Tag: "v1.1.0"}})
+	require.Len(t, gl.commits, 1)
+	assert.Equal(t, gitLabCreateCommit{
+		Branch:        branch,
+		StartSha:      gitHash1,
+		Force:         true,
+		CommitMessage: imageUpdateCommitMessage + "- " + gl.image + ":v1.1.0\n",
+		Actions: []gitLabCommitAction{
+			{
+				Action:   "update",
+				FilePath: "app.yaml",
+				Content:  "image: " + gl.image + ":v1.1.0\n",
+			},
+		},
+	}, gl.commits[0])
+	require.Len(t, gl.mergeRequests, 1)
+	assert.Equal(t, gitLabCreateMergeRequest{
+		SourceBranch:       branch,
+		TargetBranch:       "main",
+		Title:              imageUpdateMergeRequestTitle,
+		Description:        "- " + gl.image + ":v1.1.0\n",
+		RemoveSourceBranch: true,
+	}, gl.mergeRequests[0])
+
+	// A new updater, e.g. after a restart, does not propose the same updates again.
+	updater = &imageUpdater{
+		log:             updater.log,
+		projectId:       updater.projectId,
+		automation:      updater.automation,
+		httpClient:      updater.httpClient,
+		kubeClient:      updater.kubeClient,
+		secretNamespace: updater.secretNamespace,
+	}
+	updater.setManifests(gl.manifests())
+	require.NoError(t, updater.update(ctx))
+	assert.Len(t, gl.commits, 1)
+	assert.Len(t, gl.mergeRequests, 1)
+}
+
+func TestImageUpdateBranchName(t *testing.T) {
+	a := imageUpdate{Image: "registry.gitlab.com/group/app", Tag: "v1.1.0"}
+	b := imageUpdate{Image: "registry.gitlab.com/group/worker", Tag: "v2.0.0"}
+	name := imageUpdateBranchName([]imageUpdate{a, b})
+	assert.Equal(t, name, imageUpdateBranchName([]imageUpdate{b, a}))
+	assert.NotEqual(t, name, imageUpdateBranchName([]imageUpdate{a}))
+	assert.Len(t, name, len(imageUpdateBranchPrefix)+imageUpdateBranchHashLen)
+}
+
+// fakeImageUpdateServer is a container registry and a GitLab API server.
+type fakeImageUpdateServer struct {
+	t              *testing.T
+	image          string
+	branchCommitId string
+	commits        []gitLabCreateCommit
+	mergeRequests  []gitLabCreateMergeRequest
+}
+
+func (s *fakeImageUpdateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const projectPath = "/api/v4/projects/group%2Fmanifests"
+	if strings.HasPrefix(r.URL.EscapedPath(), "/api/") {
+		assert.Equal(s.t, "glpat-token", r.Header.Get("PRIVATE-TOKEN"))
+	}
+	switch r.Method + " " + r.URL.EscapedPath() {
+	case "GET /v2/group/app/tags/list":
+		_, _ = w.Write([]byte(`{"name":"group/app","tags":["v1.0.0","v1.1.0","v2.0.0"]}`))
+	case "GET " + projectPath:
+		_, _ = w.Write([]byte(`{"default_branch":"main"}`))
+	case "GET " + projectPath + "/repository/branches/main":
+		_, _ = fmt.Fprintf(w, `{"commit":{"id":%q}}`, s.branchCommitId)
+	case "GET " + projectPath + "/repository/files/app.yaml":
+		assert.Equal(s.t, gitHash1, r.URL.Query().Get("ref"))
+		_, _ = fmt.Fprintf(w, `{"file_path":"app.yaml","last_commit_id":%q}`, appFileCommitId)
+	case "POST " + projectPath + "/repository/commits":
+		var commit gitLabCreateCommit
+		assert.NoError(s.t, json.NewDecoder(r.Body).Decode(&commit))
+		s.commits = append(s.commits, commit)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	case "GET " + projectPath + "/merge_requests":
+		assert.Equal(s.t, "all", r.URL.Query().Get("state"))
+		var mrs []gitLabMergeRequest
+		for i, mr := range s.mergeRequests {
+			if mr.SourceBranch == r.URL.Query().Get("source_branch") {
+				mrs = append(mrs, gitLabMergeRequest{Iid: int64(i + 1), State: "opened"})
+			}
+		}
+		assert.NoError(s.t, json.NewEncoder(w).Encode(mrs))
+	case "POST " + projectPath + "/merge_requests":
+		var mr gitLabCreateMergeRequest
+		assert.NoError(s.t, json.NewDecoder(r.Body).Decode(&mr))
+		s.mergeRequests = append(s.mergeRequests, mr)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"iid":%d,"state":"opened"}`, len(s.mergeRequests))
+	default:
+		s.t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeImageUpdateServer) manifests() rpc.ObjectsToSynchronizeData {
+	return rpc.ObjectsToSynchronizeData{
+		CommitId: gitHash1,
+		Sources: []rpc.ObjectSource{
+			{
+				Name: "app.yaml",
+				Data: []byte("image: " + s.image + ":v1.0.0\n"),
+			},
+			{
+				Name: "other.yaml",
+				Data: []byte("image: " + strings.TrimSuffix(s.image, "/app") + "/other:v1.0.0\n"),
+			},
+		},
+	}
+}
+
+func setupImageUpdater(t *testing.T, createMergeRequest bool) (*fakeImageUpdateServer, *imageUpdater) {
+	gl := &fakeImageUpdateServer{
+		t: t,
+	}
+	srv := httptest.NewTLSServer(gl) // requests are sequential, no locking needed
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	gl.image = u.Host + "/group/app"
+	updater := &imageUpdater{
+		log:       zaptest.NewLogger(t),
+		projectId: "group/manifests",
+		automation: &agentcfg.ImageUpdateAutomationCF{
+			Images: []*agentcfg.ImagePolicyCF{
+				{
+					Image:       gl.image,
+					SemverRange: "~1",
+				},
+			},
+			CreateMergeRequest: createMergeRequest,
+			GitlabUrl:          srv.URL,
+			TokenSecret:        "gitlab-token",
+		},
+		httpClient: srv.Client(),
+		kubeClient: fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gitlab-token",
+				Namespace: "agentk",
+			},
+			Data: map[string][]byte{
+				secretKeyToken: []byte("glpat-token"),
+			},
+		}),
+		secretNamespace: "agentk",
+	}
+	return gl, updater
+}
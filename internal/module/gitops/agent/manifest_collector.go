@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v2"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+)
+
+const (
+	// Same limits as gitlab-kas applies by default to manifests from GitLab projects.
+	maxManifestFileSize      = 1024 * 1024
+	maxTotalManifestFileSize = 2 * 1024 * 1024
+	maxNumberOfManifestFiles = 1000
+)
+
+// manifestCollector collects manifest files for sources that agentk fetches directly, without gitlab-kas.
+// Only files matching any of the globs are collected. Files in directories with names starting with a dot are ignored.
+type manifestCollector struct {
+	paths                  []*agentcfg.PathCF
+	remainingTotalFileSize int64
+	sources                []rpc.ObjectSource
+}
+
+func newManifestCollector(paths []*agentcfg.PathCF) *manifestCollector {
+	return &manifestCollector{
+		paths:                  paths,
+		remainingTotalFileSize: maxTotalManifestFileSize,
+	}
+}
+
+// Wants checks if a file with the given name should be collected.
+// An error is returned if collecting the file of the given size would exceed a limit.
+func (c *manifestCollector) Wants(name string, size int64) (bool, error) {
+	if isHiddenDir(name) {
+		return false, nil
+	}
+	match, err := matchesAnyPath(c.paths, name)
+	if err != nil || !match {
+		return false, err
+	}
+	if len(c.sources) == maxNumberOfManifestFiles {
+		return false, fmt.Errorf("maximum number of manifest files limit reached: %d", maxNumberOfManifestFiles)
+	}
+	if size > maxManifestFileSize {
+		return false, fmt.Errorf("file %s is bigger than the maximum manifest file size: %d", name, maxManifestFileSize)
+	}
+	if size > c.remainingTotalFileSize {
+		return false, fmt.Errorf("maximum total size of manifest files limit reached: %d", maxTotalManifestFileSize)
+	}
+	return true, nil
+}
+
+// Add adds a file. Wants must be called first to check that the file is wanted.
+func (c *manifestCollector) Add(name string, data []byte) {
+	c.remainingTotalFileSize -= int64(len(data))
+	c.sources = append(c.sources, rpc.ObjectSource{
+		Name: name,
+		Data: data,
+	})
+}
+
+func matchesAnyPath(paths []*agentcfg.PathCF, filename string) (bool, error) {
+	for _, p := range paths {
+		glob := strings.TrimPrefix(p.Glob, "/")
+		match, err := doublestar.Match(glob, filename)
+		if err != nil {
+			return false, fmt.Errorf("glob %s match failed: %v", p.Glob, err)
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isHiddenDir checks if a file is in a directory, which name starts with a dot.
+func isHiddenDir(filename string) bool {
+	dir := path.Dir(filename)
+	if dir == "." { // root directory special case
+		return false
+	}
+	for _, part := range strings.Split(dir, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
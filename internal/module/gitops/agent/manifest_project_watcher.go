@@ -0,0 +1,219 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// manifestProjectAPICheckPeriod is how often to check if the ManifestProject API is served, until it is.
+	manifestProjectAPICheckPeriod = time.Minute
+)
+
+var (
+	manifestProjectGVR = schema.GroupVersionResource{
+		Group:    "gitops.agent.gitlab.com",
+		Version:  "v1alpha1",
+		Resource: "manifestprojects",
+	}
+)
+
+// manifestProjectWatcher watches ManifestProject custom resources.
+type manifestProjectWatcher struct {
+	log           *zap.Logger
+	dynamicClient dynamic.Interface
+	discovery     discovery.DiscoveryInterface
+	// apiCheckPeriod is how often to check if the ManifestProject API is served.
+	apiCheckPeriod time.Duration
+}
+
+// Run calls the callback with the full set of valid manifest projects, keyed by namespace/project id,
+// once the initial list has been received and then each time the set of custom resources changes.
+// Only the given namespaces are watched. All namespaces are watched if the list is empty.
+// Informers are only started once the ManifestProject API is served, i.e. the CRD has been installed.
+// Run blocks until ctx is done.
+func (w *manifestProjectWatcher) Run(ctx context.Context, namespaces []string, callback func(map[string]namespacedManifestProject)) {
+	if !w.waitForAPI(ctx) {
+		return // context is done
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default: // a notification is already pending
+		}
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			notify()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			notify()
+		},
+		DeleteFunc: func(obj interface{}) {
+			notify()
+		},
+	}
+	var wg wait.Group
+	defer wg.Wait()
+	informers := make([]cache.SharedIndexInformer, 0, len(namespaces))
+	synced := make([]cache.InformerSynced, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(w.dynamicClient, 0, namespace, nil)
+		informer := factory.ForResource(manifestProjectGVR).Informer()
+		informer.AddEventHandler(handler)
+		wg.StartWithChannel(ctx.Done(), informer.Run)
+		informers = append(informers, informer)
+		synced = append(synced, informer.HasSynced)
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return // context is done
+	}
+	notify() // report the initial list, even if it is empty
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+		var objs []interface{}
+		for _, informer := range informers {
+			objs = append(objs, informer.GetStore().List()...)
+		}
+		callback(w.projects(objs))
+	}
+}
+
+// waitForAPI polls the discovery API until the ManifestProject resource is served.
+// It returns false if ctx is done.
+func (w *manifestProjectWatcher) waitForAPI(ctx context.Context) bool {
+	logged := false
+	err := retry.PollImmediateUntil(ctx, w.apiCheckPeriod, func() (bool /*done*/, error) {
+		served, err := isAPIServed(w.discovery, manifestProjectGVR)
+		switch {
+		case err != nil:
+			w.log.Warn("Failed to check if ManifestProject API is served", zap.Error(err))
+		case !served && !logged:
+			w.log.Info("ManifestProject custom resource definition is not installed, waiting for it")
+			logged = true
+		}
+		return served, nil // nil error to keep polling
+	})
+	return err == nil
+}
+
+// isAPIServed returns true if the API server serves the resource.
+func isAPIServed(discovery discovery.DiscoveryInterface, gvr schema.GroupVersionResource) (bool, error) {
+	resources, err := discovery.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == gvr.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (w *manifestProjectWatcher) projects(objs []interface{}) map[string]namespacedManifestProject {
+	result := make(map[string]namespacedManifestProject, len(objs))
+	for _, o := range objs {
+		obj, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		l := w.log.With(logz.Namespace(obj.GetNamespace()), logz.ObjectName(obj.GetName()))
+		project, err := parseManifestProject(obj)
+		if err != nil {
+			l.Error("Invalid ManifestProject", zap.Error(err))
+			continue
+		}
+		key := obj.GetNamespace() + "/" + project.Id
+		if _, ok := result[key]; ok {
+			l.Error("Duplicate ManifestProject for project in namespace, ignoring", logz.ProjectId(project.Id))
+			continue
+		}
+		result[key] = namespacedManifestProject{
+			project:           project,
+			namespace:         obj.GetNamespace(),
+			allowedNamespaces: []string{obj.GetNamespace()},
+		}
+	}
+	return result
+}
+
+// parseManifestProject converts the spec of a ManifestProject custom resource into a validated
+// manifest project configuration, restricted to the namespace of the custom resource.
+func parseManifestProject(obj *unstructured.Unstructured) (*agentcfg.ManifestProjectCF, error) {
+	spec, ok := obj.Object["spec"]
+	if !ok {
+		return nil, errors.New("spec is missing")
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %v", err)
+	}
+	project := &agentcfg.ManifestProjectCF{}
+	err = protojson.Unmarshal(data, project)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	err = project.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	namespace := obj.GetNamespace()
+	if project.DefaultNamespace != "" && project.DefaultNamespace != namespace {
+		return nil, fmt.Errorf("default_namespace must be empty or %s", namespace)
+	}
+	project.DefaultNamespace = namespace
+	// Features below either affect the whole cluster, run code in the agent or make the agent connect to arbitrary
+	// hosts, they are only available in the agent's configuration file.
+	switch {
+	case project.GitRemote != nil:
+		return nil, errors.New("git_remote cannot be used in a ManifestProject")
+	case project.OciArtifact != nil:
+		return nil, errors.New("oci_artifact cannot be used in a ManifestProject")
+	case project.PreviewEnvironments != nil:
+		return nil, errors.New("preview_environments cannot be used in a ManifestProject")
+	case project.ImageUpdateAutomation != nil:
+		return nil, errors.New("image_update_automation cannot be used in a ManifestProject")
+	case project.Cluster != "":
+		return nil, errors.New("cluster cannot be used in a ManifestProject")
+	}
+	for _, path := range project.Paths {
+		if path.Renderer != nil {
+			return nil, fmt.Errorf("glob %s: renderer cannot be used in a ManifestProject", path.Glob)
+		}
+	}
+	err = defaultAndValidateManifestProject(project)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
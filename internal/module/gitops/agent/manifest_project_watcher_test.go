@@ -0,0 +1,303 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestParseManifestProject(t *testing.T) {
+	project, err := parseManifestProject(manifestProjectObject("ns1", "app", map[string]interface{}{
+		"id": "group/project",
+		"paths": []interface{}{
+			map[string]interface{}{
+				"glob": "/manifests/**",
+			},
+		},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "group/project", project.Id)
+	assert.Equal(t, "ns1", project.DefaultNamespace)
+	assert.Equal(t, "/manifests/**", project.Paths[0].Glob)
+}
+
+func TestParseManifestProjectErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        map[string]interface{}
+		expectedErr string
+	}{
+		{
+			name:        "no spec",
+			expectedErr: "spec is missing",
+		},
+		{
+			name: "no id",
+			spec: map[string]interface{}{
+				"default_namespace": "ns1",
+			},
+			expectedErr: "invalid spec: invalid ManifestProjectCF.Id: value length must be at least 1 runes",
+		},
+		{
+			name: "other namespace",
+			spec: map[string]interface{}{
+				"id":                "group/project",
+				"default_namespace": "kube-system",
+			},
+			expectedErr: "default_namespace must be empty or ns1",
+		},
+		{
+			name: "preview environments",
+			spec: map[string]interface{}{
+				"id": "group/project",
+				"preview_environments": map[string]interface{}{
+					"branch_glob": "*",
+				},
+			},
+			expectedErr: "preview_environments cannot be used in a ManifestProject",
+		},
+		{
+			name: "cluster",
+			spec: map[string]interface{}{
+				"id":      "group/project",
+				"cluster": "edge1",
+			},
+			expectedErr: "cluster cannot be used in a ManifestProject",
+		},
+		{
+			name: "git_remote",
+			spec: map[string]interface{}{
+				"id": "group/project",
+				"git_remote": map[string]interface{}{
+					"url": "https://example.com/repo.git",
+				},
+			},
+			expectedErr: "git_remote cannot be used in a ManifestProject",
+		},
+		{
+			name: "oci_artifact",
+			spec: map[string]interface{}{
+				"id": "group/project",
+				"oci_artifact": map[string]interface{}{
+					"ref": "registry.example.com/group/manifests:main",
+				},
+			},
+			expectedErr: "oci_artifact cannot be used in a ManifestProject",
+		},
+		{
+			name: "renderer",
+			spec: map[string]interface{}{
+				"id": "group/project",
+				"paths": []interface{}{
+					map[string]interface{}{
+						"glob": "/jsonnet/**",
+						"renderer": map[string]interface{}{
+							"command": []interface{}{"jsonnet"},
+						},
+					},
+				},
+			},
+			expectedErr: "glob /jsonnet/**: renderer cannot be used in a ManifestProject",
+		},
+		{
+			name: "invalid semver constraint",
+			spec: map[string]interface{}{
+				"id":                    "group/project",
+				"semver_tag_constraint": "bla bla",
+			},
+			expectedErr: `invalid semver_tag_constraint "bla bla": improper constraint: bla bla`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := manifestProjectObject("ns1", "app", tc.spec) // nolint: scopelint
+			if tc.spec == nil {                                 // nolint: scopelint
+				delete(obj.Object, "spec")
+			}
+			_, err := parseManifestProject(obj)
+			assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+		})
+	}
+}
+
+func TestManifestProjectWatcher(t *testing.T) {
+	dynamicClient := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			manifestProjectGVR: "ManifestProjectList",
+		},
+		manifestProjectObject("ns1", "app", map[string]interface{}{
+			"id": "group/project",
+		}),
+		manifestProjectObject("ns1", "app-duplicate", map[string]interface{}{
+			"id": "group/project",
+		}),
+		manifestProjectObject("ns2", "app", map[string]interface{}{
+			"id": "group/project",
+		}),
+		manifestProjectObject("ns2", "invalid", map[string]interface{}{
+			"id":                "group/project2",
+			"default_namespace": "ns1",
+		}),
+	)
+	w := &manifestProjectWatcher{
+		log:            zaptest.NewLogger(t),
+		dynamicClient:  dynamicClient,
+		discovery:      manifestProjectDiscovery(),
+		apiCheckPeriod: time.Minute,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	projects := make(chan map[string]namespacedManifestProject)
+	var wg wait.Group
+	defer wg.Wait()
+	defer cancel()
+	wg.StartWithContext(ctx, func(ctx context.Context) {
+		w.Run(ctx, nil, func(p map[string]namespacedManifestProject) {
+			select {
+			case <-ctx.Done():
+			case projects <- p:
+			}
+		})
+	})
+
+	var p map[string]namespacedManifestProject
+	select {
+	case <-ctx.Done():
+		require.FailNow(t, ctx.Err().Error())
+	case p = <-projects:
+	}
+	require.Len(t, p, 2)
+	assert.Equal(t, "ns1", p["ns1/group/project"].namespace)
+	assert.Equal(t, "ns2", p["ns2/group/project"].namespace)
+	assert.Equal(t, "ns2", p["ns2/group/project"].project.DefaultNamespace)
+
+	err := dynamicClient.Resource(manifestProjectGVR).Namespace("ns2").Delete(ctx, "app", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	select {
+	case <-ctx.Done():
+		require.FailNow(t, ctx.Err().Error())
+	case p = <-projects:
+	}
+	require.Len(t, p, 1)
+	expected := &agentcfg.ManifestProjectCF{
+		Id:               "group/project",
+		DefaultNamespace: "ns1",
+	}
+	applyDefaultsToManifestProject(expected)
+	assert.True(t, proto.Equal(expected, p["ns1/group/project"].project))
+}
+
+func TestManifestProjectWatcherNamespaces(t *testing.T) {
+	w := &manifestProjectWatcher{
+		log: zaptest.NewLogger(t),
+		dynamicClient: fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				manifestProjectGVR: "ManifestProjectList",
+			},
+			manifestProjectObject("ns1", "app", map[string]interface{}{
+				"id": "group/project",
+			}),
+			manifestProjectObject("ns2", "app", map[string]interface{}{
+				"id": "group/project",
+			}),
+			manifestProjectObject("ns3", "app", map[string]interface{}{
+				"id": "group/project",
+			}),
+		),
+		discovery:      manifestProjectDiscovery(),
+		apiCheckPeriod: time.Minute,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var p map[string]namespacedManifestProject
+	w.Run(ctx, []string{"ns1", "ns3"}, func(projects map[string]namespacedManifestProject) {
+		p = projects
+		cancel()
+	})
+	require.Len(t, p, 2)
+	assert.Contains(t, p, "ns1/group/project")
+	assert.Contains(t, p, "ns3/group/project")
+}
+
+func TestManifestProjectWatcherWaitsForAPI(t *testing.T) {
+	d := &fakediscovery.FakeDiscovery{
+		Fake: &k8stesting.Fake{},
+	}
+	checks := 0
+	d.AddReactor("get", "resource", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		checks++
+		if checks == 3 { // CRD is installed after a few checks
+			d.Resources = manifestProjectDiscovery().Resources
+		}
+		return false, nil, nil
+	})
+	w := &manifestProjectWatcher{
+		log: zaptest.NewLogger(t),
+		dynamicClient: fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				manifestProjectGVR: "ManifestProjectList",
+			},
+			manifestProjectObject("ns1", "app", map[string]interface{}{
+				"id": "group/project",
+			}),
+		),
+		discovery:      d,
+		apiCheckPeriod: 10 * time.Millisecond,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var p map[string]namespacedManifestProject
+	w.Run(ctx, nil, func(projects map[string]namespacedManifestProject) {
+		p = projects
+		cancel()
+	})
+	assert.Equal(t, 3, checks)
+	require.Len(t, p, 1)
+	assert.Contains(t, p, "ns1/group/project")
+}
+
+func manifestProjectDiscovery() *fakediscovery.FakeDiscovery {
+	return &fakediscovery.FakeDiscovery{
+		Fake: &k8stesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: manifestProjectGVR.GroupVersion().String(),
+					APIResources: []metav1.APIResource{
+						{
+							Name:       manifestProjectGVR.Resource,
+							Namespaced: true,
+							Kind:       "ManifestProject",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func manifestProjectObject(namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	obj.SetAPIVersion(manifestProjectGVR.GroupVersion().String())
+	obj.SetKind("ManifestProject")
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricNamespace = "gitlab_agent"
+	metricSubsystem = "gitops"
+
+	metricProjectIdLabel = "project_id"
+	// metricNamespaceLabel is the namespace of the ManifestProject object. Empty for projects from the configuration file.
+	metricNamespaceLabel = "namespace"
+	// metricBranchLabel is the branch of a preview environment. Empty for the default branch.
+	metricBranchLabel = "branch"
+	metricResultLabel = "result"
+	metricCommitLabel = "commit_id"
+
+	metricResultApplied = "applied"
+	metricResultPruned  = "pruned"
+	metricResultFailed  = "failed"
+)
+
+var (
+	projectLabels = []string{metricProjectIdLabel, metricNamespaceLabel, metricBranchLabel}
+)
+
+// gitopsMetrics holds Prometheus metrics of all GitOps workers.
+type gitopsMetrics struct {
+	lastSuccessfulSync   *prometheus.GaugeVec
+	syncDuration         *prometheus.HistogramVec
+	syncObjects          *prometheus.CounterVec
+	commitInfo           *prometheus.GaugeVec
+	driftedObjects       *prometheus.GaugeVec
+	receiveToSyncLatency *prometheus.HistogramVec
+}
+
+func newGitopsMetrics() *gitopsMetrics {
+	return &gitopsMetrics{
+		lastSuccessfulSync: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: metricSubsystem,
+			Name:      "last_successful_sync_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful synchronization of the project.",
+		}, projectLabels),
+		syncDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: metricSubsystem,
+			Name:      "sync_duration_seconds",
+			Help:      "Duration of synchronizations of the project.",
+			Buckets:   []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		}, projectLabels),
+		syncObjects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: metricSubsystem,
+			Name:      "sync_objects_total",
+			Help:      "Number of objects that have been applied, including unchanged ones, pruned or failed to synchronize.",
+		}, []string{metricProjectIdLabel, metricNamespaceLabel, metricBranchLabel, metricResultLabel}),
+		commitInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: metricSubsystem,
+			Name:      "commit_info",
+			Help:      "Commit that has been synchronized successfully last. The value is always 1.",
+		}, []string{metricProjectIdLabel, metricNamespaceLabel, metricBranchLabel, metricCommitLabel}),
+		driftedObjects: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: metricSubsystem,
+			Name:      "drifted_objects",
+			Help:      "Number of objects, the live state of which differs from the last synchronized commit.",
+		}, projectLabels),
+		receiveToSyncLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: metricSubsystem,
+			Name:      "receive_to_sync_latency_seconds",
+			Help:      "Time from receiving a new commit from kas or the source to synchronizing it successfully.",
+			Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+		}, projectLabels),
+	}
+}
+
+func (m *gitopsMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.lastSuccessfulSync,
+		m.syncDuration,
+		m.syncObjects,
+		m.commitInfo,
+		m.driftedObjects,
+		m.receiveToSyncLatency,
+	}
+}
+
+// forProject returns metrics of a worker. Returns nil if m is nil.
+func (m *gitopsMetrics) forProject(projectId, namespace, branch string) *projectMetrics {
+	if m == nil {
+		return nil
+	}
+	return &projectMetrics{
+		metrics: m,
+		labels: prometheus.Labels{
+			metricProjectIdLabel: projectId,
+			metricNamespaceLabel: namespace,
+			metricBranchLabel:    branch,
+		},
+	}
+}
+
+// projectMetrics records metrics of a single worker.
+// All methods are safe to call on a nil instance, they are no-op then.
+type projectMetrics struct {
+	metrics *gitopsMetrics
+	labels  prometheus.Labels
+
+	mu sync.Mutex
+	// appliedCommitId is the commit in the commit info metric.
+	appliedCommitId string
+}
+
+// syncFinished records a finished synchronization of a commit.
+// received is when the commit has been received, started is when the synchronization has been started.
+func (p *projectMetrics) syncFinished(commitId string, received, started time.Time, results []common.ResourceSyncResult, err error) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	p.metrics.syncDuration.With(p.labels).Observe(now.Sub(started).Seconds())
+	if err != nil {
+		return
+	}
+	var applied, pruned, failed int
+	for _, res := range results {
+		if res.HookType != "" {
+			continue
+		}
+		switch res.Status {
+		case common.ResultCodeSynced:
+			applied++
+		case common.ResultCodePruned:
+			pruned++
+		case common.ResultCodeSyncFailed:
+			failed++
+		}
+	}
+	p.metrics.syncObjects.With(p.withLabel(metricResultLabel, metricResultApplied)).Add(float64(applied))
+	p.metrics.syncObjects.With(p.withLabel(metricResultLabel, metricResultPruned)).Add(float64(pruned))
+	p.metrics.syncObjects.With(p.withLabel(metricResultLabel, metricResultFailed)).Add(float64(failed))
+	if failed > 0 {
+		return
+	}
+	p.metrics.lastSuccessfulSync.With(p.labels).Set(float64(now.UnixNano()) / float64(time.Second))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.appliedCommitId == commitId {
+		return // the same commit has been received and synchronized again, e.g. after a reconnect to kas
+	}
+	if p.appliedCommitId != "" {
+		p.metrics.commitInfo.Delete(p.withLabel(metricCommitLabel, p.appliedCommitId))
+	}
+	p.appliedCommitId = commitId
+	p.metrics.commitInfo.With(p.withLabel(metricCommitLabel, commitId)).Set(1)
+	p.metrics.receiveToSyncLatency.With(p.labels).Observe(now.Sub(received).Seconds())
+}
+
+func (p *projectMetrics) setDriftedObjects(n int) {
+	if p == nil {
+		return
+	}
+	p.metrics.driftedObjects.With(p.labels).Set(float64(n))
+}
+
+// delete removes the metrics of the worker. Must be called when the worker stops.
+func (p *projectMetrics) delete() {
+	if p == nil {
+		return
+	}
+	p.metrics.lastSuccessfulSync.Delete(p.labels)
+	p.metrics.syncDuration.Delete(p.labels)
+	for _, result := range []string{metricResultApplied, metricResultPruned, metricResultFailed} {
+		p.metrics.syncObjects.Delete(p.withLabel(metricResultLabel, result))
+	}
+	p.metrics.driftedObjects.Delete(p.labels)
+	p.metrics.receiveToSyncLatency.Delete(p.labels)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.appliedCommitId != "" {
+		p.metrics.commitInfo.Delete(p.withLabel(metricCommitLabel, p.appliedCommitId))
+		p.appliedCommitId = ""
+	}
+}
+
+func (p *projectMetrics) withLabel(name, value string) prometheus.Labels {
+	labels := make(prometheus.Labels, len(p.labels)+1)
+	for k, v := range p.labels {
+		labels[k] = v
+	}
+	labels[name] = value
+	return labels
+}
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectMetricsSyncFinished(t *testing.T) {
+	m := newGitopsMetrics()
+	p := m.forProject("group/project", "", "")
+	received := time.Now().Add(-time.Minute)
+	started := time.Now().Add(-time.Second)
+
+	p.syncFinished(gitHash1, received, started, []common.ResourceSyncResult{
+		{
+			ResourceKey: kube.NewResourceKey("apps", "Deployment", "ns", "app"),
+			Status:      common.ResultCodeSynced,
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "cm"),
+			Status:      common.ResultCodeSynced,
+			Message:     "configmap/cm unchanged",
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "old"),
+			Status:      common.ResultCodePruned,
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "kept"),
+			Status:      common.ResultCodePruneSkipped,
+		},
+	}, nil)
+	assert.EqualValues(t, 2, testutil.ToFloat64(m.syncObjects.With(p.withLabel(metricResultLabel, metricResultApplied))))
+	assert.EqualValues(t, 1, testutil.ToFloat64(m.syncObjects.With(p.withLabel(metricResultLabel, metricResultPruned))))
+	assert.EqualValues(t, 0, testutil.ToFloat64(m.syncObjects.With(p.withLabel(metricResultLabel, metricResultFailed))))
+	assert.EqualValues(t, 1, testutil.ToFloat64(m.commitInfo.With(p.withLabel(metricCommitLabel, gitHash1))))
+	assert.Greater(t, testutil.ToFloat64(m.lastSuccessfulSync.With(p.labels)), float64(received.Unix()))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.receiveToSyncLatency))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.syncDuration))
+
+	// Failed synchronization doesn't change the synchronized commit
+	p.syncFinished(gitHash2, received, started, nil, errors.New("boom"))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.commitInfo))
+	assert.EqualValues(t, 1, testutil.ToFloat64(m.commitInfo.With(p.withLabel(metricCommitLabel, gitHash1))))
+
+	p.syncFinished(gitHash2, received, started, nil, nil)
+	assert.Equal(t, 1, testutil.CollectAndCount(m.commitInfo))
+	assert.EqualValues(t, 1, testutil.ToFloat64(m.commitInfo.With(p.withLabel(metricCommitLabel, gitHash2))))
+
+	p.setDriftedObjects(3)
+	assert.EqualValues(t, 3, testutil.ToFloat64(m.driftedObjects.With(p.labels)))
+
+	p.delete()
+	for _, c := range m.collectors() {
+		assert.Zero(t, testutil.CollectAndCount(c))
+	}
+}
+
+func TestProjectMetricsSeparateProjects(t *testing.T) {
+	m := newGitopsMetrics()
+	p1 := m.forProject("group/project", "", "")
+	p2 := m.forProject("group/project", "", "feature")
+	p1.setDriftedObjects(1)
+	p2.setDriftedObjects(2)
+	assert.Equal(t, 2, testutil.CollectAndCount(m.driftedObjects))
+	p2.delete()
+	assert.Equal(t, 1, testutil.CollectAndCount(m.driftedObjects))
+	assert.EqualValues(t, 1, testutil.ToFloat64(m.driftedObjects.With(p1.labels)))
+}
+
+func TestProjectMetricsNil(t *testing.T) {
+	var m *gitopsMetrics
+	p := m.forProject("group/project", "", "")
+	p.syncFinished(gitHash1, time.Now(), time.Now(), nil, nil)
+	p.setDriftedObjects(1)
+	p.delete()
+}
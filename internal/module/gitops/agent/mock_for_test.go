@@ -12,6 +12,7 @@ import (
 	engine "github.com/argoproj/gitops-engine/pkg/engine"
 	gomock "github.com/golang/mock/gomock"
 	agentcfg "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	rest "k8s.io/client-go/rest"
 )
 
 // MockGitopsEngineFactory is a mock of GitopsEngineFactory interface.
@@ -38,17 +39,17 @@ func (m *MockGitopsEngineFactory) EXPECT() *MockGitopsEngineFactoryMockRecorder
 }
 
 // New mocks base method.
-func (m *MockGitopsEngineFactory) New(arg0 []engine.Option, arg1 []cache.UpdateSettingsFunc) engine.GitOpsEngine {
+func (m *MockGitopsEngineFactory) New(arg0 *rest.Config, arg1 []engine.Option, arg2 cache.ClusterCache) engine.GitOpsEngine {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "New", arg0, arg1)
+	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2)
 	ret0, _ := ret[0].(engine.GitOpsEngine)
 	return ret0
 }
 
 // New indicates an expected call of New.
-func (mr *MockGitopsEngineFactoryMockRecorder) New(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockGitopsEngineFactoryMockRecorder) New(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockGitopsEngineFactory)(nil).New), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockGitopsEngineFactory)(nil).New), arg0, arg1, arg2)
 }
 
 // MockGitopsWorkerFactory is a mock of GitopsWorkerFactory interface.
@@ -75,17 +76,17 @@ func (m *MockGitopsWorkerFactory) EXPECT() *MockGitopsWorkerFactoryMockRecorder
 }
 
 // New mocks base method.
-func (m *MockGitopsWorkerFactory) New(arg0 *agentcfg.ManifestProjectCF) GitopsWorker {
+func (m *MockGitopsWorkerFactory) New(arg0 *agentcfg.ManifestProjectCF, arg1 string, arg2 []string, arg3 *agentcfg.ClusterCF, arg4 string) GitopsWorker {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "New", arg0)
+	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(GitopsWorker)
 	return ret0
 }
 
 // New indicates an expected call of New.
-func (mr *MockGitopsWorkerFactoryMockRecorder) New(arg0 interface{}) *gomock.Call {
+func (mr *MockGitopsWorkerFactoryMockRecorder) New(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockGitopsWorkerFactory)(nil).New), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockGitopsWorkerFactory)(nil).New), arg0, arg1, arg2, arg3, arg4)
 }
 
 // MockGitopsWorker is a mock of GitopsWorker interface.
@@ -111,6 +112,18 @@ func (m *MockGitopsWorker) EXPECT() *MockGitopsWorkerMockRecorder {
 	return m.recorder
 }
 
+// Cleanup mocks base method.
+func (m *MockGitopsWorker) Cleanup(arg0 context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Cleanup", arg0)
+}
+
+// Cleanup indicates an expected call of Cleanup.
+func (mr *MockGitopsWorkerMockRecorder) Cleanup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cleanup", reflect.TypeOf((*MockGitopsWorker)(nil).Cleanup), arg0)
+}
+
 // Run mocks base method.
 func (m *MockGitopsWorker) Run(arg0 context.Context) {
 	m.ctrl.T.Helper()
@@ -2,52 +2,454 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/bmatcuk/doublestar/v2"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/oci"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/metric"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/protodefault"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
 	defaultGitOpsManifestNamespace = metav1.NamespaceDefault
 	defaultGitOpsManifestPathGlob  = "**/*.{yaml,yml,json}"
+	// defaultManifestProjectServiceAccount is the service account that is impersonated for ManifestProject objects.
+	defaultManifestProjectServiceAccount = "gitlab-agent-deployer"
+
+	// workerCleanupTimeout is how long a worker may take to clean up after a removed project.
+	workerCleanupTimeout = 10 * time.Second
 )
 
 type module struct {
 	log           *zap.Logger
 	workerFactory GitopsWorkerFactory
+	// manifestProjectWatcher is nil if ManifestProject custom resources are not watched.
+	manifestProjectWatcher *manifestProjectWatcher
+	// eventBroadcaster is nil if Kubernetes Events are not recorded.
+	eventBroadcaster record.EventBroadcaster
+	eventSink        record.EventSink
+	// metrics is nil if metrics are not recorded.
+	metrics    *gitopsMetrics
+	registerer prometheus.Registerer
 }
 
 func (m *module) Run(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguration) error {
+	if m.metrics != nil {
+		cleanup, err := metric.Register(m.registerer, m.metrics.collectors()...)
+		if err != nil {
+			return err
+		}
+		defer cleanup() // after all workers have stopped
+	}
+	if m.eventBroadcaster != nil {
+		m.eventBroadcaster.StartRecordingToSink(m.eventSink)
+		defer m.eventBroadcaster.Shutdown() // after all workers have stopped
+	}
 	workers := make(map[string]*gitopsWorkerHolder) // project id -> worker holder instance
 	defer stopAllWorkers(workers)
-	for config := range cfg {
-		err := m.configureWorkers(workers, config.Gitops.ManifestProjects)
-		if err != nil {
-			m.log.Error("Failed to apply manifest projects configuration", zap.Error(err))
-			continue
+	// Workers for ManifestProject custom resources, separate from workers for the configuration file.
+	crWorkers := make(map[string]*gitopsWorkerHolder) // namespace/project id -> worker holder instance
+	defer stopAllWorkers(crWorkers)
+	crProjects := make(chan map[string]namespacedManifestProject)
+	var (
+		gitopsCfg *agentcfg.GitopsCF
+		// watchedCRProjects holds the last set of valid projects from ManifestProject objects.
+		watchedCRProjects map[string]namespacedManifestProject
+	)
+	// The watcher is started once the first configuration has been received because it depends on gitops.namespaces.
+	var crWatch *manifestProjectWatch
+	defer func() {
+		if crWatch != nil {
+			crWatch.stop()
+		}
+	}()
+	for {
+		select {
+		case config, ok := <-cfg:
+			if !ok {
+				return nil
+			}
+			gitopsCfg = config.Gitops
+			namespaces := gitopsCfg.Namespaces
+			m.configureWorkers(workers, gitopsCfg)
+			watchCRs := m.manifestProjectWatcher != nil && gitopsCfg.ManifestProjectResources
+			if crWatch != nil && (!watchCRs || !stringSlicesEqual(crWatch.namespaces, namespaces)) {
+				crWatch.stop()
+				crWatch = nil
+			}
+			switch {
+			case watchCRs && crWatch == nil:
+				crWatch = m.startManifestProjectWatch(crProjects, namespaces)
+			case !watchCRs:
+				watchedCRProjects = nil
+			}
+			// The configuration restricts which ManifestProject objects are allowed, re-check them.
+			m.syncWorkers(crWorkers, m.allowedManifestProjects(gitopsCfg, watchedCRProjects))
+		case projects := <-crProjects:
+			watchedCRProjects = projects
+			m.syncWorkers(crWorkers, m.allowedManifestProjects(gitopsCfg, watchedCRProjects))
 		}
 	}
-	return nil
+}
+
+// manifestProjectWatch is a running manifestProjectWatcher.
+type manifestProjectWatch struct {
+	namespaces []string
+	cancel     context.CancelFunc
+	wg         wait.Group
+}
+
+func (m *module) startManifestProjectWatch(projects chan<- map[string]namespacedManifestProject, namespaces []string) *manifestProjectWatch {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &manifestProjectWatch{
+		namespaces: namespaces,
+		cancel:     cancel,
+	}
+	w.wg.StartWithContext(ctx, func(ctx context.Context) {
+		m.manifestProjectWatcher.Run(ctx, namespaces, func(p map[string]namespacedManifestProject) {
+			select {
+			case <-ctx.Done():
+			case projects <- p:
+			}
+		})
+	})
+	return w
+}
+
+func (w *manifestProjectWatch) stop() {
+	w.cancel()
+	w.wg.Wait()
 }
 
 func (m *module) DefaultAndValidateConfiguration(config *agentcfg.AgentConfiguration) error {
+	return DefaultAndValidateConfiguration(config)
+}
+
+// DefaultAndValidateConfiguration applies defaults and validates the module's part of the configuration.
+// It allows to validate configuration without creating the module.
+func DefaultAndValidateConfiguration(config *agentcfg.AgentConfiguration) error {
 	protodefault.NotNil(&config.Gitops)
+	ids := make(map[string]struct{}, len(config.Gitops.ManifestProjects))
 	for _, project := range config.Gitops.ManifestProjects {
-		applyDefaultsToManifestProject(project)
+		if _, ok := ids[project.Id]; ok {
+			return fmt.Errorf("duplicate project id: %s", project.Id)
+		}
+		ids[project.Id] = struct{}{}
+		if err := defaultAndValidateManifestProject(project); err != nil {
+			return fmt.Errorf("project %s: %v", project.Id, err)
+		}
+	}
+	if err := validateNamespaces(config.Gitops); err != nil {
+		return err
+	}
+	if err := defaultAndValidateClusters(config.Gitops); err != nil {
+		return err
+	}
+	if err := defaultAndValidateManifestProjectResources(config.Gitops); err != nil {
+		return err
+	}
+	return validateDependencies(config.Gitops.ManifestProjects)
+}
+
+// defaultAndValidateManifestProjectResources checks the settings for ManifestProject custom resources.
+func defaultAndValidateManifestProjectResources(gitopsCfg *agentcfg.GitopsCF) error {
+	for _, pattern := range gitopsCfg.ManifestProjectResourceProjects {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("manifest_project_resource_projects: invalid pattern %q: %v", pattern, err)
+		}
+	}
+	if !gitopsCfg.ManifestProjectResources {
+		return nil
+	}
+	protodefault.String(&gitopsCfg.ManifestProjectResourceServiceAccount, defaultManifestProjectServiceAccount)
+	if errs := validation.IsDNS1123Subdomain(gitopsCfg.ManifestProjectResourceServiceAccount); len(errs) > 0 {
+		return fmt.Errorf("manifest_project_resource_service_account: invalid name %q: %s", gitopsCfg.ManifestProjectResourceServiceAccount, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateNamespaces checks that, when the agent is restricted to a set of namespaces,
+// projects only use these namespaces.
+func validateNamespaces(gitopsCfg *agentcfg.GitopsCF) error {
+	if len(gitopsCfg.Namespaces) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(gitopsCfg.Namespaces))
+	for _, namespace := range gitopsCfg.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("namespaces: invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+		if _, ok := seen[namespace]; ok {
+			return fmt.Errorf("namespaces: duplicate namespace %s", namespace)
+		}
+		seen[namespace] = struct{}{}
+	}
+	for _, project := range gitopsCfg.ManifestProjects {
+		if project.PreviewEnvironments != nil {
+			return fmt.Errorf("project %s: preview_environments cannot be used when namespaces is set", project.Id)
+		}
+		if _, ok := seen[project.DefaultNamespace]; !ok {
+			return fmt.Errorf("project %s: default_namespace %s is not one of namespaces", project.Id, project.DefaultNamespace)
+		}
+	}
+	return nil
+}
+
+// defaultAndValidateClusters checks that cluster names are unique and that projects only reference existing clusters.
+func defaultAndValidateClusters(gitopsCfg *agentcfg.GitopsCF) error {
+	names := make(map[string]struct{}, len(gitopsCfg.Clusters))
+	for _, cluster := range gitopsCfg.Clusters {
+		if _, ok := names[cluster.Name]; ok {
+			return fmt.Errorf("clusters: duplicate cluster name %s", cluster.Name)
+		}
+		names[cluster.Name] = struct{}{}
+		protodefault.String(&cluster.KubeconfigSecretKey, defaultKubeconfigSecretKey)
+	}
+	for _, project := range gitopsCfg.ManifestProjects {
+		if project.Cluster == "" {
+			continue
+		}
+		if _, ok := names[project.Cluster]; !ok {
+			return fmt.Errorf("project %s: unknown cluster %s", project.Id, project.Cluster)
+		}
+		if project.PreviewEnvironments != nil {
+			return fmt.Errorf("project %s: preview_environments cannot be used with cluster", project.Id)
+		}
+	}
+	return nil
+}
+
+// validateProjectDependencies checks that the project only depends on other projects from byId.
+func validateProjectDependencies(project *agentcfg.ManifestProjectCF, byId map[string]*agentcfg.ManifestProjectCF) error {
+	for _, dep := range project.DependsOn {
+		if dep == project.Id {
+			return fmt.Errorf("project %s: depends_on: project cannot depend on itself", project.Id)
+		}
+		depProject, ok := byId[dep]
+		if !ok {
+			return fmt.Errorf("project %s: depends_on: unknown project %s", project.Id, dep)
+		}
+		if depProject.PreviewEnvironments != nil {
+			return fmt.Errorf("project %s: depends_on: project %s with preview_environments cannot be a dependency", project.Id, dep)
+		}
+	}
+	return nil
+}
+
+// validateDependencies checks that projects only depend on other existing projects and that there are no cycles.
+func validateDependencies(projects []*agentcfg.ManifestProjectCF) error {
+	byId := make(map[string]*agentcfg.ManifestProjectCF, len(projects))
+	for _, project := range projects {
+		byId[project.Id] = project
+	}
+	for _, project := range projects {
+		if err := validateProjectDependencies(project, byId); err != nil {
+			return err
+		}
+	}
+	const (
+		inProgress = iota + 1
+		done
+	)
+	state := make(map[string]int, len(projects))
+	var path []string
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case inProgress:
+			// Cut the path to start from the first occurrence of id
+			for i, p := range path {
+				if p == id {
+					return fmt.Errorf("depends_on: dependency cycle: %s", strings.Join(append(path[i:], id), " -> "))
+				}
+			}
+		}
+		state[id] = inProgress
+		path = append(path, id)
+		for _, dep := range byId[id].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+	for _, project := range projects {
+		if err := visit(project.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func defaultAndValidateManifestProject(project *agentcfg.ManifestProjectCF) error {
+	applyDefaultsToManifestProject(project)
+	if project.SemverTagConstraint != "" {
+		if _, err := semver.NewConstraint(project.SemverTagConstraint); err != nil {
+			return fmt.Errorf("invalid semver_tag_constraint %q: %v", project.SemverTagConstraint, err)
+		}
+	}
+	if err := validatePreviewEnvironments(project); err != nil {
+		return err
+	}
+	if err := validateGitRemote(project); err != nil {
+		return err
+	}
+	if err := validateOciArtifact(project); err != nil {
+		return err
+	}
+	if err := validateImageUpdateAutomation(project); err != nil {
+		return err
+	}
+	if project.Variables != nil {
+		for name := range project.Variables.Values {
+			if err := validateVariableName(name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, p := range project.Paths {
+		if p.Renderer != nil && (len(p.Renderer.Command) == 0 || p.Renderer.Command[0] == "") {
+			return fmt.Errorf("glob %s: renderer command must not be empty", p.Glob)
+		}
+	}
+	return nil
+}
+
+func validatePreviewEnvironments(project *agentcfg.ManifestProjectCF) error {
+	preview := project.PreviewEnvironments
+	if preview == nil {
+		return nil
+	}
+	if project.SemverTagConstraint != "" {
+		return errors.New("preview_environments and semver_tag_constraint cannot be used together")
+	}
+	if _, err := doublestar.Match(preview.BranchGlob, preview.BranchGlob); err != nil {
+		return fmt.Errorf("invalid preview_environments.branch_glob %q: %v", preview.BranchGlob, err)
+	}
+	if strings.Count(preview.NamespaceTemplate, previewNamespaceBranchPlaceholder) != 1 {
+		return fmt.Errorf("preview_environments.namespace_template must contain %s exactly once", previewNamespaceBranchPlaceholder)
+	}
+	if _, err := previewNamespaceName(preview.NamespaceTemplate, "branch"); err != nil {
+		return fmt.Errorf("preview_environments.namespace_template %q: %v", preview.NamespaceTemplate, err)
+	}
+	return nil
+}
+
+func validateGitRemote(project *agentcfg.ManifestProjectCF) error {
+	if project.GitRemote == nil {
+		return nil
+	}
+	if project.SemverTagConstraint != "" {
+		return errors.New("git_remote and semver_tag_constraint cannot be used together")
+	}
+	if project.PreviewEnvironments != nil {
+		return errors.New("git_remote and preview_environments cannot be used together")
+	}
+	endpoint, err := transport.NewEndpoint(project.GitRemote.Url)
+	if err != nil {
+		return fmt.Errorf("invalid git_remote.url: %v", err)
+	}
+	switch endpoint.Protocol {
+	case "https", "ssh":
+	default:
+		return fmt.Errorf("git_remote.url must be an HTTPS or SSH URL, got %s", endpoint.Protocol)
+	}
+	return nil
+}
+
+func validateOciArtifact(project *agentcfg.ManifestProjectCF) error {
+	if project.OciArtifact == nil {
+		return nil
+	}
+	switch {
+	case project.SemverTagConstraint != "":
+		return errors.New("oci_artifact and semver_tag_constraint cannot be used together")
+	case project.PreviewEnvironments != nil:
+		return errors.New("oci_artifact and preview_environments cannot be used together")
+	case project.GitRemote != nil:
+		return errors.New("oci_artifact and git_remote cannot be used together")
+	}
+	if _, err := oci.ParseReference(project.OciArtifact.Ref); err != nil {
+		return fmt.Errorf("invalid oci_artifact.ref: %v", err)
+	}
+	return nil
+}
+
+func validateImageUpdateAutomation(project *agentcfg.ManifestProjectCF) error {
+	if project.ImageUpdateAutomation == nil {
+		return nil
+	}
+	switch {
+	case project.SemverTagConstraint != "":
+		return errors.New("image_update_automation and semver_tag_constraint cannot be used together")
+	case project.PreviewEnvironments != nil:
+		return errors.New("image_update_automation and preview_environments cannot be used together")
+	case project.GitRemote != nil:
+		return errors.New("image_update_automation and git_remote cannot be used together")
+	case project.OciArtifact != nil:
+		return errors.New("image_update_automation and oci_artifact cannot be used together")
+	}
+	u, err := url.Parse(project.ImageUpdateAutomation.GitlabUrl)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("image_update_automation.gitlab_url %q must be an HTTP or HTTPS URL", project.ImageUpdateAutomation.GitlabUrl)
+	}
+	for _, policy := range project.ImageUpdateAutomation.Images {
+		ref, err := oci.ParseReference(policy.Image)
+		if err != nil {
+			return fmt.Errorf("invalid image_update_automation image: %v", err)
+		}
+		if ref.Digest != "" || ref.String() != policy.Image+":"+ref.Tag {
+			return fmt.Errorf("image_update_automation image %q must not have a tag or a digest", policy.Image)
+		}
+		switch {
+		case policy.SemverRange != "" && policy.TagRegex != "":
+			return fmt.Errorf("image_update_automation image %s: semver_range and tag_regex cannot be used together", policy.Image)
+		case policy.SemverRange != "":
+			if _, err := semver.NewConstraint(policy.SemverRange); err != nil {
+				return fmt.Errorf("image_update_automation image %s: invalid semver_range %q: %v", policy.Image, policy.SemverRange, err)
+			}
+		case policy.TagRegex != "":
+			if _, err := regexp.Compile(policy.TagRegex); err != nil {
+				return fmt.Errorf("image_update_automation image %s: invalid tag_regex %q: %v", policy.Image, policy.TagRegex, err)
+			}
+		default:
+			return fmt.Errorf("image_update_automation image %s: either semver_range or tag_regex must be set", policy.Image)
+		}
 	}
 	return nil
 }
 
 func applyDefaultsToManifestProject(project *agentcfg.ManifestProjectCF) {
 	protodefault.String(&project.DefaultNamespace, defaultGitOpsManifestNamespace)
+	if project.PreviewEnvironments != nil {
+		protodefault.String(&project.PreviewEnvironments.NamespaceTemplate, defaultPreviewNamespaceTemplate)
+	}
+	if len(project.DependsOn) > 0 {
+		protodefault.Duration(&project.DependsOnTimeout, defaultDependsOnTimeout)
+	}
 	if len(project.Paths) == 0 {
 		project.Paths = []*agentcfg.PathCF{
 			{
@@ -55,16 +457,23 @@ func applyDefaultsToManifestProject(project *agentcfg.ManifestProjectCF) {
 			},
 		}
 	}
+	for _, p := range project.Paths {
+		if p.Renderer != nil {
+			protodefault.Duration(&p.Renderer.Timeout, defaultRendererTimeout)
+			protodefault.Uint32(&p.Renderer.MaxMemoryMib, defaultRendererMaxMemoryMiB)
+			protodefault.Duration(&p.Renderer.MaxCpuTime, defaultRendererMaxCPUTime)
+		}
+	}
 }
 
 func (m *module) Name() string {
 	return gitops.ModuleName
 }
 
-func (m *module) startNewWorker(workers map[string]*gitopsWorkerHolder, project *agentcfg.ManifestProjectCF) {
-	l := m.log.With(logz.ProjectId(project.Id))
+func (m *module) startNewWorker(workers map[string]*gitopsWorkerHolder, key string, project namespacedManifestProject) {
+	l := m.workerLogger(project)
 	l.Info("Starting synchronization worker")
-	worker := m.workerFactory.New(project)
+	worker := m.workerFactory.New(project.project, project.namespace, project.allowedNamespaces, project.cluster, project.serviceAccount)
 	ctx, cancel := context.WithCancel(context.Background())
 	workerHolder := &gitopsWorkerHolder{
 		worker:  worker,
@@ -72,62 +481,98 @@ func (m *module) startNewWorker(workers map[string]*gitopsWorkerHolder, project
 		stop:    cancel,
 	}
 	workerHolder.wg.StartWithContext(ctx, worker.Run)
-	workers[project.Id] = workerHolder
+	workers[key] = workerHolder
+}
+
+// configureWorkers makes the running workers match the projects of the configuration.
+// The configuration must have been validated by DefaultAndValidateConfiguration so it cannot be applied partially.
+func (m *module) configureWorkers(workers map[string]*gitopsWorkerHolder, gitopsCfg *agentcfg.GitopsCF) {
+	clusters := make(map[string]*agentcfg.ClusterCF, len(gitopsCfg.Clusters))
+	for _, cluster := range gitopsCfg.Clusters {
+		clusters[cluster.Name] = cluster
+	}
+	desired := make(map[string]namespacedManifestProject, len(gitopsCfg.ManifestProjects))
+	for _, project := range gitopsCfg.ManifestProjects {
+		desired[project.Id] = namespacedManifestProject{
+			project:           project,
+			allowedNamespaces: gitopsCfg.Namespaces,
+			cluster:           clusters[project.Cluster], // nil if the project is synchronized into this cluster
+		}
+	}
+	m.syncWorkers(workers, desired)
 }
 
-func (m *module) configureWorkers(workers map[string]*gitopsWorkerHolder, projects []*agentcfg.ManifestProjectCF) error {
-	newSetOfProjects := sets.NewString()
+// syncWorkers starts, restarts and stops workers so that there is exactly one worker per desired project.
+func (m *module) syncWorkers(workers map[string]*gitopsWorkerHolder, desired map[string]namespacedManifestProject) {
 	var (
-		projectsToStartWorkersFor []*agentcfg.ManifestProjectCF
+		projectsToStartWorkersFor = make(map[string]namespacedManifestProject)
 		workersToStop             []*gitopsWorkerHolder
 	)
 
 	// Collect projects without workers or with updated configuration.
-	for _, project := range projects {
-		if newSetOfProjects.Has(project.Id) {
-			return fmt.Errorf("duplicate project id: %s", project.Id)
-		}
-		newSetOfProjects.Insert(project.Id)
-		workerHolder := workers[project.Id]
+	for key, project := range desired {
+		workerHolder := workers[key]
 		if workerHolder == nil { // New project added
-			projectsToStartWorkersFor = append(projectsToStartWorkersFor, project)
+			projectsToStartWorkersFor[key] = project
 		} else { // We have a worker for this project already
-			if proto.Equal(project, workerHolder.project) {
+			if project.equal(workerHolder.project) {
 				// Worker's configuration hasn't changed, nothing to do here
 				continue
 			}
-			m.log.Info("Configuration has been updated, restarting synchronization worker", logz.ProjectId(project.Id))
+			m.workerLogger(project).Info("Configuration has been updated, restarting synchronization worker")
 			workersToStop = append(workersToStop, workerHolder)
-			projectsToStartWorkersFor = append(projectsToStartWorkersFor, project)
+			projectsToStartWorkersFor[key] = project
 		}
 	}
 
 	// Stop workers for projects which have been removed from the list.
-	for projectId, workerHolder := range workers {
-		if newSetOfProjects.Has(projectId) {
+	var removedWorkers []*gitopsWorkerHolder
+	for key, workerHolder := range workers {
+		if _, ok := desired[key]; ok {
 			continue
 		}
 		workersToStop = append(workersToStop, workerHolder)
+		removedWorkers = append(removedWorkers, workerHolder)
 	}
 
 	// Tell workers that should be stopped to stop.
-	for _, workerHolder := range workersToStop {
-		m.log.Info("Stopping synchronization worker", logz.ProjectId(workerHolder.project.Id))
-		workerHolder.stop()
-		delete(workers, workerHolder.project.Id)
+	for key, workerHolder := range workers {
+		for _, toStop := range workersToStop {
+			if workerHolder == toStop {
+				m.workerLogger(workerHolder.project).Info("Stopping synchronization worker")
+				workerHolder.stop()
+				delete(workers, key)
+				break
+			}
+		}
 	}
 
 	// Wait for stopped workers to finish.
 	for _, workerHolder := range workersToStop {
-		m.log.Info("Waiting for synchronization worker to stop", logz.ProjectId(workerHolder.project.Id))
+		m.workerLogger(workerHolder.project).Info("Waiting for synchronization worker to stop")
 		workerHolder.wg.Wait()
 	}
 
+	// Clean up after projects which have been removed. Restarted workers keep what they have created.
+	for _, workerHolder := range removedWorkers {
+		m.workerLogger(workerHolder.project).Info("Project has been removed, cleaning up")
+		ctx, cancel := context.WithTimeout(context.Background(), workerCleanupTimeout)
+		workerHolder.worker.Cleanup(ctx)
+		cancel()
+	}
+
 	// Start new workers for new projects or because of updated configuration.
-	for _, project := range projectsToStartWorkersFor {
-		m.startNewWorker(workers, project)
+	for key, project := range projectsToStartWorkersFor {
+		m.startNewWorker(workers, key, project)
 	}
-	return nil
+}
+
+func (m *module) workerLogger(project namespacedManifestProject) *zap.Logger {
+	l := m.log.With(logz.ProjectId(project.project.Id))
+	if project.namespace != "" {
+		l = l.With(logz.Namespace(project.namespace))
+	}
+	return l
 }
 
 func stopAllWorkers(workers map[string]*gitopsWorkerHolder) {
@@ -141,9 +586,72 @@ func stopAllWorkers(workers map[string]*gitopsWorkerHolder) {
 	}
 }
 
+// namespacedManifestProject is a manifest project, that is restricted to a namespace.
+// namespace is empty for projects from the configuration file, that are not restricted.
+type namespacedManifestProject struct {
+	project   *agentcfg.ManifestProjectCF
+	namespace string
+	// allowedNamespaces restricts the worker to these namespaces. Empty means the whole cluster.
+	allowedNamespaces []string
+	// cluster is the cluster to synchronize objects into. nil means the cluster agentk runs in.
+	cluster *agentcfg.ClusterCF
+	// serviceAccount is the service account in namespace to synchronize objects as.
+	// Empty means agentk's own identity is used.
+	serviceAccount string
+}
+
+func (p namespacedManifestProject) equal(other namespacedManifestProject) bool {
+	return proto.Equal(p.project, other.project) &&
+		p.namespace == other.namespace &&
+		stringSlicesEqual(p.allowedNamespaces, other.allowedNamespaces) &&
+		proto.Equal(p.cluster, other.cluster) &&
+		p.serviceAccount == other.serviceAccount
+}
+
+// allowedManifestProjects returns the projects from ManifestProject objects that the configuration allows.
+// Projects that are not allowed are logged and skipped.
+func (m *module) allowedManifestProjects(gitopsCfg *agentcfg.GitopsCF, projects map[string]namespacedManifestProject) map[string]namespacedManifestProject {
+	if len(projects) == 0 {
+		return nil
+	}
+	namespaces := sets.NewString(gitopsCfg.Namespaces...)
+	byId := make(map[string]*agentcfg.ManifestProjectCF, len(gitopsCfg.ManifestProjects))
+	for _, project := range gitopsCfg.ManifestProjects {
+		byId[project.Id] = project
+	}
+	result := make(map[string]namespacedManifestProject, len(projects))
+	for key, project := range projects {
+		if namespaces.Len() > 0 && !namespaces.Has(project.namespace) {
+			continue // the object has been received before the watched namespaces changed
+		}
+		l := m.workerLogger(project)
+		if !projectMatches(gitopsCfg.ManifestProjectResourceProjects, project.project.Id) {
+			l.Error("ManifestProject is for a project that is not in manifest_project_resource_projects, ignoring")
+			continue
+		}
+		if err := validateProjectDependencies(project.project, byId); err != nil {
+			l.Error("Invalid ManifestProject, ignoring", zap.Error(err))
+			continue
+		}
+		project.serviceAccount = gitopsCfg.ManifestProjectResourceServiceAccount
+		result[key] = project
+	}
+	return result
+}
+
+// projectMatches returns true if the project id matches one of the patterns.
+func projectMatches(patterns []string, projectId string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, projectId); ok { // patterns have been validated
+			return true
+		}
+	}
+	return false
+}
+
 type gitopsWorkerHolder struct {
 	worker  GitopsWorker
-	project *agentcfg.ManifestProjectCF
+	project namespacedManifestProject
 	wg      wait.Group
 	stop    context.CancelFunc
 }
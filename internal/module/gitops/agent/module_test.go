@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +15,12 @@ import (
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var (
@@ -35,7 +41,7 @@ func TestStartsWorkersAccordingToConfiguration(t *testing.T) {
 			worker := NewMockGitopsWorker(ctrl)
 			for i := 0; i < expectedNumberOfWorkers; i++ {
 				factory.EXPECT().
-					New(matcher.ProtoEq(t, projects[i])).
+					New(matcher.ProtoEq(t, projects[i]), "", nil, nil, "").
 					Return(worker)
 			}
 			worker.EXPECT().
@@ -92,8 +98,11 @@ func TestUpdatesWorkersAccordingToConfiguration(t *testing.T) {
 					<-ctx.Done()
 				}).
 				Times(numEngines)
+			worker.EXPECT().
+				Cleanup(gomock.Any()).
+				AnyTimes()
 			factory.EXPECT().
-				New(gomock.Any()).
+				New(gomock.Any(), "", nil, nil, "").
 				Return(worker).
 				Times(numEngines)
 			cfg := make(chan *agentcfg.AgentConfiguration)
@@ -111,6 +120,179 @@ func TestUpdatesWorkersAccordingToConfiguration(t *testing.T) {
 	}
 }
 
+func TestCleansUpOnlyRemovedProjects(t *testing.T) {
+	m, ctrl, factory := setupModule(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runUntilDone := func(ctx context.Context) {
+		<-ctx.Done()
+	}
+	worker1 := NewMockGitopsWorker(ctrl)
+	worker1.EXPECT().
+		Run(gomock.Any()).
+		Do(runUntilDone)
+	worker1Restarted := NewMockGitopsWorker(ctrl)
+	worker1Restarted.EXPECT().
+		Run(gomock.Any()).
+		Do(runUntilDone)
+	worker2 := NewMockGitopsWorker(ctrl)
+	gomock.InOrder(
+		worker2.EXPECT().
+			Run(gomock.Any()).
+			Do(runUntilDone),
+		worker2.EXPECT().
+			Cleanup(gomock.Any()),
+	)
+	gomock.InOrder(
+		factory.EXPECT().
+			New(matcher.ProtoEq(t, &agentcfg.ManifestProjectCF{Id: "project1"}), "", nil, nil, "").
+			Return(worker1),
+		factory.EXPECT().
+			New(matcher.ProtoEq(t, &agentcfg.ManifestProjectCF{Id: "project1", DefaultNamespace: "ns"}), "", nil, nil, "").
+			Return(worker1Restarted),
+	)
+	factory.EXPECT().
+		New(matcher.ProtoEq(t, &agentcfg.ManifestProjectCF{Id: "project2"}), "", nil, nil, "").
+		Return(worker2)
+	cfg := make(chan *agentcfg.AgentConfiguration)
+	var wg wait.Group
+	wg.Start(func() {
+		err := m.Run(ctx, cfg)
+		assert.NoError(t, err)
+	})
+	cfg <- &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{Id: "project1"},
+				{Id: "project2"},
+			},
+		},
+	}
+	// project1 is restarted, project2 is removed
+	cfg <- &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{Id: "project1", DefaultNamespace: "ns"},
+			},
+		},
+	}
+	close(cfg)
+	wg.Wait()
+}
+
+func TestStartsWorkersForManifestProjects(t *testing.T) {
+	m, ctrl, factory := setupModule(t)
+	m.manifestProjectWatcher = &manifestProjectWatcher{
+		log: zaptest.NewLogger(t),
+		dynamicClient: fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				manifestProjectGVR: "ManifestProjectList",
+			},
+			manifestProjectObject("ns1", "app", map[string]interface{}{
+				"id": "group/project",
+			}),
+		),
+		discovery:      manifestProjectDiscovery(),
+		apiCheckPeriod: time.Minute,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker := NewMockGitopsWorker(ctrl)
+	gomock.InOrder(
+		factory.EXPECT().
+			New(gomock.Any(), "", nil, nil, "").
+			Return(worker),
+		worker.EXPECT().
+			Run(gomock.Any()),
+	)
+	crWorker := NewMockGitopsWorker(ctrl)
+	gomock.InOrder(
+		factory.EXPECT().
+			New(gomock.Any(), "ns1", []string{"ns1"}, nil, defaultManifestProjectServiceAccount).
+			Do(func(project *agentcfg.ManifestProjectCF, namespace string, allowedNamespaces []string, cluster *agentcfg.ClusterCF, serviceAccount string) {
+				assert.Equal(t, "group/project", project.Id)
+				assert.Equal(t, "ns1", project.DefaultNamespace)
+			}).
+			Return(crWorker),
+		crWorker.EXPECT().
+			Run(gomock.Any()).
+			Do(func(ctx context.Context) {
+				cancel()
+			}),
+	)
+	cfg := make(chan *agentcfg.AgentConfiguration, 1)
+	config := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id: "group/project",
+				},
+			},
+			ManifestProjectResources:        true,
+			ManifestProjectResourceProjects: []string{"group/*"},
+		},
+	}
+	require.NoError(t, m.DefaultAndValidateConfiguration(config))
+	cfg <- config
+	var wg wait.Group
+	wg.Start(func() {
+		<-ctx.Done()
+		close(cfg)
+	})
+	err := m.Run(ctx, cfg)
+	require.NoError(t, err)
+	wg.Wait()
+}
+
+func TestDoesNotWatchManifestProjectsWhenDisabled(t *testing.T) {
+	m, ctrl, factory := setupModule(t)
+	d := &fakediscovery.FakeDiscovery{
+		Fake: &k8stesting.Fake{},
+	}
+	d.AddReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		t.Error("unexpected discovery request")
+		return false, nil, nil
+	})
+	m.manifestProjectWatcher = &manifestProjectWatcher{
+		log:            zaptest.NewLogger(t),
+		discovery:      d,
+		apiCheckPeriod: time.Minute,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	worker := NewMockGitopsWorker(ctrl)
+	gomock.InOrder(
+		factory.EXPECT().
+			New(gomock.Any(), "", nil, nil, "").
+			Return(worker),
+		worker.EXPECT().
+			Run(gomock.Any()).
+			Do(func(ctx context.Context) {
+				cancel()
+			}),
+	)
+	cfg := make(chan *agentcfg.AgentConfiguration, 1)
+	config := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id: "group/project",
+				},
+			},
+		},
+	}
+	require.NoError(t, m.DefaultAndValidateConfiguration(config))
+	cfg <- config
+	var wg wait.Group
+	wg.Start(func() {
+		<-ctx.Done()
+		close(cfg)
+	})
+	err := m.Run(ctx, cfg)
+	require.NoError(t, err)
+	wg.Wait()
+}
+
 func setupModule(t *testing.T) (*module, *gomock.Controller, *MockGitopsWorkerFactory) {
 	ctrl := gomock.NewController(t)
 	workerFactory := NewMockGitopsWorkerFactory(ctrl)
@@ -141,6 +323,516 @@ func numUniqueProjects(cfgs []*agentcfg.AgentConfiguration) int {
 	return num
 }
 
+func TestDefaultAndValidateConfigurationErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		project     *agentcfg.ManifestProjectCF
+		expectedErr string
+	}{
+		{
+			name: "invalid semver constraint",
+			project: &agentcfg.ManifestProjectCF{
+				Id:                  "bla",
+				SemverTagConstraint: "bla bla",
+			},
+			expectedErr: `project bla: invalid semver_tag_constraint "bla bla": improper constraint: bla bla`,
+		},
+		{
+			name: "preview environments with semver constraint",
+			project: &agentcfg.ManifestProjectCF{
+				Id:                  "bla",
+				SemverTagConstraint: "~1",
+				PreviewEnvironments: &agentcfg.PreviewEnvironmentsCF{
+					BranchGlob: "*",
+				},
+			},
+			expectedErr: "project bla: preview_environments and semver_tag_constraint cannot be used together",
+		},
+		{
+			name: "preview environments without placeholder",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				PreviewEnvironments: &agentcfg.PreviewEnvironmentsCF{
+					BranchGlob:        "*",
+					NamespaceTemplate: "preview",
+				},
+			},
+			expectedErr: "project bla: preview_environments.namespace_template must contain {branch} exactly once",
+		},
+		{
+			name: "preview environments invalid namespace",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				PreviewEnvironments: &agentcfg.PreviewEnvironmentsCF{
+					BranchGlob:        "*",
+					NamespaceTemplate: "Preview_{branch}",
+				},
+			},
+			expectedErr: `project bla: preview_environments.namespace_template "Preview_{branch}": namespace name "Preview_branch-f38c764c" for branch "branch" is invalid: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+		{
+			name: "git remote with semver constraint",
+			project: &agentcfg.ManifestProjectCF{
+				Id:                  "bla",
+				SemverTagConstraint: "~1",
+				GitRemote: &agentcfg.GitRemoteCF{
+					Url: "https://github.com/org/repo.git",
+				},
+			},
+			expectedErr: "project bla: git_remote and semver_tag_constraint cannot be used together",
+		},
+		{
+			name: "git remote with file URL",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				GitRemote: &agentcfg.GitRemoteCF{
+					Url: "file:///srv/repo.git",
+				},
+			},
+			expectedErr: "project bla: git_remote.url must be an HTTPS or SSH URL, got file",
+		},
+		{
+			name: "git remote with git URL",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				GitRemote: &agentcfg.GitRemoteCF{
+					Url: "git://github.com/org/repo.git",
+				},
+			},
+			expectedErr: "project bla: git_remote.url must be an HTTPS or SSH URL, got git",
+		},
+		{
+			name: "oci artifact with git remote",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				GitRemote: &agentcfg.GitRemoteCF{
+					Url: "https://github.com/org/repo.git",
+				},
+				OciArtifact: &agentcfg.OciArtifactCF{
+					Ref: "registry.gitlab.com/group/project/manifests:main",
+				},
+			},
+			expectedErr: "project bla: oci_artifact and git_remote cannot be used together",
+		},
+		{
+			name: "oci artifact invalid ref",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				OciArtifact: &agentcfg.OciArtifactCF{
+					Ref: "group/project/manifests:main",
+				},
+			},
+			expectedErr: `project bla: invalid oci_artifact.ref: invalid reference "group/project/manifests:main": registry is required`,
+		},
+		{
+			name: "reserved variable name",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				Variables: &agentcfg.VariablesCF{
+					Values: map[string]string{
+						"AGENT_NAME": "x",
+					},
+				},
+			},
+			expectedErr: `project bla: variable name "AGENT_NAME" uses reserved prefix AGENT_`,
+		},
+		{
+			name: "image update automation invalid GitLab URL",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				ImageUpdateAutomation: &agentcfg.ImageUpdateAutomationCF{
+					GitlabUrl:   "gitlab.example.com",
+					TokenSecret: "gitlab-token",
+					Images: []*agentcfg.ImagePolicyCF{
+						{
+							Image:       "registry.gitlab.com/group/app",
+							SemverRange: "~1",
+						},
+					},
+				},
+			},
+			expectedErr: `project bla: image_update_automation.gitlab_url "gitlab.example.com" must be an HTTP or HTTPS URL`,
+		},
+		{
+			name: "image update automation with tag",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				ImageUpdateAutomation: &agentcfg.ImageUpdateAutomationCF{
+					GitlabUrl:   "https://gitlab.example.com",
+					TokenSecret: "gitlab-token",
+					Images: []*agentcfg.ImagePolicyCF{
+						{
+							Image:       "registry.gitlab.com/group/app:v1",
+							SemverRange: "~1",
+						},
+					},
+				},
+			},
+			expectedErr: `project bla: image_update_automation image "registry.gitlab.com/group/app:v1" must not have a tag or a digest`,
+		},
+		{
+			name: "image update automation without policy",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				ImageUpdateAutomation: &agentcfg.ImageUpdateAutomationCF{
+					GitlabUrl:   "https://gitlab.example.com",
+					TokenSecret: "gitlab-token",
+					Images: []*agentcfg.ImagePolicyCF{
+						{
+							Image: "registry.gitlab.com/group/app",
+						},
+					},
+				},
+			},
+			expectedErr: "project bla: image_update_automation image registry.gitlab.com/group/app: either semver_range or tag_regex must be set",
+		},
+		{
+			name: "image update automation invalid regex",
+			project: &agentcfg.ManifestProjectCF{
+				Id: "bla",
+				ImageUpdateAutomation: &agentcfg.ImageUpdateAutomationCF{
+					GitlabUrl:   "https://gitlab.example.com",
+					TokenSecret: "gitlab-token",
+					Images: []*agentcfg.ImagePolicyCF{
+						{
+							Image:    "registry.gitlab.com/group/app",
+							TagRegex: "(",
+						},
+					},
+				},
+			},
+			expectedErr: "project bla: image_update_automation image registry.gitlab.com/group/app: invalid tag_regex \"(\": error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m, _, _ := setupModule(t)
+			config := &agentcfg.AgentConfiguration{
+				Gitops: &agentcfg.GitopsCF{
+					ManifestProjects: []*agentcfg.ManifestProjectCF{tc.project}, // nolint: scopelint
+				},
+			}
+			err := m.DefaultAndValidateConfiguration(config)
+			assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+		})
+	}
+}
+
+func TestDefaultAndValidateConfigurationDuplicateProjects(t *testing.T) {
+	m, _, _ := setupModule(t)
+	config := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id: "bla",
+				},
+				{
+					Id: "bla",
+				},
+			},
+		},
+	}
+	err := m.DefaultAndValidateConfiguration(config)
+	assert.EqualError(t, err, "duplicate project id: bla")
+}
+
+func TestDefaultAndValidateConfigurationDependencies(t *testing.T) {
+	tests := []struct {
+		name        string
+		projects    []*agentcfg.ManifestProjectCF
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			projects: []*agentcfg.ManifestProjectCF{
+				{
+					Id:        "app",
+					DependsOn: []string{"crds", "operators"},
+				},
+				{
+					Id:        "operators",
+					DependsOn: []string{"crds"},
+				},
+				{
+					Id: "crds",
+				},
+			},
+		},
+		{
+			name: "unknown project",
+			projects: []*agentcfg.ManifestProjectCF{
+				{
+					Id:        "app",
+					DependsOn: []string{"crds"},
+				},
+			},
+			expectedErr: "project app: depends_on: unknown project crds",
+		},
+		{
+			name: "self",
+			projects: []*agentcfg.ManifestProjectCF{
+				{
+					Id:        "app",
+					DependsOn: []string{"app"},
+				},
+			},
+			expectedErr: "project app: depends_on: project cannot depend on itself",
+		},
+		{
+			name: "preview environments",
+			projects: []*agentcfg.ManifestProjectCF{
+				{
+					Id:        "app",
+					DependsOn: []string{"preview"},
+				},
+				{
+					Id: "preview",
+					PreviewEnvironments: &agentcfg.PreviewEnvironmentsCF{
+						BranchGlob: "*",
+					},
+				},
+			},
+			expectedErr: "project app: depends_on: project preview with preview_environments cannot be a dependency",
+		},
+		{
+			name: "cycle",
+			projects: []*agentcfg.ManifestProjectCF{
+				{
+					Id:        "app",
+					DependsOn: []string{"a"},
+				},
+				{
+					Id:        "a",
+					DependsOn: []string{"b"},
+				},
+				{
+					Id:        "b",
+					DependsOn: []string{"c"},
+				},
+				{
+					Id:        "c",
+					DependsOn: []string{"a"},
+				},
+			},
+			expectedErr: "depends_on: dependency cycle: a -> b -> c -> a",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m, _, _ := setupModule(t)
+			config := &agentcfg.AgentConfiguration{
+				Gitops: &agentcfg.GitopsCF{
+					ManifestProjects: tc.projects, // nolint: scopelint
+				},
+			}
+			err := m.DefaultAndValidateConfiguration(config)
+			if tc.expectedErr == "" { // nolint: scopelint
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+			}
+		})
+	}
+}
+
+func TestDefaultAndValidateConfigurationNamespaces(t *testing.T) {
+	tests := []struct {
+		name        string
+		namespaces  []string
+		project     *agentcfg.ManifestProjectCF
+		expectedErr string
+	}{
+		{
+			name:       "valid",
+			namespaces: []string{"ns1", "ns2"},
+			project: &agentcfg.ManifestProjectCF{
+				Id:               "app",
+				DefaultNamespace: "ns2",
+			},
+		},
+		{
+			name:       "invalid namespace",
+			namespaces: []string{"Ns1"},
+			project: &agentcfg.ManifestProjectCF{
+				Id: "app",
+			},
+			expectedErr: `namespaces: invalid namespace "Ns1": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+		{
+			name:       "duplicate namespace",
+			namespaces: []string{"ns1", "ns1"},
+			project: &agentcfg.ManifestProjectCF{
+				Id:               "app",
+				DefaultNamespace: "ns1",
+			},
+			expectedErr: "namespaces: duplicate namespace ns1",
+		},
+		{
+			name:       "default namespace not allowed",
+			namespaces: []string{"ns1"},
+			project: &agentcfg.ManifestProjectCF{
+				Id: "app",
+			},
+			expectedErr: "project app: default_namespace default is not one of namespaces",
+		},
+		{
+			name:       "preview environments",
+			namespaces: []string{"ns1"},
+			project: &agentcfg.ManifestProjectCF{
+				Id:               "app",
+				DefaultNamespace: "ns1",
+				PreviewEnvironments: &agentcfg.PreviewEnvironmentsCF{
+					BranchGlob: "*",
+				},
+			},
+			expectedErr: "project app: preview_environments cannot be used when namespaces is set",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m, _, _ := setupModule(t)
+			config := &agentcfg.AgentConfiguration{
+				Gitops: &agentcfg.GitopsCF{
+					Namespaces:       tc.namespaces,                             // nolint: scopelint
+					ManifestProjects: []*agentcfg.ManifestProjectCF{tc.project}, // nolint: scopelint
+				},
+			}
+			err := m.DefaultAndValidateConfiguration(config)
+			if tc.expectedErr == "" { // nolint: scopelint
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+			}
+		})
+	}
+}
+
+func TestDefaultAndValidateConfigurationClusters(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusters    []*agentcfg.ClusterCF
+		project     *agentcfg.ManifestProjectCF
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			clusters: []*agentcfg.ClusterCF{
+				{
+					Name:             "edge1",
+					KubeconfigSecret: "edge1-kubeconfig",
+				},
+			},
+			project: &agentcfg.ManifestProjectCF{
+				Id:      "app",
+				Cluster: "edge1",
+			},
+		},
+		{
+			name: "duplicate cluster",
+			clusters: []*agentcfg.ClusterCF{
+				{
+					Name:             "edge1",
+					KubeconfigSecret: "edge1-kubeconfig",
+				},
+				{
+					Name:             "edge1",
+					KubeconfigSecret: "edge1-kubeconfig-2",
+				},
+			},
+			project: &agentcfg.ManifestProjectCF{
+				Id: "app",
+			},
+			expectedErr: "clusters: duplicate cluster name edge1",
+		},
+		{
+			name: "unknown cluster",
+			project: &agentcfg.ManifestProjectCF{
+				Id:      "app",
+				Cluster: "edge1",
+			},
+			expectedErr: "project app: unknown cluster edge1",
+		},
+		{
+			name: "preview environments",
+			clusters: []*agentcfg.ClusterCF{
+				{
+					Name:             "edge1",
+					KubeconfigSecret: "edge1-kubeconfig",
+				},
+			},
+			project: &agentcfg.ManifestProjectCF{
+				Id:      "app",
+				Cluster: "edge1",
+				PreviewEnvironments: &agentcfg.PreviewEnvironmentsCF{
+					BranchGlob: "*",
+				},
+			},
+			expectedErr: "project app: preview_environments cannot be used with cluster",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m, _, _ := setupModule(t)
+			config := &agentcfg.AgentConfiguration{
+				Gitops: &agentcfg.GitopsCF{
+					Clusters:         tc.clusters,                               // nolint: scopelint
+					ManifestProjects: []*agentcfg.ManifestProjectCF{tc.project}, // nolint: scopelint
+				},
+			}
+			err := m.DefaultAndValidateConfiguration(config)
+			if tc.expectedErr == "" { // nolint: scopelint
+				require.NoError(t, err)
+				assert.Equal(t, defaultKubeconfigSecretKey, config.Gitops.Clusters[0].KubeconfigSecretKey)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+			}
+		})
+	}
+}
+
+func TestStartsWorkersForClusters(t *testing.T) {
+	m, ctrl, factory := setupModule(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster := &agentcfg.ClusterCF{
+		Name:             "edge1",
+		KubeconfigSecret: "edge1-kubeconfig",
+	}
+	worker := NewMockGitopsWorker(ctrl)
+	gomock.InOrder(
+		factory.EXPECT().
+			New(gomock.Any(), "", nil, matcher.ProtoEq(t, cluster), "").
+			Return(worker),
+		worker.EXPECT().
+			Run(gomock.Any()).
+			Do(func(ctx context.Context) {
+				cancel()
+			}),
+	)
+	cfg := make(chan *agentcfg.AgentConfiguration, 1)
+	config := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			Clusters: []*agentcfg.ClusterCF{cluster},
+			ManifestProjects: []*agentcfg.ManifestProjectCF{
+				{
+					Id:      "group/project",
+					Cluster: "edge1",
+				},
+			},
+		},
+	}
+	require.NoError(t, m.DefaultAndValidateConfiguration(config))
+	cfg <- config
+	var wg wait.Group
+	wg.Start(func() {
+		<-ctx.Done()
+		close(cfg)
+	})
+	err := m.Run(ctx, cfg)
+	require.NoError(t, err)
+	wg.Wait()
+}
+
 func testConfigurations() []*agentcfg.AgentConfiguration {
 	const (
 		project1 = "bla1/project1"
@@ -194,3 +886,68 @@ func reverse(cfgs []*agentcfg.AgentConfiguration) {
 		cfgs[i], cfgs[j] = cfgs[j], cfgs[i]
 	}
 }
+
+func TestDefaultAndValidateConfigurationManifestProjectResources(t *testing.T) {
+	config := &agentcfg.AgentConfiguration{
+		Gitops: &agentcfg.GitopsCF{
+			ManifestProjectResources:        true,
+			ManifestProjectResourceProjects: []string{"group/app", "team1/*"},
+		},
+	}
+	require.NoError(t, DefaultAndValidateConfiguration(config))
+	assert.Equal(t, defaultManifestProjectServiceAccount, config.Gitops.ManifestProjectResourceServiceAccount)
+
+	config.Gitops.ManifestProjectResourceProjects = []string{"team1/["}
+	assert.EqualError(t, DefaultAndValidateConfiguration(config), `manifest_project_resource_projects: invalid pattern "team1/[": syntax error in pattern`)
+
+	config.Gitops.ManifestProjectResourceProjects = nil
+	config.Gitops.ManifestProjectResourceServiceAccount = "Deployer"
+	assert.Error(t, DefaultAndValidateConfiguration(config))
+}
+
+func TestAllowedManifestProjects(t *testing.T) {
+	m, _, _ := setupModule(t)
+	gitopsCfg := &agentcfg.GitopsCF{
+		ManifestProjects: []*agentcfg.ManifestProjectCF{
+			{
+				Id: "platform/crds",
+			},
+		},
+		ManifestProjectResourceProjects:       []string{"team1/*"},
+		ManifestProjectResourceServiceAccount: "deployer",
+	}
+	crProject := func(namespace, id string, dependsOn ...string) namespacedManifestProject {
+		return namespacedManifestProject{
+			project: &agentcfg.ManifestProjectCF{
+				Id:        id,
+				DependsOn: dependsOn,
+			},
+			namespace:         namespace,
+			allowedNamespaces: []string{namespace},
+		}
+	}
+	projects := map[string]namespacedManifestProject{
+		"ns1/team1/app":     crProject("ns1", "team1/app", "platform/crds"),
+		"ns1/team2/app":     crProject("ns1", "team2/app"),
+		"ns1/team1/sub/app": crProject("ns1", "team1/sub/app"),
+		"ns2/team1/app":     crProject("ns2", "team1/app", "team1/other"),
+		"ns3/team1/app":     crProject("ns3", "team1/app", "team1/app"),
+		"ns4/team1/worker":  crProject("ns4", "team1/worker"),
+		"ns4/platform/crds": crProject("ns4", "platform/crds"),
+	}
+	allowed := m.allowedManifestProjects(gitopsCfg, projects)
+	expected := map[string]namespacedManifestProject{
+		"ns1/team1/app":    crProject("ns1", "team1/app", "platform/crds"),
+		"ns4/team1/worker": crProject("ns4", "team1/worker"),
+	}
+	require.Len(t, allowed, len(expected))
+	for key, project := range expected {
+		project.serviceAccount = "deployer"
+		assert.True(t, project.equal(allowed[key]), key)
+	}
+
+	gitopsCfg.Namespaces = []string{"ns1"}
+	allowed = m.allowedManifestProjects(gitopsCfg, projects)
+	assert.Len(t, allowed, 1)
+	assert.Contains(t, allowed, "ns1/team1/app")
+}
@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/oci"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultOciArtifactPollPeriod = 20 * time.Second
+)
+
+// ociWatcher fetches manifests from an OCI artifact in a container registry.
+// Layers that are tar archives (optionally gzip-compressed) are unpacked.
+// Other layers are treated as single files if they have a title annotation.
+// It implements rpc.ObjectsToSynchronizeWatcherInterface.
+type ociWatcher struct {
+	log        *zap.Logger
+	artifact   *agentcfg.OciArtifactCF
+	httpClient *http.Client
+	kubeClient kubernetes.Interface
+	// secretNamespace is the namespace to read the credentials Secret from.
+	secretNamespace string
+	pollPeriod      time.Duration
+}
+
+func (w *ociWatcher) Watch(ctx context.Context, req *rpc.ObjectsToSynchronizeRequest, callback rpc.ObjectsToSynchronizeCallback) error {
+	ref, err := oci.ParseReference(w.artifact.Ref)
+	if err != nil {
+		return err // should never happen as the reference is validated in DefaultAndValidateConfiguration()
+	}
+	var lastDigest string
+	retry.JitterUntil(ctx, w.pollPeriod, func(ctx context.Context) {
+		client, err := w.client(ctx)
+		if err != nil {
+			w.log.Error("Failed to get credentials for OCI registry", zap.Error(err))
+			return
+		}
+		digest, err := client.Resolve(ctx, ref)
+		if err != nil {
+			w.log.Error("Failed to resolve OCI artifact", zap.Error(err))
+			return
+		}
+		if digest == lastDigest {
+			w.log.Debug("OCI artifact: no updates")
+			return
+		}
+		manifest, err := client.FetchManifest(ctx, ref, digest)
+		if err != nil {
+			w.log.Error("Failed to fetch OCI artifact manifest", zap.Error(err))
+			return
+		}
+		c := newManifestCollector(req.Paths)
+		for _, layer := range manifest.Layers {
+			err = w.collectLayer(ctx, client, ref, layer, c)
+			if err != nil {
+				w.log.Error("Failed to read manifests from OCI artifact", zap.String("layer_digest", layer.Digest), zap.Error(err))
+				return
+			}
+		}
+		callback(ctx, rpc.ObjectsToSynchronizeData{
+			CommitId: digest,
+			Sources:  c.sources,
+		})
+		lastDigest = digest
+	})
+	return nil
+}
+
+func (w *ociWatcher) collectLayer(ctx context.Context, client *oci.Client, ref oci.Reference, layer oci.Descriptor, c *manifestCollector) error {
+	var compressed bool
+	switch layer.MediaType {
+	case oci.MediaTypeImageLayer:
+	case oci.MediaTypeImageLayerGzip, oci.MediaTypeDockerLayerGzip:
+		compressed = true
+	default:
+		title := layer.Annotations[oci.AnnotationTitle]
+		if title == "" {
+			w.log.Debug("Skipping OCI artifact layer of unknown media type", zap.String("media_type", layer.MediaType))
+			return nil
+		}
+		wanted, err := c.Wants(title, layer.Size)
+		if err != nil || !wanted {
+			return err
+		}
+		data, err := fetchBlob(ctx, client, ref, layer)
+		if err != nil {
+			return err
+		}
+		c.Add(title, data)
+		return nil
+	}
+	blob, err := client.FetchBlob(ctx, ref, layer)
+	if err != nil {
+		return err
+	}
+	defer blob.Close() // nolint: errcheck
+	var r io.Reader = blob
+	if compressed {
+		gz, err := gzip.NewReader(blob)
+		if err != nil {
+			return err
+		}
+		defer gz.Close() // nolint: errcheck
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// Read the layer to the very end so that its digest is verified before the files are accepted.
+				// The archive may end before the layer does, e.g. because of padding or trailing data.
+				_, err = io.Copy(ioutil.Discard, blob)
+			}
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean(hdr.Name), "/")
+		wanted, err := c.Wants(name, hdr.Size)
+		if err != nil {
+			return err
+		}
+		if !wanted {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		c.Add(name, data)
+	}
+}
+
+func (w *ociWatcher) client(ctx context.Context) (*oci.Client, error) {
+	client := &oci.Client{
+		HTTPClient: w.httpClient,
+	}
+	if w.artifact.CredentialsSecret == "" {
+		return client, nil
+	}
+	secret, err := w.kubeClient.CoreV1().Secrets(w.secretNamespace).Get(ctx, w.artifact.CredentialsSecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	client.Username = string(secret.Data[secretKeyUsername])
+	client.Password = string(secret.Data[secretKeyPassword])
+	return client, nil
+}
+
+func fetchBlob(ctx context.Context, client *oci.Client, ref oci.Reference, desc oci.Descriptor) ([]byte, error) {
+	blob, err := client.FetchBlob(ctx, ref, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close() // nolint: errcheck
+	data, err := ioutil.ReadAll(blob)
+	if err != nil {
+		return nil, fmt.Errorf("blob read: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,176 @@
+package agent
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/oci"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestOciWatcher(t *testing.T) {
+	archive := tarGz(t, map[string]string{
+		"manifest.yaml":    "a: b",
+		"dir/manifest.yml": "c: d",
+		"dir/readme.txt":   "bla",
+	})
+	single := []byte("e: f")
+	layers := map[string][]byte{
+		ociDigest(archive): archive,
+		ociDigest(single):  single,
+	}
+	manifest, err := json.Marshal(oci.Manifest{
+		MediaType: oci.MediaTypeImageManifest,
+		Layers: []oci.Descriptor{
+			{
+				MediaType: oci.MediaTypeImageLayerGzip,
+				Digest:    ociDigest(archive),
+				Size:      int64(len(archive)),
+			},
+			{
+				MediaType: "application/yaml",
+				Digest:    ociDigest(single),
+				Size:      int64(len(single)),
+				Annotations: map[string]string{
+					oci.AnnotationTitle: "single.yaml",
+				},
+			},
+			{
+				MediaType: "application/octet-stream",
+				Digest:    ociDigest([]byte("unknown")),
+				Size:      7,
+			},
+		},
+	})
+	require.NoError(t, err)
+	manifestDigest := ociDigest(manifest)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/group/manifests/manifests/main", "/v2/group/manifests/manifests/" + manifestDigest:
+			w.Header().Set("Docker-Content-Digest", manifestDigest)
+			_, _ = w.Write(manifest)
+		default:
+			const blobsPrefix = "/v2/group/manifests/blobs/"
+			if len(r.URL.Path) > len(blobsPrefix) {
+				if data, ok := layers[r.URL.Path[len(blobsPrefix):]]; ok {
+					_, _ = w.Write(data)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	w := &ociWatcher{
+		log: zaptest.NewLogger(t),
+		artifact: &agentcfg.OciArtifactCF{
+			Ref: u.Host + "/group/manifests:main",
+		},
+		httpClient: srv.Client(),
+		pollPeriod: time.Minute,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req := &rpc.ObjectsToSynchronizeRequest{
+		Paths: []*agentcfg.PathCF{
+			{
+				Glob: defaultGitOpsManifestPathGlob,
+			},
+		},
+	}
+	var data rpc.ObjectsToSynchronizeData
+	err = w.Watch(ctx, req, func(ctx context.Context, d rpc.ObjectsToSynchronizeData) {
+		data = d
+		cancel()
+	})
+	require.NoError(t, err)
+	assert.Equal(t, manifestDigest, data.CommitId)
+	assert.ElementsMatch(t, []rpc.ObjectSource{
+		{
+			Name: "manifest.yaml",
+			Data: []byte("a: b"),
+		},
+		{
+			Name: "dir/manifest.yml",
+			Data: []byte("c: d"),
+		},
+		{
+			Name: "single.yaml",
+			Data: single,
+		},
+	}, data.Sources)
+}
+
+func TestOciWatcher_LayerDigestMismatchAfterArchiveEnd(t *testing.T) {
+	archive := tarGz(t, map[string]string{
+		"manifest.yaml": "a: b",
+	})
+	tampered := append(append([]byte(nil), archive...), "junk"...)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(tampered)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	ref, err := oci.ParseReference(u.Host + "/group/manifests:main")
+	require.NoError(t, err)
+
+	w := &ociWatcher{
+		log: zaptest.NewLogger(t),
+	}
+	c := newManifestCollector([]*agentcfg.PathCF{
+		{
+			Glob: defaultGitOpsManifestPathGlob,
+		},
+	})
+	err = w.collectLayer(context.Background(), &oci.Client{HTTPClient: srv.Client()}, ref, oci.Descriptor{
+		MediaType: oci.MediaTypeImageLayerGzip,
+		Digest:    ociDigest(archive),
+		Size:      int64(len(tampered)),
+	}, c)
+	assert.EqualError(t, err, fmt.Sprintf("blob digest mismatch: expected %s, got %s", ociDigest(archive), ociDigest(tampered)))
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+		})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func ociDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,233 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// maxConcurrentAccessReviews limits how many SelfSubjectAccessReviews are in flight at once.
+	maxConcurrentAccessReviews = 10
+)
+
+var (
+	// applyVerbs are the verbs needed to apply an object. kubectl apply gets the object and then creates or patches it.
+	applyVerbs = []string{"get", "create", "patch"}
+)
+
+// permissionChecker checks, using SelfSubjectAccessReviews, that the agent is allowed to apply objects.
+// Objects are not pruned, so permissions to delete objects that are no longer in the desired state are not checked.
+// Reviews are performed with the same client configuration as synchronization so they are for the identity
+// objects are applied as.
+// All methods are safe to call on a nil instance, they are no-op then.
+type permissionChecker struct {
+	log        *zap.Logger
+	kubeClient kubernetes.Interface
+}
+
+// missingPermission is a permission that the agent needs to synchronize the desired state but does not have.
+type missingPermission struct {
+	Group     string
+	Resource  string
+	Namespace string
+	Verb      string
+	Objects   []string
+}
+
+type permissionKey struct {
+	group     string
+	resource  string
+	namespace string
+	verb      string
+}
+
+// check returns an error describing missing permissions, if any. It logs missing permissions per object.
+// Objects of kinds that are not known to the cluster yet are skipped.
+func (c *permissionChecker) check(ctx context.Context, restMapper meta.RESTMapper, commitId, defaultNamespace string, objs []*unstructured.Unstructured) error {
+	if c == nil {
+		return nil
+	}
+	required := make(map[permissionKey][]string) // permission -> objects that need it
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			// E.g. a custom resource, the definition of which is in the same commit.
+			c.log.Debug("Skipping permission check for object of unknown kind", zap.String("kind", gvk.String()), zap.Error(err))
+			continue
+		}
+		namespace := ""
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			namespace = obj.GetNamespace()
+			if namespace == "" {
+				namespace = defaultNamespace
+			}
+		}
+		name := objectDisplayName(gvk.Kind, namespace, obj.GetName())
+		for _, verb := range applyVerbs {
+			key := permissionKey{
+				group:     mapping.Resource.Group,
+				resource:  mapping.Resource.Resource,
+				namespace: namespace,
+				verb:      verb,
+			}
+			required[key] = append(required[key], name)
+		}
+	}
+	missing, err := c.missingPermissions(ctx, required)
+	if err != nil {
+		return err
+	}
+	sortMissingPermissions(missing)
+	for _, m := range missing {
+		for _, obj := range m.Objects {
+			c.log.Warn("Missing permission to synchronize object", logz.CommitId(commitId),
+				zap.String("object", obj), zap.String("verb", m.Verb), zap.String("resource", resourceDisplayName(m.Group, m.Resource)))
+		}
+	}
+	if len(missing) > 0 {
+		return newMissingPermissionsError(missing)
+	}
+	return nil
+}
+
+// missingPermissions performs access reviews for the required permissions concurrently.
+func (c *permissionChecker) missingPermissions(ctx context.Context, required map[permissionKey][]string) ([]missingPermission, error) {
+	var (
+		mu      sync.Mutex
+		missing []missingPermission
+	)
+	g, groupCtx := errgroup.WithContext(ctx)
+	limit := make(chan struct{}, maxConcurrentAccessReviews)
+	for key, objects := range required {
+		key := key
+		objects := objects
+		select {
+		case <-groupCtx.Done():
+		case limit <- struct{}{}:
+			g.Go(func() error {
+				defer func() { <-limit }()
+				allowed, err := c.isAllowed(groupCtx, key)
+				if err != nil {
+					return err
+				}
+				if allowed {
+					return nil
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				missing = append(missing, missingPermission{
+					Group:     key.group,
+					Resource:  key.resource,
+					Namespace: key.namespace,
+					Verb:      key.verb,
+					Objects:   objects,
+				})
+				return nil
+			})
+		}
+	}
+	err := g.Wait()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SelfSubjectAccessReview: %v", err)
+	}
+	return missing, nil
+}
+
+func (c *permissionChecker) isAllowed(ctx context.Context, key permissionKey) (bool, error) {
+	review, err := c.kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: key.namespace,
+				Verb:      key.verb,
+				Group:     key.group,
+				Resource:  key.resource,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// missingPermissionsError is returned when the agent does not have permissions to apply the desired state.
+type missingPermissionsError struct {
+	missing []missingPermission
+}
+
+func newMissingPermissionsError(missing []missingPermission) error {
+	return &missingPermissionsError{
+		missing: missing,
+	}
+}
+
+func (e *missingPermissionsError) Error() string {
+	var msg strings.Builder
+	msg.WriteString("missing permissions: ")
+	for i, m := range e.missing {
+		if i > 0 {
+			msg.WriteString("; ")
+		}
+		where := "cluster-wide"
+		if m.Namespace != "" {
+			where = "in namespace " + m.Namespace
+		}
+		fmt.Fprintf(&msg, "%s %s %s (%d objects)", m.Verb, resourceDisplayName(m.Group, m.Resource), where, len(m.Objects))
+	}
+	return msg.String()
+}
+
+func sortMissingPermissions(missing []missingPermission) {
+	sort.Slice(missing, func(i, j int) bool {
+		a, b := missing[i], missing[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return verbOrder(a.Verb) < verbOrder(b.Verb)
+	})
+}
+
+func verbOrder(verb string) int {
+	for i, v := range applyVerbs {
+		if v == verb {
+			return i
+		}
+	}
+	return len(applyVerbs)
+}
+
+func resourceDisplayName(group, resource string) string {
+	if group == "" {
+		return resource
+	}
+	return resource + "." + group
+}
+
+func objectDisplayName(kind, namespace, name string) string {
+	if namespace == "" {
+		return kind + "/" + name
+	}
+	return kind + "/" + namespace + "/" + name
+}
@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestPermissionChecker(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}, {Group: "apps", Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	obj := func(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+		o := &unstructured.Unstructured{}
+		o.SetAPIVersion(apiVersion)
+		o.SetKind(kind)
+		o.SetNamespace(namespace)
+		o.SetName(name)
+		return o
+	}
+	objs := []*unstructured.Unstructured{
+		obj("v1", "ConfigMap", "", "cm1"),
+		obj("v1", "ConfigMap", "ns2", "cm2"),
+		obj("v1", "Namespace", "", "ns2"),
+		obj("apps/v1", "Deployment", "ns1", "app"),
+		obj("example.com/v1", "Unknown", "ns1", "x"), // skipped
+	}
+	denied := map[authorizationv1.ResourceAttributes]bool{
+		{Namespace: "ns2", Verb: "patch", Resource: "configmaps"}: true,
+		{Verb: "create", Resource: "namespaces"}:                  true,
+		{Verb: "patch", Resource: "namespaces"}:                   true,
+	}
+	allowAll := false
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = allowAll || !denied[*review.Spec.ResourceAttributes]
+		return true, review, nil
+	})
+	c := &permissionChecker{
+		log:        zaptest.NewLogger(t),
+		kubeClient: kubeClient,
+	}
+	ctx := context.Background()
+	err := c.check(ctx, restMapper, gitHash1, "ns1", objs)
+	require.Error(t, err)
+	var permErr *missingPermissionsError
+	require.True(t, errors.As(err, &permErr))
+	assert.EqualError(t, err, "missing permissions: create namespaces cluster-wide (1 objects); patch namespaces cluster-wide (1 objects); patch configmaps in namespace ns2 (1 objects)")
+	assert.Equal(t, []missingPermission{
+		{
+			Resource: "namespaces",
+			Verb:     "create",
+			Objects:  []string{"Namespace/ns2"},
+		},
+		{
+			Resource: "namespaces",
+			Verb:     "patch",
+			Objects:  []string{"Namespace/ns2"},
+		},
+		{
+			Resource:  "configmaps",
+			Namespace: "ns2",
+			Verb:      "patch",
+			Objects:   []string{"ConfigMap/ns2/cm2"},
+		},
+	}, permErr.missing)
+
+	// Permissions have been granted
+	allowAll = true
+	require.NoError(t, c.check(ctx, restMapper, gitHash2, "ns1", objs))
+}
+
+func TestPermissionCheckerNil(t *testing.T) {
+	var c *permissionChecker
+	assert.NoError(t, c.check(context.Background(), nil, gitHash1, "ns1", nil))
+}
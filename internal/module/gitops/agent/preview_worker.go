@@ -0,0 +1,244 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultPreviewNamespaceTemplate   = "preview-" + previewNamespaceBranchPlaceholder
+	previewNamespaceBranchPlaceholder = "{branch}"
+
+	// previewProjectIdAnnotation is set on namespaces, created for preview environments.
+	// Only namespaces with this annotation, referring to the right project, are deleted.
+	previewProjectIdAnnotation = "agent.gitlab.com/preview-project-id"
+	// previewBranchAnnotation holds the name of the branch the namespace was created for.
+	previewBranchAnnotation = "agent.gitlab.com/preview-branch"
+
+	namespaceCreateRetryPeriod = 10 * time.Second
+
+	// previewNamespaceHashLen is the number of hex digits of the branch name hash in the namespace name.
+	previewNamespaceHashLen = 8
+)
+
+var (
+	// invalidNamespaceChars matches sequences of characters that are not allowed in a namespace name.
+	invalidNamespaceChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// previewWorker synchronizes each branch, matching the configured glob, into its own namespace.
+// Namespace is deleted when the branch is deleted or the project is removed from the configuration. Namespaces are
+// kept when the worker is only stopped, e.g. on shutdown or to restart it with an updated configuration.
+type previewWorker struct {
+	log             *zap.Logger
+	project         *agentcfg.ManifestProjectCF
+	branchesWatcher rpc.BranchesWatcherInterface
+	kubeClient      kubernetes.Interface
+	// newBranchWorker constructs a worker to synchronize a single branch of the project.
+	newBranchWorker func(project *agentcfg.ManifestProjectCF, branch string) GitopsWorker
+}
+
+func (w *previewWorker) Run(ctx context.Context) {
+	workers := make(map[string]*previewBranchWorkerHolder) // branch name -> worker holder instance
+	defer stopAllBranchWorkers(workers)
+	req := &rpc.BranchesRequest{
+		ProjectId:  w.project.Id,
+		BranchGlob: w.project.PreviewEnvironments.BranchGlob,
+	}
+	firstList := true
+	w.branchesWatcher.Watch(ctx, req, func(ctx context.Context, branches []string) {
+		if firstList {
+			// Branches might have been deleted while the worker was not running.
+			w.deleteNamespaces(ctx, sets.NewString(branches...))
+			firstList = false
+		}
+		w.configureBranchWorkers(ctx, workers, branches)
+	})
+}
+
+// Cleanup deletes namespaces of all preview environments of the project.
+func (w *previewWorker) Cleanup(ctx context.Context) {
+	w.deleteNamespaces(ctx, sets.NewString())
+}
+
+func (w *previewWorker) configureBranchWorkers(ctx context.Context, workers map[string]*previewBranchWorkerHolder, branches []string) {
+	newSetOfBranches := sets.NewString(branches...)
+	var workersToStop []*previewBranchWorkerHolder
+	for branch, workerHolder := range workers {
+		if newSetOfBranches.Has(branch) {
+			continue
+		}
+		workersToStop = append(workersToStop, workerHolder)
+	}
+	for _, workerHolder := range workersToStop {
+		w.log.Info("Branch has been removed, stopping preview environment", logz.GitBranch(workerHolder.branch))
+		workerHolder.stop()
+		delete(workers, workerHolder.branch)
+	}
+	for _, workerHolder := range workersToStop {
+		workerHolder.wg.Wait()
+		workerHolder.worker.Cleanup(ctx)
+		w.deleteNamespace(ctx, workerHolder.branch, workerHolder.namespace)
+	}
+	for _, branch := range branches {
+		if workers[branch] != nil {
+			continue
+		}
+		w.startBranchWorker(workers, branch)
+	}
+}
+
+func (w *previewWorker) startBranchWorker(workers map[string]*previewBranchWorkerHolder, branch string) {
+	namespace, err := previewNamespaceName(w.project.PreviewEnvironments.NamespaceTemplate, branch)
+	if err != nil {
+		w.log.Error("Cannot start preview environment", logz.GitBranch(branch), zap.Error(err))
+		return
+	}
+	log := w.log.With(logz.GitBranch(branch), logz.Namespace(namespace))
+	log.Info("Starting preview environment")
+	project := proto.Clone(w.project).(*agentcfg.ManifestProjectCF)
+	project.DefaultNamespace = namespace
+	project.PreviewEnvironments = nil
+	worker := w.newBranchWorker(project, branch)
+	ctx, cancel := context.WithCancel(context.Background())
+	workerHolder := &previewBranchWorkerHolder{
+		branch:    branch,
+		namespace: namespace,
+		worker:    worker,
+		stop:      cancel,
+	}
+	workerHolder.wg.StartWithContext(ctx, func(ctx context.Context) {
+		if !w.ensureNamespace(ctx, log, branch, namespace) {
+			return // context is done
+		}
+		worker.Run(ctx)
+	})
+	workers[branch] = workerHolder
+}
+
+// ensureNamespace creates the namespace for the branch, retrying until it succeeds or ctx is done.
+func (w *previewWorker) ensureNamespace(ctx context.Context, log *zap.Logger, branch, namespace string) bool /* created */ {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+			Annotations: map[string]string{
+				previewProjectIdAnnotation: w.project.Id,
+				previewBranchAnnotation:    branch,
+			},
+		},
+	}
+	err := retry.PollImmediateUntil(ctx, namespaceCreateRetryPeriod, func() (bool /*done*/, error) {
+		_, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+		if err != nil && !kerrors.IsAlreadyExists(err) {
+			log.Error("Failed to create namespace for preview environment", zap.Error(err))
+			return false, nil // nil error to keep polling
+		}
+		return true, nil
+	})
+	return err == nil
+}
+
+// deleteNamespace deletes the namespace of the preview environment if it was created for this project and branch.
+func (w *previewWorker) deleteNamespace(ctx context.Context, branch, namespace string) {
+	log := w.log.With(logz.GitBranch(branch), logz.Namespace(namespace))
+	nsClient := w.kubeClient.CoreV1().Namespaces()
+	ns, err := nsClient.Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			log.Error("Failed to get namespace of preview environment", zap.Error(err))
+		}
+		return
+	}
+	if ns.Annotations[previewProjectIdAnnotation] != w.project.Id || ns.Annotations[previewBranchAnnotation] != branch {
+		log.Warn("Namespace was not created for this preview environment, not deleting it")
+		return
+	}
+	log.Info("Deleting namespace of preview environment")
+	err = nsClient.Delete(ctx, namespace, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Error("Failed to delete namespace of preview environment", zap.Error(err))
+	}
+}
+
+// deleteNamespaces deletes namespaces, created for preview environments of the project, except for the namespaces
+// of the branches to keep.
+func (w *previewWorker) deleteNamespaces(ctx context.Context, keep sets.String) {
+	nsList, err := w.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Error("Failed to list namespaces of preview environments", zap.Error(err))
+		}
+		return
+	}
+	for _, ns := range nsList.Items {
+		branch, ok := ns.Annotations[previewBranchAnnotation]
+		if !ok || ns.Annotations[previewProjectIdAnnotation] != w.project.Id || keep.Has(branch) {
+			continue
+		}
+		w.log.Info("Branch has been removed, deleting preview environment", logz.GitBranch(branch), logz.Namespace(ns.Name))
+		w.deleteNamespace(ctx, branch, ns.Name)
+	}
+}
+
+// previewNamespaceName converts the branch name into a valid namespace name and substitutes it into the template.
+// A short hash of the branch name is appended to it so that branches, that only differ in characters that are
+// replaced or truncated, get different namespaces.
+func previewNamespaceName(template, branch string) (string, error) {
+	sum := sha256.Sum256([]byte(branch))
+	hash := hex.EncodeToString(sum[:])[:previewNamespaceHashLen]
+	prefixAndSuffixLen := len(template) - len(previewNamespaceBranchPlaceholder) + len(hash) + 1 // +1 for the "-" before hash
+	name := strings.Trim(invalidNamespaceChars.ReplaceAllString(strings.ToLower(branch), "-"), "-")
+	if maxLen := validation.DNS1123LabelMaxLength - prefixAndSuffixLen; len(name) > maxLen {
+		if maxLen < 0 {
+			maxLen = 0
+		}
+		name = strings.Trim(name[:maxLen], "-")
+	}
+	if name == "" {
+		return "", fmt.Errorf("branch name %q does not produce a valid namespace name", branch)
+	}
+	namespace := strings.Replace(template, previewNamespaceBranchPlaceholder, name+"-"+hash, 1)
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", fmt.Errorf("namespace name %q for branch %q is invalid: %s", namespace, branch, strings.Join(errs, ", "))
+	}
+	return namespace, nil
+}
+
+// stopAllBranchWorkers stops all workers. Namespaces of their preview environments are kept.
+func stopAllBranchWorkers(workers map[string]*previewBranchWorkerHolder) {
+	// Tell all workers to stop
+	for _, workerHolder := range workers {
+		workerHolder.stop()
+	}
+	// Wait for all workers to stop
+	for _, workerHolder := range workers {
+		workerHolder.wg.Wait()
+	}
+}
+
+type previewBranchWorkerHolder struct {
+	branch    string
+	namespace string
+	worker    GitopsWorker
+	wg        wait.Group
+	stop      context.CancelFunc
+}
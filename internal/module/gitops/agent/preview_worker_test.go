@@ -0,0 +1,178 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/matcher"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/mock_rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPreviewNamespaceName(t *testing.T) {
+	tests := []struct {
+		template string
+		branch   string
+		expected string
+	}{
+		{
+			template: defaultPreviewNamespaceTemplate,
+			branch:   "feature",
+			expected: "preview-feature-2ad56231",
+		},
+		{
+			template: defaultPreviewNamespaceTemplate,
+			branch:   "review/Fix_Bug-123",
+			expected: "preview-review-fix-bug-123-6989d826",
+		},
+		{
+			template: "{branch}-env",
+			branch:   "-feature-",
+			expected: "feature-da4c408e-env",
+		},
+		{
+			template: defaultPreviewNamespaceTemplate,
+			branch:   "a-very-long-branch-name-that-does-not-fit-into-a-namespace-name-at-all",
+			expected: "preview-a-very-long-branch-name-that-does-not-fit-into-30f81851",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.branch, func(t *testing.T) {
+			name, err := previewNamespaceName(tc.template, tc.branch) // nolint: scopelint
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, name) // nolint: scopelint
+		})
+	}
+}
+
+func TestPreviewNamespaceNameIsUnique(t *testing.T) {
+	name1, err := previewNamespaceName(defaultPreviewNamespaceTemplate, "review/fix")
+	require.NoError(t, err)
+	name2, err := previewNamespaceName(defaultPreviewNamespaceTemplate, "review_fix")
+	require.NoError(t, err)
+	assert.NotEqual(t, name1, name2)
+}
+
+func TestPreviewNamespaceNameErrors(t *testing.T) {
+	_, err := previewNamespaceName(defaultPreviewNamespaceTemplate, "///")
+	assert.EqualError(t, err, `branch name "///" does not produce a valid namespace name`)
+	_, err = previewNamespaceName("a-very-long-template-that-leaves-no-space-for-the-branch-{branch}", "feature")
+	assert.EqualError(t, err, `branch name "feature" does not produce a valid namespace name`)
+}
+
+func TestPreviewWorkerStartsAndStopsBranchWorkers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	project := &agentcfg.ManifestProjectCF{
+		Id: "bla",
+		PreviewEnvironments: &agentcfg.PreviewEnvironmentsCF{
+			BranchGlob:        "review/*",
+			NamespaceTemplate: defaultPreviewNamespaceTemplate,
+		},
+	}
+	nsA := namespaceForBranch(t, "review/a")
+	nsB := namespaceForBranch(t, "review/b")
+	nsC := namespaceForBranch(t, "review/c")
+	nsOld := namespaceForBranch(t, "review/old")
+	kubeClient := fake.NewSimpleClientset(
+		// A namespace that was not created by the agent must be left intact.
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: nsB,
+			},
+		},
+		// The branch of this namespace has been deleted while the worker was not running.
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: nsOld,
+				Annotations: map[string]string{
+					previewProjectIdAnnotation: "bla",
+					previewBranchAnnotation:    "review/old",
+				},
+			},
+		},
+	)
+	branchesWatcher := mock_rpc.NewMockBranchesWatcherInterface(ctrl)
+	branchWorker := NewMockGitopsWorker(ctrl)
+	branchWorker.EXPECT().
+		Run(gomock.Any()).
+		DoAndReturn(func(ctx context.Context) {
+			<-ctx.Done()
+		}).
+		Times(3)
+	branchWorker.EXPECT().
+		Cleanup(gomock.Any()).
+		Times(2) // review/a and review/b
+	var startedBranches []string
+	w := &previewWorker{
+		log:             zaptest.NewLogger(t),
+		project:         project,
+		branchesWatcher: branchesWatcher,
+		kubeClient:      kubeClient,
+		newBranchWorker: func(p *agentcfg.ManifestProjectCF, branch string) GitopsWorker {
+			assert.Nil(t, p.PreviewEnvironments)
+			assert.Equal(t, namespaceForBranch(t, branch), p.DefaultNamespace)
+			startedBranches = append(startedBranches, branch)
+			return branchWorker
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	branchesWatcher.EXPECT().
+		Watch(gomock.Any(), matcher.ProtoEq(t, &rpc.BranchesRequest{
+			ProjectId:  "bla",
+			BranchGlob: "review/*",
+		}), gomock.Any()).
+		Do(func(ctx context.Context, req *rpc.BranchesRequest, callback rpc.BranchesCallback) {
+			callback(ctx, []string{"review/a", "review/b", "review/c", "!!!"}) // last one does not produce a valid name
+			var ns *corev1.Namespace
+			require.Eventually(t, func() bool {
+				var err error
+				ns, err = kubeClient.CoreV1().Namespaces().Get(ctx, nsA, metav1.GetOptions{})
+				if err != nil {
+					return false
+				}
+				_, err = kubeClient.CoreV1().Namespaces().Get(ctx, nsC, metav1.GetOptions{})
+				return err == nil
+			}, time.Second, 10*time.Millisecond) // namespaces are created by the branch workers' goroutines
+			assert.Equal(t, "bla", ns.Annotations[previewProjectIdAnnotation])
+			assert.Equal(t, "review/a", ns.Annotations[previewBranchAnnotation])
+			_, err := kubeClient.CoreV1().Namespaces().Get(ctx, nsOld, metav1.GetOptions{})
+			assert.True(t, kerrors.IsNotFound(err))
+
+			callback(ctx, []string{"review/c"})
+			_, err = kubeClient.CoreV1().Namespaces().Get(ctx, nsA, metav1.GetOptions{})
+			assert.True(t, kerrors.IsNotFound(err))
+			_, err = kubeClient.CoreV1().Namespaces().Get(ctx, nsB, metav1.GetOptions{})
+			assert.NoError(t, err)
+			_, err = kubeClient.CoreV1().Namespaces().Get(ctx, nsC, metav1.GetOptions{})
+			assert.NoError(t, err)
+		})
+	w.Run(ctx)
+	assert.ElementsMatch(t, []string{"review/a", "review/b", "review/c"}, startedBranches)
+	// Namespaces are kept when the worker stops
+	_, err := kubeClient.CoreV1().Namespaces().Get(context.Background(), nsC, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// Namespaces are deleted when the project is removed
+	w.Cleanup(context.Background())
+	_, err = kubeClient.CoreV1().Namespaces().Get(context.Background(), nsC, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+	_, err = kubeClient.CoreV1().Namespaces().Get(context.Background(), nsB, metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func namespaceForBranch(t *testing.T, branch string) string {
+	name, err := previewNamespaceName(defaultPreviewNamespaceTemplate, branch)
+	require.NoError(t, err)
+	return name
+}
@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v2"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+)
+
+const (
+	defaultRendererTimeout      = 30 * time.Second
+	defaultRendererMaxMemoryMiB = 1024
+	defaultRendererMaxCPUTime   = time.Minute
+	// maxRenderedOutputSize is the maximum size of manifests a renderer can produce.
+	maxRenderedOutputSize = 10 * 1024 * 1024
+	// maxRendererStderrSize is the maximum size of the tail of stderr to include into an error.
+	maxRendererStderrSize = 4 * 1024
+
+	rendererEnvProjectId = "GITOPS_PROJECT_ID"
+	rendererEnvCommitId  = "GITOPS_COMMIT_ID"
+)
+
+// renderObjectsToSynchronize runs renderers of project's paths on the files that match them.
+// Files that do not match a path with a renderer are returned as is.
+func renderObjectsToSynchronize(ctx context.Context, project *agentcfg.ManifestProjectCF, data rpc.ObjectsToSynchronizeData) (rpc.ObjectsToSynchronizeData, error) {
+	var renderPaths []*agentcfg.PathCF
+	for _, p := range project.Paths {
+		if p.Renderer != nil {
+			renderPaths = append(renderPaths, p)
+		}
+	}
+	if len(renderPaths) == 0 {
+		return data, nil
+	}
+	inputs := make([][]rpc.ObjectSource, len(renderPaths))
+	result := rpc.ObjectsToSynchronizeData{
+		CommitId: data.CommitId,
+		Tag:      data.Tag,
+	}
+	for _, source := range data.Sources {
+		idx, err := renderPathIndex(renderPaths, source.Name)
+		if err != nil {
+			return rpc.ObjectsToSynchronizeData{}, err
+		}
+		if idx == -1 {
+			result.Sources = append(result.Sources, source)
+			continue
+		}
+		inputs[idx] = append(inputs[idx], source)
+	}
+	for i, p := range renderPaths {
+		if len(inputs[i]) == 0 {
+			continue
+		}
+		output, err := render(ctx, p.Renderer, project.Id, data.CommitId, inputs[i])
+		if err != nil {
+			return rpc.ObjectsToSynchronizeData{}, fmt.Errorf("glob %s: %v", p.Glob, err)
+		}
+		result.Sources = append(result.Sources, rpc.ObjectSource{
+			Name: fmt.Sprintf("%s (rendered)", p.Glob),
+			Data: output,
+		})
+	}
+	return result, nil
+}
+
+// renderPathIndex returns the index of the first path that matches the file or -1 if there is no such path.
+func renderPathIndex(paths []*agentcfg.PathCF, filename string) (int, error) {
+	for i, p := range paths {
+		glob := strings.TrimPrefix(p.Glob, "/")
+		match, err := doublestar.Match(glob, filename)
+		if err != nil {
+			return 0, fmt.Errorf("glob %s match failed: %v", p.Glob, err)
+		}
+		if match {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+func render(ctx context.Context, renderer *agentcfg.RendererCF, projectId, commitId string, sources []rpc.ObjectSource) (output []byte, retErr error) {
+	dir, err := ioutil.TempDir("", "gitops-render-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := os.RemoveAll(dir)
+		if retErr == nil && err != nil {
+			retErr = err
+		}
+	}()
+	for _, source := range sources {
+		name := path.Clean(source.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("invalid file name: %s", source.Name)
+		}
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		err = os.MkdirAll(filepath.Dir(filename), 0700)
+		if err != nil {
+			return nil, err
+		}
+		err = ioutil.WriteFile(filename, source.Data, 0600)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, renderer.Timeout.AsDuration())
+	defer cancel()
+	stdout := &limitedBuffer{limit: maxRenderedOutputSize}
+	stderr := &tailBuffer{limit: maxRendererStderrSize}
+	cmd := exec.CommandContext(ctx, renderer.Command[0], renderer.Command[1:]...) // nolint: gosec
+	cmd.Dir = dir
+	// Do not leak agentk's environment into the renderer.
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + dir,
+		rendererEnvProjectId + "=" + projectId,
+		rendererEnvCommitId + "=" + commitId,
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err = cmd.Start()
+	if err == nil {
+		// Limits are applied right after the process has started, before it had a chance to do any meaningful work.
+		err = setRendererLimits(cmd.Process.Pid, uint64(renderer.MaxMemoryMib)*1024*1024, renderer.MaxCpuTime.AsDuration())
+		if err != nil {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return nil, fmt.Errorf("renderer %s: failed to set resource limits: %v", renderer.Command[0], err)
+		}
+		err = cmd.Wait()
+	}
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, fmt.Errorf("renderer %s timed out after %s: %s", renderer.Command[0], renderer.Timeout.AsDuration(), stderr)
+	case stdout.exceeded:
+		return nil, fmt.Errorf("renderer %s output is bigger than %d bytes", renderer.Command[0], maxRenderedOutputSize)
+	case err != nil:
+		return nil, fmt.Errorf("renderer %s failed: %v: %s", renderer.Command[0], err, stderr)
+	}
+	return stdout.buf.Bytes(), nil
+}
+
+// limitedBuffer accumulates up to limit bytes. Writes beyond the limit fail.
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buf.Len()+len(p) > b.limit {
+		b.exceeded = true
+		return 0, fmt.Errorf("output is bigger than %d bytes", b.limit)
+	}
+	return b.buf.Write(p)
+}
+
+// tailBuffer keeps the last limit bytes that have been written to it.
+type tailBuffer struct {
+	buf   []byte
+	limit int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return strings.TrimSpace(string(b.buf))
+}
@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// setRendererLimits limits the virtual memory size and CPU time of the renderer process.
+func setRendererLimits(pid int, maxMemory uint64, maxCPUTime time.Duration) error {
+	err := prlimit(pid, syscall.RLIMIT_AS, &syscall.Rlimit{
+		Cur: maxMemory,
+		Max: maxMemory,
+	})
+	if err != nil {
+		return err
+	}
+	cpuSeconds := uint64((maxCPUTime + time.Second - 1) / time.Second)
+	// SIGXCPU is sent when the soft limit is reached and SIGKILL when the hard limit is reached.
+	return prlimit(pid, syscall.RLIMIT_CPU, &syscall.Rlimit{
+		Cur: cpuSeconds,
+		Max: cpuSeconds + 1,
+	})
+}
+
+func prlimit(pid, resource int, limit *syscall.Rlimit) error {
+	_, _, errno := syscall.RawSyscall6(syscall.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(limit)), 0, 0, 0) // nolint: gosec
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
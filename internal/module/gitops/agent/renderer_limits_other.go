@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package agent
+
+import (
+	"time"
+)
+
+// setRendererLimits is a no-op. agentk only runs on Linux, resource limits are not supported elsewhere.
+func setRendererLimits(pid int, maxMemory uint64, maxCPUTime time.Duration) error {
+	return nil
+}
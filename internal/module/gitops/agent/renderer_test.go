@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestRenderObjectsToSynchronize(t *testing.T) {
+	project := &agentcfg.ManifestProjectCF{
+		Id: "group/project",
+		Paths: []*agentcfg.PathCF{
+			{
+				Glob: "/plain/*.yaml",
+			},
+			{
+				Glob: "/jsonnet/**",
+				Renderer: &agentcfg.RendererCF{
+					Command:      []string{"sh", "-c", `cat lib/a.txt jsonnet/b.txt`},
+					Timeout:      durationpb.New(10 * time.Second),
+					MaxMemoryMib: defaultRendererMaxMemoryMiB,
+					MaxCpuTime:   durationpb.New(defaultRendererMaxCPUTime),
+				},
+			},
+		},
+	}
+	data := rpc.ObjectsToSynchronizeData{
+		CommitId: gitHash1,
+		Sources: []rpc.ObjectSource{
+			{
+				Name: "plain/cm.yaml",
+				Data: []byte("a: b"),
+			},
+			{
+				Name: "jsonnet/b.txt",
+				Data: []byte("b\n"),
+			},
+		},
+	}
+	// lib/a.txt does not exist
+	_, err := renderObjectsToSynchronize(context.Background(), project, data)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "glob /jsonnet/**: renderer sh failed: exit status 1: "), err.Error())
+	assert.Contains(t, err.Error(), "lib/a.txt")
+
+	project.Paths[1].Renderer.Command[2] = `cat jsonnet/b.txt; echo "$GITOPS_PROJECT_ID $GITOPS_COMMIT_ID"`
+	rendered, err := renderObjectsToSynchronize(context.Background(), project, data)
+	require.NoError(t, err)
+	assert.Equal(t, rpc.ObjectsToSynchronizeData{
+		CommitId: gitHash1,
+		Sources: []rpc.ObjectSource{
+			{
+				Name: "plain/cm.yaml",
+				Data: []byte("a: b"),
+			},
+			{
+				Name: "/jsonnet/** (rendered)",
+				Data: []byte("b\ngroup/project " + gitHash1 + "\n"),
+			},
+		},
+	}, rendered)
+}
+
+func TestRenderTimeout(t *testing.T) {
+	renderer := &agentcfg.RendererCF{
+		Command:      []string{"sh", "-c", "echo started >&2; exec sleep 10"},
+		Timeout:      durationpb.New(100 * time.Millisecond),
+		MaxMemoryMib: defaultRendererMaxMemoryMiB,
+		MaxCpuTime:   durationpb.New(defaultRendererMaxCPUTime),
+	}
+	_, err := render(context.Background(), renderer, "group/project", gitHash1, nil)
+	assert.EqualError(t, err, "renderer sh timed out after 100ms: started")
+}
+
+func TestRenderOutputLimit(t *testing.T) {
+	renderer := &agentcfg.RendererCF{
+		Command:      []string{"sh", "-c", "head -c 10485761 /dev/zero"},
+		Timeout:      durationpb.New(10 * time.Second),
+		MaxMemoryMib: defaultRendererMaxMemoryMiB,
+		MaxCpuTime:   durationpb.New(defaultRendererMaxCPUTime),
+	}
+	_, err := render(context.Background(), renderer, "group/project", gitHash1, nil)
+	assert.EqualError(t, err, "renderer sh output is bigger than 10485760 bytes")
+}
+
+func TestRenderRejectsFilesOutsideOfDirectory(t *testing.T) {
+	renderer := &agentcfg.RendererCF{
+		Command:      []string{"true"},
+		Timeout:      durationpb.New(10 * time.Second),
+		MaxMemoryMib: defaultRendererMaxMemoryMiB,
+		MaxCpuTime:   durationpb.New(defaultRendererMaxCPUTime),
+	}
+	_, err := render(context.Background(), renderer, "group/project", gitHash1, []rpc.ObjectSource{
+		{
+			Name: "../a.yaml",
+		},
+	})
+	assert.EqualError(t, err, "invalid file name: ../a.yaml")
+}
+
+func TestRenderCPUTimeLimit(t *testing.T) {
+	renderer := &agentcfg.RendererCF{
+		Command:      []string{"sh", "-c", "while :; do :; done"},
+		Timeout:      durationpb.New(20 * time.Second),
+		MaxMemoryMib: defaultRendererMaxMemoryMiB,
+		MaxCpuTime:   durationpb.New(time.Second),
+	}
+	_, err := render(context.Background(), renderer, "group/project", gitHash1, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "renderer sh failed: signal: CPU time limit exceeded")
+}
+
+func TestRenderMemoryLimit(t *testing.T) {
+	renderer := &agentcfg.RendererCF{
+		// Allocates a 64 MiB variable.
+		Command:      []string{"sh", "-c", `x=$(head -c 67108864 /dev/zero | tr '\0' a); echo done`},
+		Timeout:      durationpb.New(20 * time.Second),
+		MaxMemoryMib: 16,
+		MaxCpuTime:   durationpb.New(defaultRendererMaxCPUTime),
+	}
+	_, err := render(context.Background(), renderer, "group/project", gitHash1, nil)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "renderer sh failed: "), err.Error())
+}
@@ -0,0 +1,571 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/cache"
+	"github.com/argoproj/gitops-engine/pkg/diff"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	gitopsProjectKind = "GitOpsProject"
+	// gitopsStatusRefreshPeriod is how often health of managed objects is re-evaluated between synchronizations.
+	gitopsStatusRefreshPeriod = time.Minute
+	// maxGitopsStatusAPICheckPeriod is the maximum period between checks if the GitOpsProject API is served.
+	maxGitopsStatusAPICheckPeriod = 16 * time.Minute
+	// maxGitopsStatusMessageLength is the maximum length of the error message in the status.
+	maxGitopsStatusMessageLength = 1024
+
+	gitopsPhasePending = "Pending"
+	gitopsPhaseWaiting = "Waiting"
+	gitopsPhaseSyncing = "Syncing"
+	gitopsPhaseSynced  = "Synced"
+	gitopsPhaseFailed  = "Failed"
+)
+
+var (
+	gitopsProjectGVR = schema.GroupVersionResource{
+		Group:    "gitops.agent.gitlab.com",
+		Version:  "v1alpha1",
+		Resource: "gitopsprojects",
+	}
+	invalidObjectNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+type gitopsProjectStatus struct {
+	phase          string
+	commitId       string
+	message        string
+	managedObjects int
+	lastSyncTime   time.Time
+	// missingPermissions holds permissions that prevented synchronization of the commit.
+	missingPermissions []missingPermission
+}
+
+// liveObjectsCache is the part of the gitops-engine cluster cache that is used to read live objects.
+type liveObjectsCache interface {
+	FindResources(namespace string, predicates ...func(r *cache.Resource) bool) map[kube.ResourceKey]*cache.Resource
+}
+
+// gitopsStatusReporter maintains a GitOpsProject custom resource with the synchronization status of a worker.
+// All methods are safe to call on a nil instance, they are no-op then.
+type gitopsStatusReporter struct {
+	log           *zap.Logger
+	dynamicClient dynamic.Interface
+	// objectsCache is where live managed objects are read from. nil until the cluster cache has been created.
+	objectsCache liveObjectsCache
+	// restMapper is the REST mapper of the cluster with managed objects.
+	restMapper meta.RESTMapper
+	namespace  string
+	projectId  string
+	// branch is the synchronized branch. Empty means the default branch.
+	branch        string
+	refreshPeriod time.Duration
+	// eventRecorder is used to emit summary Events for the GitOpsProject object. nil disables Events.
+	eventRecorder record.EventRecorder
+	// projectStates is where readiness of the project is published for projects that depend on it.
+	// nil if other projects cannot depend on this one.
+	projectStates *projectStates
+	// metrics is where the number of drifted objects is published. nil if metrics are not recorded.
+	metrics *projectMetrics
+	// discovery is used to check if the GitOpsProject API is served. nil means the API is assumed to be served.
+	discovery discovery.DiscoveryInterface
+
+	// Fields below are only accessed by the goroutine that writes the status.
+	apiServed bool
+	// apiCheckPeriod is the period between checks if the API is served. It doubles after each check. Zero before
+	// the first check.
+	apiCheckPeriod time.Duration
+	nextAPICheck   time.Time
+	// lastWritten is the object as it has been written last time. The object is only written when it changes.
+	lastWritten *unstructured.Unstructured
+
+	mu     sync.Mutex
+	status gitopsProjectStatus
+	// uid is the UID of the GitOpsProject object once it has been written.
+	uid types.UID
+	// managed holds keys of objects that have been synchronized by the last successful synchronization.
+	managed []kube.ResourceKey
+	// desired holds objects of the last successful synchronization to detect drift.
+	desired          []*unstructured.Unstructured
+	defaultNamespace string
+	updated          chan struct{}
+}
+
+func newGitopsStatusReporter(log *zap.Logger, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, eventRecorder record.EventRecorder, namespace, projectId, branch string) *gitopsStatusReporter {
+	return &gitopsStatusReporter{
+		log:           log,
+		dynamicClient: dynamicClient,
+		restMapper:    restMapper,
+		namespace:     namespace,
+		projectId:     projectId,
+		branch:        branch,
+		refreshPeriod: gitopsStatusRefreshPeriod,
+		eventRecorder: eventRecorder,
+		status: gitopsProjectStatus{
+			phase: gitopsPhasePending,
+		},
+		updated: make(chan struct{}, 1),
+	}
+}
+
+// setObjectsRESTMapper sets the REST mapper of the cluster with managed objects when it is not the cluster,
+// the GitOpsProject object is in. Must be called before run.
+func (r *gitopsStatusReporter) setObjectsRESTMapper(restMapper meta.RESTMapper) {
+	if r == nil {
+		return
+	}
+	r.restMapper = restMapper
+}
+
+// setObjectsCache sets the cluster cache to read live managed objects from. Must be called before run.
+func (r *gitopsStatusReporter) setObjectsCache(objectsCache liveObjectsCache) {
+	if r == nil {
+		return
+	}
+	r.objectsCache = objectsCache
+}
+
+// setDesiredObjects records objects of a successful synchronization.
+// defaultNamespace is the namespace of namespaced objects without a namespace.
+func (r *gitopsStatusReporter) setDesiredObjects(defaultNamespace string, objs []*unstructured.Unstructured) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.desired = objs
+	r.defaultNamespace = defaultNamespace
+}
+
+func (r *gitopsStatusReporter) syncStarted(commitId string, numberOfObjects int) {
+	if r == nil {
+		return
+	}
+	r.setStatus(func(status *gitopsProjectStatus) {
+		status.phase = gitopsPhaseSyncing
+		status.commitId = commitId
+		status.message = ""
+		status.missingPermissions = nil
+		status.managedObjects = numberOfObjects
+	})
+}
+
+// waitingForDependencies records that the commit is not synchronized until the projects are ready.
+func (r *gitopsStatusReporter) waitingForDependencies(commitId string, notReady []string) {
+	if r == nil {
+		return
+	}
+	r.setStatus(func(status *gitopsProjectStatus) {
+		status.phase = gitopsPhaseWaiting
+		status.commitId = commitId
+		status.message = "waiting for dependencies: " + strings.Join(notReady, ", ")
+		status.missingPermissions = nil
+	})
+}
+
+func (r *gitopsStatusReporter) syncFinished(commitId string, results []common.ResourceSyncResult, err error) {
+	if r == nil {
+		return
+	}
+	if err != nil {
+		r.syncFailed(commitId, err)
+		return
+	}
+	var (
+		managed                 []kube.ResourceKey
+		applied, pruned, failed int
+	)
+	for _, res := range results {
+		if res.HookType != "" {
+			continue
+		}
+		switch res.Status {
+		case common.ResultCodePruned:
+			pruned++
+			continue
+		case common.ResultCodePruneSkipped:
+			continue
+		case common.ResultCodeSyncFailed:
+			failed++
+		case common.ResultCodeSynced:
+			if !strings.HasSuffix(res.Message, " unchanged") {
+				applied++
+			}
+		}
+		managed = append(managed, res.ResourceKey)
+	}
+	if failed > 0 {
+		r.event(corev1.EventTypeWarning, eventReasonSyncFailed, "Synchronized commit %s with errors: %d applied, %d pruned, %d failed", commitId, applied, pruned, failed)
+	} else {
+		r.event(corev1.EventTypeNormal, eventReasonSynced, "Synchronized commit %s: %d applied, %d pruned", commitId, applied, pruned)
+	}
+	r.mu.Lock()
+	r.managed = managed
+	r.mu.Unlock()
+	r.setStatus(func(status *gitopsProjectStatus) {
+		status.phase = gitopsPhaseSynced
+		status.commitId = commitId
+		status.message = ""
+		status.missingPermissions = nil
+		status.managedObjects = len(managed)
+		status.lastSyncTime = time.Now()
+	})
+}
+
+// syncFailed records an error that happened while preparing or performing a synchronization.
+func (r *gitopsStatusReporter) syncFailed(commitId string, err error) {
+	if r == nil {
+		return
+	}
+	msg := err.Error()
+	if len(msg) > maxGitopsStatusMessageLength {
+		msg = msg[:maxGitopsStatusMessageLength]
+	}
+	var missingPermissions []missingPermission
+	var permErr *missingPermissionsError
+	if errors.As(err, &permErr) {
+		missingPermissions = permErr.missing
+	}
+	r.setStatus(func(status *gitopsProjectStatus) {
+		status.phase = gitopsPhaseFailed
+		status.commitId = commitId
+		status.message = msg
+		status.missingPermissions = missingPermissions
+	})
+	r.event(corev1.EventTypeWarning, eventReasonSyncFailed, "Failed to synchronize commit %s: %s", commitId, msg)
+}
+
+// event emits an Event for the GitOpsProject object.
+func (r *gitopsStatusReporter) event(eventType, reason, messageFmt string, args ...interface{}) {
+	if r.eventRecorder == nil {
+		return
+	}
+	r.mu.Lock()
+	uid := r.uid
+	r.mu.Unlock()
+	ref := &corev1.ObjectReference{
+		Kind:       gitopsProjectKind,
+		APIVersion: gitopsProjectGVR.GroupVersion().String(),
+		Namespace:  r.namespace,
+		Name:       gitopsProjectName(r.projectId, r.branch),
+		UID:        uid,
+	}
+	r.eventRecorder.Event(ref, eventType, reason, eventMessage(fmt.Sprintf(messageFmt, args...)))
+}
+
+func (r *gitopsStatusReporter) setStatus(f func(status *gitopsProjectStatus)) {
+	r.mu.Lock()
+	f(&r.status)
+	r.mu.Unlock()
+	select {
+	case r.updated <- struct{}{}:
+	default: // an update is already pending
+	}
+}
+
+// run writes the status when it changes. Health of managed objects is re-evaluated periodically.
+// The custom resource is not deleted when the context is done, see cleanup.
+func (r *gitopsStatusReporter) run(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	defer r.removeProjectState()
+	ticker := time.NewTicker(r.refreshPeriod)
+	defer ticker.Stop()
+	r.write(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.updated:
+		case <-ticker.C:
+		}
+		r.write(ctx)
+	}
+}
+
+func (r *gitopsStatusReporter) write(ctx context.Context) {
+	r.mu.Lock()
+	status := r.status
+	managed := r.managed
+	desired := r.desired
+	defaultNamespace := r.defaultNamespace
+	r.mu.Unlock()
+	healthStatus, drifted := r.checkObjects(managed, r.desiredByKey(defaultNamespace, desired))
+	r.metrics.setDriftedObjects(drifted)
+	if r.projectStates != nil {
+		ready := status.phase == gitopsPhaseSynced && (healthStatus == "" || healthStatus == health.HealthStatusHealthy)
+		reason := status.phase
+		if status.phase == gitopsPhaseSynced {
+			reason = string(healthStatus)
+		}
+		r.projectStates.setReady(r.projectId, ready, reason)
+	}
+	obj := r.object(status, healthStatus)
+	if r.lastWritten != nil && equality.Semantic.DeepEqual(r.lastWritten.Object, obj.Object) {
+		return // nothing has changed
+	}
+	if !r.checkAPI() {
+		return
+	}
+	client := r.dynamicClient.Resource(gitopsProjectGVR).Namespace(r.namespace)
+	existing, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	var written *unstructured.Unstructured
+	switch {
+	case err == nil:
+		toWrite := obj.DeepCopy()
+		toWrite.SetResourceVersion(existing.GetResourceVersion())
+		written, err = client.Update(ctx, toWrite, metav1.UpdateOptions{})
+	case kerrors.IsNotFound(err):
+		written, err = client.Create(ctx, obj.DeepCopy(), metav1.CreateOptions{})
+		if kerrors.IsNotFound(err) && r.discovery != nil {
+			// The custom resource definition has been removed. Start checking if it is served again.
+			r.apiServed = false
+			r.apiCheckPeriod = 0
+			r.nextAPICheck = time.Time{}
+			return
+		}
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			r.log.Warn("Failed to write GitOps project status", zap.Error(err))
+		}
+		return
+	}
+	r.lastWritten = obj
+	r.mu.Lock()
+	r.uid = written.GetUID()
+	r.mu.Unlock()
+}
+
+// checkAPI returns true if the GitOpsProject API is served. While it is not, the discovery API is checked
+// with an exponential backoff and the status is not written.
+func (r *gitopsStatusReporter) checkAPI() bool {
+	if r.apiServed || r.discovery == nil {
+		return true
+	}
+	now := time.Now()
+	if now.Before(r.nextAPICheck) {
+		return false
+	}
+	served, err := isAPIServed(r.discovery, gitopsProjectGVR)
+	switch {
+	case err != nil:
+		r.log.Warn("Failed to check if GitOpsProject API is served", zap.Error(err))
+	case served:
+		r.apiServed = true
+		return true
+	case r.apiCheckPeriod == 0:
+		r.log.Info("GitOpsProject custom resource definition is not installed, status is not reported until it is")
+	}
+	switch {
+	case r.apiCheckPeriod == 0:
+		r.apiCheckPeriod = r.refreshPeriod
+	case r.apiCheckPeriod < maxGitopsStatusAPICheckPeriod:
+		r.apiCheckPeriod *= 2
+	}
+	r.nextAPICheck = now.Add(r.apiCheckPeriod)
+	return false
+}
+
+func (r *gitopsStatusReporter) removeProjectState() {
+	if r.projectStates != nil {
+		r.projectStates.remove(r.projectId)
+	}
+}
+
+// cleanup deletes the GitOpsProject object. It is called when the project has been removed from the configuration.
+func (r *gitopsStatusReporter) cleanup(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	err := r.dynamicClient.Resource(gitopsProjectGVR).Namespace(r.namespace).Delete(ctx, gitopsProjectName(r.projectId, r.branch), metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		r.log.Warn("Failed to delete GitOps project status", zap.Error(err))
+	}
+}
+
+// checkObjects returns the worst health status of the objects or an empty string if there are no objects.
+// It also returns the number of objects, the live state of which differs from the desired state.
+// Live objects are read from the cluster cache, no requests are made to the API server.
+func (r *gitopsStatusReporter) checkObjects(keys []kube.ResourceKey, desired map[kube.ResourceKey]*unstructured.Unstructured) (health.HealthStatusCode, int) {
+	if len(keys) == 0 || r.objectsCache == nil {
+		return "", 0
+	}
+	wanted := make(map[kube.ResourceKey]struct{}, len(keys))
+	for _, key := range keys {
+		wanted[key] = struct{}{}
+	}
+	live := r.objectsCache.FindResources("", func(res *cache.Resource) bool {
+		_, ok := wanted[res.ResourceKey()]
+		return ok
+	})
+	worst := health.HealthStatusHealthy
+	drifted := 0
+	for _, key := range keys {
+		res := live[key]
+		status := objectHealth(res)
+		if health.IsWorse(worst, status) {
+			worst = status
+		}
+		if desiredObj, ok := desired[key]; ok && r.isDrifted(desiredObj, res) {
+			drifted++
+		}
+	}
+	return worst, drifted
+}
+
+// isDrifted returns true if the live object is missing or differs from the desired object.
+func (r *gitopsStatusReporter) isDrifted(desired *unstructured.Unstructured, live *cache.Resource) bool {
+	if live == nil {
+		return true
+	}
+	if live.Resource == nil {
+		// The manifest is only cached for objects with the GC mark. The object has lost it, e.g. it has been replaced.
+		return true
+	}
+	res, err := diff.Diff(desired, live.Resource, diff.WithLogr(zapr.NewLogger(r.log)))
+	if err != nil {
+		r.log.Debug("Failed to compare object with desired state", engineResourceKey(live.ResourceKey()), zap.Error(err))
+		return false
+	}
+	return res.Modified
+}
+
+// desiredByKey indexes objects by their keys. Namespaced objects without a namespace are put into the
+// default namespace, like they are when they are applied.
+func (r *gitopsStatusReporter) desiredByKey(defaultNamespace string, objs []*unstructured.Unstructured) map[kube.ResourceKey]*unstructured.Unstructured {
+	result := make(map[kube.ResourceKey]*unstructured.Unstructured, len(objs))
+	for _, obj := range objs {
+		key := kube.GetResourceKey(obj)
+		if key.Namespace == "" {
+			mapping, err := r.restMapper.RESTMapping(schema.GroupKind{Group: key.Group, Kind: key.Kind})
+			if err == nil && mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+				obj = obj.DeepCopy()
+				obj.SetNamespace(defaultNamespace)
+				key.Namespace = defaultNamespace
+			}
+		}
+		result[key] = obj
+	}
+	return result
+}
+
+func objectHealth(res *cache.Resource) health.HealthStatusCode {
+	if res == nil {
+		return health.HealthStatusMissing
+	}
+	obj := res.Resource
+	if obj == nil {
+		return health.HealthStatusUnknown // object without the GC mark, the manifest is not cached
+	}
+	h, err := health.GetResourceHealth(obj, nil)
+	if err != nil {
+		return health.HealthStatusUnknown
+	}
+	if h == nil {
+		return health.HealthStatusHealthy // object kind without health
+	}
+	return h.Status
+}
+
+func (r *gitopsStatusReporter) object(status gitopsProjectStatus, healthStatus health.HealthStatusCode) *unstructured.Unstructured {
+	s := map[string]interface{}{
+		"phase":          status.phase,
+		"managedObjects": int64(status.managedObjects),
+	}
+	if status.commitId != "" {
+		s["commitId"] = status.commitId
+	}
+	if status.message != "" {
+		s["message"] = status.message
+	}
+	if healthStatus != "" {
+		s["health"] = string(healthStatus)
+	}
+	if !status.lastSyncTime.IsZero() {
+		s["lastSyncTime"] = status.lastSyncTime.UTC().Format(time.RFC3339)
+	}
+	if len(status.missingPermissions) > 0 {
+		missing := make([]interface{}, 0, len(status.missingPermissions))
+		for _, m := range status.missingPermissions {
+			p := map[string]interface{}{
+				"resource": m.Resource,
+				"verb":     m.Verb,
+				"objects":  int64(len(m.Objects)),
+			}
+			if m.Group != "" {
+				p["group"] = m.Group
+			}
+			if m.Namespace != "" {
+				p["namespace"] = m.Namespace
+			}
+			missing = append(missing, p)
+		}
+		s["missingPermissions"] = missing
+	}
+	spec := map[string]interface{}{
+		"projectId": r.projectId,
+	}
+	if r.branch != "" {
+		spec["branch"] = r.branch
+	}
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec":   spec,
+			"status": s,
+		},
+	}
+	obj.SetAPIVersion(gitopsProjectGVR.GroupVersion().String())
+	obj.SetKind(gitopsProjectKind)
+	obj.SetNamespace(r.namespace)
+	obj.SetName(gitopsProjectName(r.projectId, r.branch))
+	obj.SetLabels(map[string]string{
+		"app.kubernetes.io/managed-by": "gitlab-agent",
+	})
+	return obj
+}
+
+// gitopsProjectName constructs a valid object name for the project and branch.
+// A hash suffix makes names unique even if different project ids map to the same name.
+func gitopsProjectName(projectId, branch string) string {
+	key := projectId
+	if branch != "" {
+		key += "@" + branch
+	}
+	sum := sha256.Sum256([]byte(key))
+	name := strings.Trim(invalidObjectNameChars.ReplaceAllString(strings.ToLower(key), "-"), "-")
+	const maxPrefixLength = 63 - 1 - 8 // fit into a label value, just in case
+	if len(name) > maxPrefixLength {
+		name = strings.TrimRight(name[:maxPrefixLength], "-")
+	}
+	if name == "" {
+		name = "project"
+	}
+	return name + "-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,365 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/cache"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestGitopsStatusReporter(t *testing.T) {
+	deployment := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":       "app",
+				"namespace":  "ns",
+				"generation": int64(1),
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+			},
+			"status": map[string]interface{}{
+				"observedGeneration": int64(1),
+				"replicas":           int64(1),
+				"updatedReplicas":    int64(1),
+				"availableReplicas":  int64(1),
+			},
+		},
+	}
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme(), deployment)
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Group: "apps", Version: "v1"}, {Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	recorder := record.NewFakeRecorder(10)
+	r := newGitopsStatusReporter(zaptest.NewLogger(t), dynamicClient, restMapper, recorder, "agent-ns", "group/project", "")
+	r.setObjectsCache(newFakeLiveObjectsCache(deployment))
+	ctx := context.Background()
+	client := dynamicClient.Resource(gitopsProjectGVR).Namespace("agent-ns")
+	name := gitopsProjectName("group/project", "")
+
+	r.write(ctx)
+	obj, err := client.Get(ctx, name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, gitopsProjectKind, obj.GetKind())
+	assert.Equal(t, map[string]interface{}{
+		"projectId": "group/project",
+	}, obj.Object["spec"])
+	assert.Equal(t, map[string]interface{}{
+		"phase":          gitopsPhasePending,
+		"managedObjects": int64(0),
+	}, obj.Object["status"])
+
+	r.syncStarted(gitHash1, 2)
+	r.syncFinished(gitHash1, []common.ResourceSyncResult{
+		{
+			ResourceKey: kube.NewResourceKey("apps", "Deployment", "ns", "app"),
+			Status:      common.ResultCodeSynced,
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "old"),
+			Status:      common.ResultCodePruned,
+		},
+	}, nil)
+	r.write(ctx)
+	obj, err = client.Get(ctx, name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Normal Synced Synchronized commit "+gitHash1+": 1 applied, 1 pruned", <-recorder.Events)
+	status := obj.Object["status"].(map[string]interface{})
+	assert.Equal(t, gitopsPhaseSynced, status["phase"])
+	assert.Equal(t, gitHash1, status["commitId"])
+	assert.EqualValues(t, 1, status["managedObjects"])
+	assert.Equal(t, "Healthy", status["health"])
+	assert.Contains(t, status, "lastSyncTime")
+
+	r.syncFinished(gitHash1, []common.ResourceSyncResult{
+		{
+			ResourceKey: kube.NewResourceKey("apps", "Deployment", "ns", "app"),
+			Status:      common.ResultCodeSynced,
+		},
+		{
+			ResourceKey: kube.NewResourceKey("", "ConfigMap", "ns", "cm"),
+			Status:      common.ResultCodeSynced,
+			Message:     "configmap/cm unchanged",
+		},
+	}, nil)
+	assert.Equal(t, "Normal Synced Synchronized commit "+gitHash1+": 1 applied, 0 pruned", <-recorder.Events)
+	r.write(ctx)
+	obj, err = client.Get(ctx, name, metav1.GetOptions{})
+	require.NoError(t, err)
+	status = obj.Object["status"].(map[string]interface{})
+	assert.Equal(t, "Missing", status["health"])
+
+	r.syncFailed(gitHash2, errors.New("boom"))
+	assert.Equal(t, "Warning SyncFailed Failed to synchronize commit "+gitHash2+": boom", <-recorder.Events)
+	r.write(ctx)
+	obj, err = client.Get(ctx, name, metav1.GetOptions{})
+	require.NoError(t, err)
+	status = obj.Object["status"].(map[string]interface{})
+	assert.Equal(t, gitopsPhaseFailed, status["phase"])
+	assert.Equal(t, gitHash2, status["commitId"])
+	assert.Equal(t, "boom", status["message"])
+	assert.NotContains(t, status, "missingPermissions")
+
+	r.syncFailed(gitHash2, newMissingPermissionsError([]missingPermission{
+		{
+			Group:     "apps",
+			Resource:  "deployments",
+			Namespace: "ns",
+			Verb:      "patch",
+			Objects:   []string{"Deployment/ns/app"},
+		},
+	}))
+	<-recorder.Events
+	r.write(ctx)
+	obj, err = client.Get(ctx, name, metav1.GetOptions{})
+	require.NoError(t, err)
+	status = obj.Object["status"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"group":     "apps",
+			"resource":  "deployments",
+			"namespace": "ns",
+			"verb":      "patch",
+			"objects":   int64(1),
+		},
+	}, status["missingPermissions"])
+
+	r.cleanup(ctx)
+	_, err = client.Get(ctx, name, metav1.GetOptions{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestGitopsStatusReporterWritesOnlyChanges(t *testing.T) {
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	r := newGitopsStatusReporter(zaptest.NewLogger(t), dynamicClient, meta.NewDefaultRESTMapper(nil), nil, "agent-ns", "group/project", "")
+	ctx := context.Background()
+
+	r.write(ctx)
+	assert.Len(t, dynamicClient.Actions(), 2) // get, create
+	r.write(ctx)
+	assert.Len(t, dynamicClient.Actions(), 2)
+	r.syncStarted(gitHash1, 1)
+	r.write(ctx)
+	assert.Len(t, dynamicClient.Actions(), 4) // get, update
+}
+
+func TestGitopsStatusReporterWaitsForAPI(t *testing.T) {
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	d := &fakediscovery.FakeDiscovery{
+		Fake: &k8stesting.Fake{},
+	}
+	r := newGitopsStatusReporter(zaptest.NewLogger(t), dynamicClient, meta.NewDefaultRESTMapper(nil), nil, "agent-ns", "group/project", "")
+	r.discovery = d
+	ctx := context.Background()
+
+	r.write(ctx)
+	assert.Len(t, d.Actions(), 1)
+	assert.Empty(t, dynamicClient.Actions())
+	assert.Equal(t, gitopsStatusRefreshPeriod, r.apiCheckPeriod)
+	r.write(ctx) // before the next check
+	assert.Len(t, d.Actions(), 1)
+
+	r.nextAPICheck = time.Time{}
+	r.write(ctx)
+	assert.Len(t, d.Actions(), 2)
+	assert.Equal(t, 2*gitopsStatusRefreshPeriod, r.apiCheckPeriod)
+
+	d.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: gitopsProjectGVR.GroupVersion().String(),
+			APIResources: []metav1.APIResource{
+				{
+					Name: gitopsProjectGVR.Resource,
+				},
+			},
+		},
+	}
+	r.nextAPICheck = time.Time{}
+	r.write(ctx)
+	assert.Len(t, d.Actions(), 3)
+	_, err := dynamicClient.Resource(gitopsProjectGVR).Namespace("agent-ns").Get(ctx, gitopsProjectName("group/project", ""), metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestGitopsStatusReporterPublishesReadiness(t *testing.T) {
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	r := newGitopsStatusReporter(zaptest.NewLogger(t), dynamicClient, restMapper, nil, "agent-ns", "group/project", "")
+	r.projectStates = newProjectStates()
+	ctx := context.Background()
+	state := func() projectState {
+		r.projectStates.mu.Lock()
+		defer r.projectStates.mu.Unlock()
+		return r.projectStates.states["group/project"]
+	}
+
+	r.write(ctx)
+	assert.Equal(t, projectState{reason: gitopsPhasePending}, state())
+	r.syncFinished(gitHash1, nil, nil)
+	r.write(ctx)
+	assert.Equal(t, projectState{ready: true}, state())
+	r.waitingForDependencies(gitHash2, []string{"a (Progressing)", "b (not started)"})
+	r.write(ctx)
+	assert.Equal(t, projectState{reason: gitopsPhaseWaiting}, state())
+	obj, err := dynamicClient.Resource(gitopsProjectGVR).Namespace("agent-ns").Get(ctx, gitopsProjectName("group/project", ""), metav1.GetOptions{})
+	require.NoError(t, err)
+	status := obj.Object["status"].(map[string]interface{})
+	assert.Equal(t, gitopsPhaseWaiting, status["phase"])
+	assert.Equal(t, "waiting for dependencies: a (Progressing), b (not started)", status["message"])
+	r.removeProjectState()
+	assert.NotContains(t, r.projectStates.states, "group/project")
+}
+
+func TestGitopsStatusReporterCountsDriftedObjects(t *testing.T) {
+	live := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "changed",
+				"namespace": "ns",
+			},
+			"data": map[string]interface{}{
+				"key": "changed",
+			},
+		},
+	}
+	inSync := live.DeepCopy()
+	inSync.SetName("same")
+	desiredChanged := live.DeepCopy()
+	desiredChanged.Object["data"] = map[string]interface{}{
+		"key": "value",
+	}
+	desiredInSync := inSync.DeepCopy()
+	desiredInSync.SetNamespace("") // default namespace is used
+	desiredMissing := live.DeepCopy()
+	desiredMissing.SetName("missing")
+	dynamicClient := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	metrics := newGitopsMetrics()
+	r := newGitopsStatusReporter(zaptest.NewLogger(t), dynamicClient, restMapper, nil, "agent-ns", "group/project", "")
+	r.metrics = metrics.forProject("group/project", "", "")
+	r.setObjectsCache(newFakeLiveObjectsCache(live, inSync))
+	ctx := context.Background()
+
+	r.setDesiredObjects("ns", []*unstructured.Unstructured{desiredChanged, desiredInSync, desiredMissing})
+	r.syncFinished(gitHash1, []common.ResourceSyncResult{
+		{
+			ResourceKey: kube.GetResourceKey(live),
+			Status:      common.ResultCodeSynced,
+		},
+		{
+			ResourceKey: kube.GetResourceKey(inSync),
+			Status:      common.ResultCodeSynced,
+		},
+		{
+			ResourceKey: kube.GetResourceKey(desiredMissing),
+			Status:      common.ResultCodeSynced,
+		},
+	}, nil)
+	r.write(ctx)
+	assert.EqualValues(t, 2, testutil.ToFloat64(metrics.driftedObjects.With(r.metrics.labels)))
+}
+
+func TestGitopsStatusReporterObjectWithoutGCMark(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	r := newGitopsStatusReporter(zaptest.NewLogger(t), fake.NewSimpleDynamicClient(runtime.NewScheme()), restMapper, nil, "agent-ns", "group/project", "")
+	key := kube.NewResourceKey("", "ConfigMap", "ns", "cm")
+	r.setObjectsCache(fakeLiveObjectsCache{
+		key: {
+			Ref: v1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Namespace:  "ns",
+				Name:       "cm",
+			},
+		},
+	})
+	desired := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "cm",
+				"namespace": "ns",
+			},
+		},
+	}
+	healthStatus, drifted := r.checkObjects([]kube.ResourceKey{key}, map[kube.ResourceKey]*unstructured.Unstructured{
+		key: desired,
+	})
+	assert.EqualValues(t, "Unknown", healthStatus)
+	assert.Equal(t, 1, drifted)
+}
+
+func TestGitopsStatusReporterNil(t *testing.T) {
+	var r *gitopsStatusReporter
+	r.syncStarted(gitHash1, 1)
+	r.syncFinished(gitHash1, nil, nil)
+	r.syncFailed(gitHash1, errors.New("boom"))
+	r.run(context.Background())
+}
+
+func TestGitopsProjectName(t *testing.T) {
+	assert.Regexp(t, "^group-project-[0-9a-f]{8}$", gitopsProjectName("Group/Project", ""))
+	assert.Regexp(t, "^group-project-main-[0-9a-f]{8}$", gitopsProjectName("group/project", "main"))
+	assert.NotEqual(t, gitopsProjectName("group/project", ""), gitopsProjectName("group-project", ""))
+	assert.Regexp(t, "^project-[0-9a-f]{8}$", gitopsProjectName("///", ""))
+	long := gitopsProjectName("group/very-long-project-name-that-does-not-fit-into-sixty-three-characters", "")
+	assert.Len(t, long, 63)
+}
+
+// fakeLiveObjectsCache is a liveObjectsCache with objects that have their manifests cached.
+type fakeLiveObjectsCache map[kube.ResourceKey]*cache.Resource
+
+func newFakeLiveObjectsCache(objs ...*unstructured.Unstructured) fakeLiveObjectsCache {
+	c := make(fakeLiveObjectsCache, len(objs))
+	for _, obj := range objs {
+		c[kube.GetResourceKey(obj)] = &cache.Resource{
+			ResourceVersion: obj.GetResourceVersion(),
+			Ref:             kube.GetObjectRef(obj),
+			Resource:        obj,
+		}
+	}
+	return c
+}
+
+func (c fakeLiveObjectsCache) FindResources(namespace string, predicates ...func(r *cache.Resource) bool) map[kube.ResourceKey]*cache.Resource {
+	result := make(map[kube.ResourceKey]*cache.Resource)
+outer:
+	for key, res := range c {
+		if namespace != "" && key.Namespace != namespace {
+			continue
+		}
+		for _, p := range predicates {
+			if !p(res) {
+				continue outer
+			}
+		}
+		result[key] = res
+	}
+	return result
+}
@@ -2,12 +2,16 @@ package agent
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/cache"
 	"github.com/argoproj/gitops-engine/pkg/engine"
 	"github.com/argoproj/gitops-engine/pkg/sync"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/go-logr/zapr"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/errz"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -15,6 +19,8 @@ import (
 type syncJob struct {
 	ctx      context.Context
 	commitId string
+	// received is when the commit has been received.
+	received time.Time
 	objects  []*unstructured.Unstructured
 }
 
@@ -32,7 +38,37 @@ func newSyncWorker(config synchronizerConfig, engine engine.GitOpsEngine) *syncW
 
 func (s *syncWorker) run(jobs <-chan syncJob) {
 	for job := range jobs {
-		err := s.synchronize(job)
+		if s.projectStates != nil {
+			err := s.projectStates.waitFor(job.ctx, s.project.DependsOn, s.project.DependsOnTimeout.AsDuration(), func(notReady []string) {
+				s.log.Info("Waiting for dependencies to be synchronized and healthy", logz.CommitId(job.commitId), zap.Strings("depends_on", notReady))
+				s.statusReporter.waitingForDependencies(job.commitId, notReady)
+			})
+			if err != nil {
+				if errz.ContextDone(err) {
+					s.log.Info("Synchronization was canceled", zap.Error(err))
+				} else {
+					s.log.Warn("Synchronization skipped", zap.Error(err), logz.CommitId(job.commitId))
+					s.statusReporter.syncFailed(job.commitId, err)
+				}
+				continue
+			}
+		}
+		if !s.checkPermissions(job) {
+			continue
+		}
+		s.statusReporter.syncStarted(job.commitId, len(job.objects))
+		started := time.Now()
+		result, err := s.synchronize(job)
+		if err == nil {
+			s.statusReporter.setDesiredObjects(s.project.DefaultNamespace, job.objects)
+		}
+		if !errz.ContextDone(err) {
+			s.statusReporter.syncFinished(job.commitId, result, err)
+			s.metrics.syncFinished(job.commitId, job.received, started, result, err)
+		}
+		if err == nil {
+			s.objectEventRecorder.syncFinished(job.commitId, result)
+		}
 		if err != nil {
 			if errz.ContextDone(err) {
 				s.log.Info("Synchronization was canceled", zap.Error(err))
@@ -43,7 +79,35 @@ func (s *syncWorker) run(jobs <-chan syncJob) {
 	}
 }
 
-func (s *syncWorker) synchronize(job syncJob) error {
+// checkPermissions returns false if the job should not be synchronized.
+func (s *syncWorker) checkPermissions(job syncJob) bool {
+	if s.permissionChecker == nil {
+		return true
+	}
+	restMapper, err := s.k8sClientGetter.ToRESTMapper()
+	if err != nil {
+		s.log.Warn("Failed to check permissions, synchronizing anyway", zap.Error(err))
+		return true
+	}
+	err = s.permissionChecker.check(job.ctx, restMapper, job.commitId, s.project.DefaultNamespace, job.objects)
+	switch {
+	case err == nil:
+		return true
+	case errz.ContextDone(err):
+		s.log.Info("Synchronization was canceled", zap.Error(err))
+		return false
+	case errors.As(err, new(*missingPermissionsError)):
+		s.log.Warn("Synchronization skipped", zap.Error(err), logz.CommitId(job.commitId))
+		s.statusReporter.syncFailed(job.commitId, err)
+		return false
+	default:
+		// Permission checks are best effort, don't block synchronization.
+		s.log.Warn("Failed to check permissions, synchronizing anyway", zap.Error(err))
+		return true
+	}
+}
+
+func (s *syncWorker) synchronize(job syncJob) ([]common.ResourceSyncResult, error) {
 	result, err := s.engine.Sync(
 		job.ctx,
 		job.objects,
@@ -53,14 +117,19 @@ func (s *syncWorker) synchronize(job syncJob) error {
 		sync.WithLogr(zapr.NewLogger(s.log)),
 	)
 	if err != nil {
-		return err // don't wrap
+		return nil, err // don't wrap
 	}
 	for _, res := range result {
 		s.log.Info("Synced", engineResourceKey(res.ResourceKey), engineSyncResult(res.Message))
 	}
-	return nil
+	return result, nil
 }
 
 func (s *syncWorker) isManaged(r *cache.Resource) bool {
-	return r.Info.(*resourceInfo).gcMark == "managed" // TODO
+	return isManagedResource(r)
+}
+
+func isManagedResource(r *cache.Resource) bool {
+	info, ok := r.Info.(*resourceInfo)
+	return ok && info.gcMark == "managed" // TODO
 }
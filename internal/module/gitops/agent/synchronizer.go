@@ -3,12 +3,16 @@ package agent
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/engine"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/resource"
@@ -23,6 +27,20 @@ type synchronizerConfig struct {
 	log             *zap.Logger
 	project         *agentcfg.ManifestProjectCF
 	k8sClientGetter resource.RESTClientGetter
+	// allowedNamespaces restricts objects to these namespaces. Empty means no restriction.
+	allowedNamespaces []string
+	// variableSubstitutor is nil if variables are not configured.
+	variableSubstitutor *variableSubstitutor
+	// statusReporter is nil if status reporting is disabled.
+	statusReporter *gitopsStatusReporter
+	// objectEventRecorder is nil if Events are disabled.
+	objectEventRecorder *objectEventRecorder
+	// projectStates is used to wait for projects from depends_on. nil if the project has no dependencies.
+	projectStates *projectStates
+	// permissionChecker is nil if permissions are not checked before synchronization.
+	permissionChecker *permissionChecker
+	// metrics is nil if metrics are not recorded.
+	metrics *projectMetrics
 }
 
 type resourceInfo struct {
@@ -78,17 +96,38 @@ func (s *synchronizer) run(ctx context.Context) {
 		case <-ctx.Done():
 			return // nolint: govet
 		case state := <-s.desiredState:
-			objs, err := s.decodeObjectsToSynchronize(state.Sources)
+			received := time.Now()
+			sources := state.Sources
+			if s.variableSubstitutor != nil {
+				var err error
+				sources, err = s.variableSubstitutor.substitute(ctx, state)
+				if err != nil {
+					s.log.Warn("Failed to substitute variables in GitOps objects", zap.Error(err), logz.CommitId(state.CommitId))
+					s.statusReporter.syncFailed(state.CommitId, err)
+					continue
+				}
+			}
+			objs, err := s.decodeObjectsToSynchronize(sources)
 			if err != nil {
 				s.log.Warn("Failed to decode GitOps objects", zap.Error(err), logz.CommitId(state.CommitId))
+				s.statusReporter.syncFailed(state.CommitId, err)
 				continue
 			}
+			if len(s.allowedNamespaces) > 0 {
+				err = s.checkObjectsNamespace(objs)
+				if err != nil {
+					s.log.Warn("GitOps objects are outside of the allowed namespaces", zap.Error(err), logz.CommitId(state.CommitId))
+					s.statusReporter.syncFailed(state.CommitId, err)
+					continue
+				}
+			}
 			if jobCancel != nil {
 				jobCancel() // Cancel running/pending job ASAP
 			}
 			markAsManaged(objs)
 			newJob = syncJob{
 				commitId: state.CommitId,
+				received: received,
 				objects:  objs,
 			}
 			newJob.ctx, jobCancel = context.WithCancel(context.Background()) // nolint: govet
@@ -130,6 +169,41 @@ func (s *synchronizer) decodeObjectsToSynchronize(sources []rpc.ObjectSource) ([
 	return res, nil
 }
 
+func (s *synchronizer) checkObjectsNamespace(objs []*unstructured.Unstructured) error {
+	restMapper, err := s.k8sClientGetter.ToRESTMapper()
+	if err != nil {
+		return fmt.Errorf("ToRESTMapper: %v", err)
+	}
+	return checkObjectsNamespace(restMapper, s.allowedNamespaces, s.project.DefaultNamespace, objs)
+}
+
+// checkObjectsNamespace returns an error if any of the objects is cluster-scoped or is not in one of the allowed
+// namespaces. Objects without a namespace are put into the default namespace.
+func checkObjectsNamespace(restMapper meta.RESTMapper, allowedNamespaces []string, defaultNamespace string, objs []*unstructured.Unstructured) error {
+	allowed := "namespace " + allowedNamespaces[0]
+	if len(allowedNamespaces) > 1 {
+		allowed = "namespaces " + strings.Join(allowedNamespaces, ", ")
+	}
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", gvk.Kind, obj.GetName(), err)
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			return fmt.Errorf("%s %s: cluster-scoped objects are not allowed, only %s can be used", gvk.Kind, obj.GetName(), allowed)
+		}
+		objNamespace := obj.GetNamespace()
+		if objNamespace == "" {
+			objNamespace = defaultNamespace
+		}
+		if !isStringInSlice(objNamespace, allowedNamespaces) {
+			return fmt.Errorf("%s %s: namespace %s is not allowed, only %s can be used", gvk.Kind, obj.GetName(), objNamespace, allowed)
+		}
+	}
+	return nil
+}
+
 func markAsManaged(objs []*unstructured.Unstructured) {
 	for _, obj := range objs {
 		annotations := obj.GetAnnotations()
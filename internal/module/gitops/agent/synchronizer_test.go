@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCheckObjectsNamespace(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	obj := func(kind, namespace string) *unstructured.Unstructured {
+		o := &unstructured.Unstructured{}
+		o.SetAPIVersion("v1")
+		o.SetKind(kind)
+		o.SetNamespace(namespace)
+		o.SetName("x")
+		return o
+	}
+	tests := []struct {
+		name        string
+		namespaces  []string
+		obj         *unstructured.Unstructured
+		expectedErr string
+	}{
+		{
+			name: "same namespace",
+			obj:  obj("ConfigMap", "ns1"),
+		},
+		{
+			name: "no namespace",
+			obj:  obj("ConfigMap", ""),
+		},
+		{
+			name:        "other namespace",
+			obj:         obj("ConfigMap", "ns2"),
+			expectedErr: "ConfigMap x: namespace ns2 is not allowed, only namespace ns1 can be used",
+		},
+		{
+			name:        "cluster-scoped",
+			obj:         obj("Namespace", ""),
+			expectedErr: "Namespace x: cluster-scoped objects are not allowed, only namespace ns1 can be used",
+		},
+		{
+			name:       "one of namespaces",
+			namespaces: []string{"ns1", "ns2"},
+			obj:        obj("ConfigMap", "ns2"),
+		},
+		{
+			name:        "not one of namespaces",
+			namespaces:  []string{"ns1", "ns2"},
+			obj:         obj("ConfigMap", "ns3"),
+			expectedErr: "ConfigMap x: namespace ns3 is not allowed, only namespaces ns1, ns2 can be used",
+		},
+		{
+			name:        "default namespace not allowed",
+			namespaces:  []string{"ns2"},
+			obj:         obj("ConfigMap", ""),
+			expectedErr: "ConfigMap x: namespace ns1 is not allowed, only namespace ns2 can be used",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			namespaces := tc.namespaces // nolint: scopelint
+			if namespaces == nil {
+				namespaces = []string{"ns1"}
+			}
+			err := checkObjectsNamespace(restMapper, namespaces, "ns1", []*unstructured.Unstructured{tc.obj}) // nolint: scopelint
+			if tc.expectedErr == "" {                                                                         // nolint: scopelint
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+			}
+		})
+	}
+}
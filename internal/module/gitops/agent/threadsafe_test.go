@@ -9,6 +9,7 @@ import (
 	enginesync "github.com/argoproj/gitops-engine/pkg/sync"
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
 )
 
 var (
@@ -23,12 +24,12 @@ type threadSafeGitopsEngineFactory struct {
 	EngineFactory GitopsEngineFactory
 }
 
-func (f *threadSafeGitopsEngineFactory) New(engineOpts []engine.Option, cacheOpts []cache.UpdateSettingsFunc) engine.GitOpsEngine {
+func (f *threadSafeGitopsEngineFactory) New(kubeClientConfig *rest.Config, engineOpts []engine.Option, clusterCache cache.ClusterCache) engine.GitOpsEngine {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 	return &threadSafeGitopsEngine{
 		mutex:    &f.mutex,
-		delegate: f.EngineFactory.New(engineOpts, cacheOpts),
+		delegate: f.EngineFactory.New(kubeClientConfig, engineOpts, clusterCache),
 	}
 }
 
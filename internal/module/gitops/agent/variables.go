@@ -0,0 +1,145 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	variableAgentVersion      = "AGENT_VERSION"
+	variableAgentPodNamespace = "AGENT_POD_NAMESPACE"
+	variableAgentPodName      = "AGENT_POD_NAME"
+	variableGitopsProjectId   = "GITOPS_PROJECT_ID"
+	variableGitopsCommitId    = "GITOPS_COMMIT_ID"
+)
+
+var (
+	variableNameRegex        = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	reservedVariablePrefixes = []string{"AGENT_", "GITOPS_"}
+)
+
+// variableSubstitutor replaces ${NAME} references in manifests with values of variables.
+type variableSubstitutor struct {
+	project    *agentcfg.ManifestProjectCF
+	agentMeta  *modshared.AgentMeta
+	kubeClient kubernetes.Interface
+	// configMapNamespace is the namespace to read the variables ConfigMap from.
+	configMapNamespace string
+}
+
+// substitute returns sources with variables substituted.
+func (v *variableSubstitutor) substitute(ctx context.Context, state rpc.ObjectsToSynchronizeData) ([]rpc.ObjectSource, error) {
+	vars, err := v.variables(ctx, state.CommitId)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]rpc.ObjectSource, 0, len(state.Sources))
+	for _, source := range state.Sources {
+		data, err := substituteVariables(source.Data, vars)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", source.Name, err)
+		}
+		result = append(result, rpc.ObjectSource{
+			Name: source.Name,
+			Data: data,
+		})
+	}
+	return result, nil
+}
+
+func (v *variableSubstitutor) variables(ctx context.Context, commitId string) (map[string]string, error) {
+	vars := make(map[string]string)
+	if v.project.Variables.ConfigMap != "" {
+		cm, err := v.kubeClient.CoreV1().ConfigMaps(v.configMapNamespace).Get(ctx, v.project.Variables.ConfigMap, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("variables ConfigMap: %v", err)
+		}
+		for name, value := range cm.Data {
+			if err := validateVariableName(name); err != nil {
+				return nil, fmt.Errorf("variables ConfigMap %s: %v", v.project.Variables.ConfigMap, err)
+			}
+			vars[name] = value
+		}
+	}
+	for name, value := range v.project.Variables.Values {
+		vars[name] = value
+	}
+	vars[variableAgentVersion] = v.agentMeta.Version
+	vars[variableAgentPodNamespace] = v.agentMeta.PodNamespace
+	vars[variableAgentPodName] = v.agentMeta.PodName
+	vars[variableGitopsProjectId] = v.project.Id
+	vars[variableGitopsCommitId] = commitId
+	return vars, nil
+}
+
+// substituteVariables replaces ${NAME} references with values of variables.
+// $${ is an escape sequence for a literal ${. References that are not valid variable names are left as is.
+// An error is returned if a referenced variable is not defined.
+func substituteVariables(data []byte, vars map[string]string) ([]byte, error) {
+	if !bytes.Contains(data, []byte("${")) {
+		return data, nil
+	}
+	var (
+		result     bytes.Buffer
+		unresolved []string
+	)
+	result.Grow(len(data))
+	for {
+		idx := bytes.Index(data, []byte("${"))
+		if idx == -1 {
+			result.Write(data)
+			break
+		}
+		if idx > 0 && data[idx-1] == '$' {
+			// Escaped $${
+			result.Write(data[:idx-1])
+			result.WriteString("${")
+			data = data[idx+2:]
+			continue
+		}
+		result.Write(data[:idx])
+		end := bytes.IndexByte(data[idx+2:], '}')
+		if end == -1 {
+			result.Write(data[idx:])
+			break
+		}
+		name := string(data[idx+2 : idx+2+end])
+		if !variableNameRegex.MatchString(name) {
+			result.WriteString("${")
+			data = data[idx+2:]
+			continue
+		}
+		value, ok := vars[name]
+		if !ok {
+			unresolved = append(unresolved, name)
+		}
+		result.WriteString(value)
+		data = data[idx+2+end+1:]
+	}
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("unresolved variables: %s", strings.Join(sets.NewString(unresolved...).List(), ", "))
+	}
+	return result.Bytes(), nil
+}
+
+func validateVariableName(name string) error {
+	if !variableNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q", name)
+	}
+	for _, prefix := range reservedVariablePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("variable name %q uses reserved prefix %s", name, prefix)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSubstituteVariables(t *testing.T) {
+	vars := map[string]string{
+		"CLUSTER": "prod-1",
+		"REGION":  "eu",
+	}
+	tests := []struct {
+		name        string
+		data        string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "no references",
+			data:     "a: $b",
+			expected: "a: $b",
+		},
+		{
+			name:     "references",
+			data:     "name: ${CLUSTER}-${REGION}\nregion: ${REGION}",
+			expected: "name: prod-1-eu\nregion: eu",
+		},
+		{
+			name:     "escaped",
+			data:     "cmd: echo $${HOME} ${CLUSTER}",
+			expected: "cmd: echo ${HOME} prod-1",
+		},
+		{
+			name:     "not a variable name",
+			data:     "a: ${a.b} ${ c",
+			expected: "a: ${a.b} ${ c",
+		},
+		{
+			name:        "unresolved",
+			data:        "a: ${ZONE} ${HOME} ${ZONE} ${CLUSTER}",
+			expectedErr: "unresolved variables: HOME, ZONE",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := substituteVariables([]byte(tc.data), vars) // nolint: scopelint
+			if tc.expectedErr != "" {                                 // nolint: scopelint
+				assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(actual)) // nolint: scopelint
+		})
+	}
+}
+
+func TestVariableSubstitutor(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-vars",
+			Namespace: "agent-ns",
+		},
+		Data: map[string]string{
+			"CLUSTER": "from-config-map",
+			"DOMAIN":  "example.com",
+		},
+	})
+	v := &variableSubstitutor{
+		project: &agentcfg.ManifestProjectCF{
+			Id: "group/project",
+			Variables: &agentcfg.VariablesCF{
+				Values: map[string]string{
+					"CLUSTER": "prod-1",
+				},
+				ConfigMap: "cluster-vars",
+			},
+		},
+		agentMeta: &modshared.AgentMeta{
+			Version:      "v1.0.0",
+			PodNamespace: "agent-ns",
+			PodName:      "agentk-1",
+		},
+		kubeClient:         kubeClient,
+		configMapNamespace: "agent-ns",
+	}
+	sources, err := v.substitute(context.Background(), rpc.ObjectsToSynchronizeData{
+		CommitId: gitHash1,
+		Sources: []rpc.ObjectSource{
+			{
+				Name: "a.yaml",
+				Data: []byte("${CLUSTER} ${DOMAIN} ${AGENT_VERSION} ${AGENT_POD_NAMESPACE} ${AGENT_POD_NAME} ${GITOPS_PROJECT_ID} ${GITOPS_COMMIT_ID}"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []rpc.ObjectSource{
+		{
+			Name: "a.yaml",
+			Data: []byte("prod-1 example.com v1.0.0 agent-ns agentk-1 group/project " + gitHash1),
+		},
+	}, sources)
+
+	_, err = v.substitute(context.Background(), rpc.ObjectsToSynchronizeData{
+		CommitId: gitHash1,
+		Sources: []rpc.ObjectSource{
+			{
+				Name: "b.yaml",
+				Data: []byte("${ZONE}"),
+			},
+		},
+	})
+	assert.EqualError(t, err, "b.yaml: unresolved variables: ZONE")
+}
@@ -0,0 +1,52 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/grpctool"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/retry"
+	"go.uber.org/zap"
+)
+
+type BranchesCallback func(ctx context.Context, branches []string)
+
+// BranchesWatcherInterface abstracts BranchesWatcher.
+type BranchesWatcherInterface interface {
+	Watch(context.Context, *BranchesRequest, BranchesCallback)
+}
+
+type BranchesWatcher struct {
+	Log          *zap.Logger
+	GitopsClient GitopsClient
+	RetryPeriod  time.Duration
+}
+
+func (w *BranchesWatcher) Watch(ctx context.Context, req *BranchesRequest, callback BranchesCallback) {
+	retry.JitterUntil(ctx, w.RetryPeriod, func(ctx context.Context) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel() // ensure streaming call is canceled
+		res, err := w.GitopsClient.GetBranches(ctx, req)
+		if err != nil {
+			if !grpctool.RequestCanceled(err) {
+				w.Log.Error("GetBranches failed", zap.Error(err))
+			}
+			return
+		}
+		for {
+			resp, err := res.Recv()
+			if err != nil {
+				switch {
+				case errors.Is(err, io.EOF):
+				case grpctool.RequestCanceled(err):
+				default:
+					w.Log.Error("GetBranches.Recv failed", zap.Error(err))
+				}
+				return
+			}
+			callback(ctx, resp.Branches)
+		}
+	})
+}
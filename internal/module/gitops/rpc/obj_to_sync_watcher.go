@@ -23,7 +23,10 @@ type ObjectSource struct {
 
 type ObjectsToSynchronizeData struct {
 	CommitId string
-	Sources  []ObjectSource
+	// Tag is the name of the tag that was selected using the semantic version constraint.
+	// Empty if no constraint was specified.
+	Tag     string
+	Sources []ObjectSource
 }
 
 type ObjectsToSynchronizeCallback func(context.Context, ObjectsToSynchronizeData)
@@ -80,6 +83,7 @@ type objectsToSynchronizeVisitor struct {
 
 func (v *objectsToSynchronizeVisitor) OnHeaders(headers *ObjectsToSynchronizeResponse_Headers) error {
 	v.objs.CommitId = headers.CommitId
+	v.objs.Tag = headers.Tag
 	return nil
 }
 
@@ -38,9 +38,11 @@ type ObjectsToSynchronizeRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProjectId string             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
-	CommitId  string             `protobuf:"bytes,2,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
-	Paths     []*agentcfg.PathCF `protobuf:"bytes,3,rep,name=paths,proto3" json:"paths,omitempty"`
+	ProjectId           string             `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	CommitId            string             `protobuf:"bytes,2,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
+	Paths               []*agentcfg.PathCF `protobuf:"bytes,3,rep,name=paths,proto3" json:"paths,omitempty"`
+	SemverTagConstraint string             `protobuf:"bytes,4,opt,name=semver_tag_constraint,json=semverTagConstraint,proto3" json:"semver_tag_constraint,omitempty"`
+	Branch              string             `protobuf:"bytes,5,opt,name=branch,proto3" json:"branch,omitempty"`
 }
 
 func (x *ObjectsToSynchronizeRequest) Reset() {
@@ -96,6 +98,20 @@ func (x *ObjectsToSynchronizeRequest) GetPaths() []*agentcfg.PathCF {
 	return nil
 }
 
+func (x *ObjectsToSynchronizeRequest) GetSemverTagConstraint() string {
+	if x != nil {
+		return x.SemverTagConstraint
+	}
+	return ""
+}
+
+func (x *ObjectsToSynchronizeRequest) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
 type ObjectsToSynchronizeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -190,18 +206,121 @@ func (*ObjectsToSynchronizeResponse_Object_) isObjectsToSynchronizeResponse_Mess
 
 func (*ObjectsToSynchronizeResponse_Trailers_) isObjectsToSynchronizeResponse_Message() {}
 
+type BranchesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProjectId  string `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	BranchGlob string `protobuf:"bytes,2,opt,name=branch_glob,json=branchGlob,proto3" json:"branch_glob,omitempty"`
+}
+
+func (x *BranchesRequest) Reset() {
+	*x = BranchesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BranchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BranchesRequest) ProtoMessage() {}
+
+func (x *BranchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BranchesRequest.ProtoReflect.Descriptor instead.
+func (*BranchesRequest) Descriptor() ([]byte, []int) {
+	return file_internal_module_gitops_rpc_rpc_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BranchesRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *BranchesRequest) GetBranchGlob() string {
+	if x != nil {
+		return x.BranchGlob
+	}
+	return ""
+}
+
+type BranchesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Branches []string `protobuf:"bytes,1,rep,name=branches,proto3" json:"branches,omitempty"`
+}
+
+func (x *BranchesResponse) Reset() {
+	*x = BranchesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BranchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BranchesResponse) ProtoMessage() {}
+
+func (x *BranchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BranchesResponse.ProtoReflect.Descriptor instead.
+func (*BranchesResponse) Descriptor() ([]byte, []int) {
+	return file_internal_module_gitops_rpc_rpc_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BranchesResponse) GetBranches() []string {
+	if x != nil {
+		return x.Branches
+	}
+	return nil
+}
+
 type ObjectsToSynchronizeResponse_Headers struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	CommitId string `protobuf:"bytes,1,opt,name=commit_id,json=commitId,proto3" json:"commit_id,omitempty"`
+	Tag      string `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
 }
 
 func (x *ObjectsToSynchronizeResponse_Headers) Reset() {
 	*x = ObjectsToSynchronizeResponse_Headers{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[2]
+		mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -214,7 +333,7 @@ func (x *ObjectsToSynchronizeResponse_Headers) String() string {
 func (*ObjectsToSynchronizeResponse_Headers) ProtoMessage() {}
 
 func (x *ObjectsToSynchronizeResponse_Headers) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[2]
+	mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -237,6 +356,13 @@ func (x *ObjectsToSynchronizeResponse_Headers) GetCommitId() string {
 	return ""
 }
 
+func (x *ObjectsToSynchronizeResponse_Headers) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
 type ObjectsToSynchronizeResponse_Object struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -249,7 +375,7 @@ type ObjectsToSynchronizeResponse_Object struct {
 func (x *ObjectsToSynchronizeResponse_Object) Reset() {
 	*x = ObjectsToSynchronizeResponse_Object{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[3]
+		mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -262,7 +388,7 @@ func (x *ObjectsToSynchronizeResponse_Object) String() string {
 func (*ObjectsToSynchronizeResponse_Object) ProtoMessage() {}
 
 func (x *ObjectsToSynchronizeResponse_Object) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[3]
+	mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -301,7 +427,7 @@ type ObjectsToSynchronizeResponse_Trailers struct {
 func (x *ObjectsToSynchronizeResponse_Trailers) Reset() {
 	*x = ObjectsToSynchronizeResponse_Trailers{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[4]
+		mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -314,7 +440,7 @@ func (x *ObjectsToSynchronizeResponse_Trailers) String() string {
 func (*ObjectsToSynchronizeResponse_Trailers) ProtoMessage() {}
 
 func (x *ObjectsToSynchronizeResponse_Trailers) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[4]
+	mi := &file_internal_module_gitops_rpc_rpc_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -343,7 +469,7 @@ var file_internal_module_gitops_rpc_rpc_proto_rawDesc = []byte{
 	0x74, 0x6f, 0x6f, 0x6c, 0x2f, 0x61, 0x75, 0x74, 0x6f, 0x6d, 0x61, 0x74, 0x61, 0x2f, 0x61, 0x75,
 	0x74, 0x6f, 0x6d, 0x61, 0x74, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x76, 0x61,
 	0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa1, 0x01, 0x0a, 0x1b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xed, 0x01, 0x0a, 0x1b, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
 	0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
 	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02,
@@ -353,53 +479,74 @@ var file_internal_module_gitops_rpc_rpc_proto_rawDesc = []byte{
 	0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x69, 0x74, 0x6c,
 	0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66,
 	0x67, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x43, 0x46, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02,
-	0x08, 0x01, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x22, 0xe2, 0x03, 0x0a, 0x1c, 0x4f, 0x62,
-	0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69,
-	0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x07, 0x68, 0x65,
-	0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x67, 0x69,
-	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f, 0x70,
-	0x73, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53,
-	0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x42, 0x08, 0x80, 0xf6, 0x2c, 0x02,
-	0x80, 0xf6, 0x2c, 0x03, 0x48, 0x00, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12,
-	0x60, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x3c, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67,
-	0x69, 0x74, 0x6f, 0x70, 0x73, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x08, 0x01, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x73, 0x65, 0x6d,
+	0x76, 0x65, 0x72, 0x5f, 0x74, 0x61, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x73, 0x65, 0x6d, 0x76, 0x65, 0x72,
+	0x54, 0x61, 0x67, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62,
+	0x72, 0x61, 0x6e, 0x63, 0x68, 0x22, 0xf2, 0x03, 0x0a, 0x1c, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
 	0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x08, 0x80,
-	0xf6, 0x2c, 0x02, 0x80, 0xf6, 0x2c, 0x03, 0x48, 0x00, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63,
-	0x74, 0x12, 0x6b, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65,
-	0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x62,
-	0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69,
-	0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x69, 0x6c,
-	0x65, 0x72, 0x73, 0x42, 0x0d, 0x80, 0xf6, 0x2c, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
-	0xff, 0x01, 0x48, 0x00, 0x52, 0x08, 0x74, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x1a, 0x2f,
-	0x0a, 0x07, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x24, 0x0a, 0x09, 0x63, 0x6f, 0x6d,
-	0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42,
-	0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x1a,
-	0x3d, 0x0a, 0x06, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x1f, 0x0a, 0x06, 0x73, 0x6f, 0x75,
-	0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02,
-	0x10, 0x01, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x0a,
-	0x0a, 0x08, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x42, 0x12, 0x0a, 0x07, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x07, 0x88, 0xf6, 0x2c, 0x01, 0xf8, 0x42, 0x01, 0x32, 0x95,
-	0x01, 0x0a, 0x06, 0x47, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x12, 0x8a, 0x01, 0x0a, 0x17, 0x47, 0x65,
-	0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72,
-	0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x12, 0x34, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x2e, 0x72, 0x70, 0x63, 0x2e,
-	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f,
-	0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x35, 0x2e, 0x67, 0x69,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68,
+	0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x42, 0x06, 0x82, 0xf6, 0x2c, 0x02, 0x02, 0x03, 0x48, 0x00,
+	0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x5e, 0x0a, 0x06, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x67, 0x69, 0x74, 0x6c,
+	0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e,
+	0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x06, 0x82, 0xf6, 0x2c, 0x02, 0x02, 0x03, 0x48,
+	0x00, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x6c, 0x0a, 0x08, 0x74, 0x72, 0x61,
+	0x69, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3e, 0x2e, 0x67, 0x69,
 	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f, 0x70,
 	0x73, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53,
 	0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42, 0x53, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d, 0x6f, 0x72, 0x67, 0x2f,
-	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d, 0x61, 0x67, 0x65, 0x6e, 0x74,
-	0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65,
-	0x2f, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x73, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x42, 0x0e, 0x82, 0xf6, 0x2c,
+	0x0a, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01, 0x48, 0x00, 0x52, 0x08, 0x74,
+	0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x1a, 0x41, 0x0a, 0x07, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x12, 0x24, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08,
+	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x1a, 0x3d, 0x0a, 0x06, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x12, 0x1f, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x06, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x0a, 0x0a, 0x08, 0x54, 0x72, 0x61,
+	0x69, 0x6c, 0x65, 0x72, 0x73, 0x42, 0x13, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x08, 0xf8, 0x42, 0x01, 0x8a, 0xf6, 0x2c, 0x01, 0x01, 0x22, 0x63, 0x0a, 0x0f, 0x42, 0x72,
+	0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a,
+	0x0a, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x0b, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x5f,
+	0x67, 0x6c, 0x6f, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72,
+	0x02, 0x10, 0x01, 0x52, 0x0a, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x47, 0x6c, 0x6f, 0x62, 0x22,
+	0x2e, 0x0a, 0x10, 0x42, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x32,
+	0xfd, 0x01, 0x0a, 0x06, 0x47, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x12, 0x8a, 0x01, 0x0a, 0x17, 0x47,
+	0x65, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68,
+	0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x12, 0x34, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x79, 0x6e, 0x63, 0x68, 0x72,
+	0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x35, 0x2e, 0x67,
+	0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f,
+	0x70, 0x73, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x54, 0x6f,
+	0x53, 0x79, 0x6e, 0x63, 0x68, 0x72, 0x6f, 0x6e, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x66, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x42, 0x72,
+	0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x12, 0x28, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x42, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x29, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x72, 0x61, 0x6e, 0x63,
+	0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42,
+	0x53, 0x5a, 0x51, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2d, 0x6f, 0x72, 0x67, 0x2f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
+	0x2d, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x69, 0x74,
+	0x6c, 0x61, 0x62, 0x2d, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x2f, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x2f, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73,
+	0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -414,24 +561,28 @@ func file_internal_module_gitops_rpc_rpc_proto_rawDescGZIP() []byte {
 	return file_internal_module_gitops_rpc_rpc_proto_rawDescData
 }
 
-var file_internal_module_gitops_rpc_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_internal_module_gitops_rpc_rpc_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_internal_module_gitops_rpc_rpc_proto_goTypes = []interface{}{
 	(*ObjectsToSynchronizeRequest)(nil),           // 0: gitlab.agent.gitops.rpc.ObjectsToSynchronizeRequest
 	(*ObjectsToSynchronizeResponse)(nil),          // 1: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse
-	(*ObjectsToSynchronizeResponse_Headers)(nil),  // 2: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Headers
-	(*ObjectsToSynchronizeResponse_Object)(nil),   // 3: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Object
-	(*ObjectsToSynchronizeResponse_Trailers)(nil), // 4: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Trailers
-	(*agentcfg.PathCF)(nil),                       // 5: gitlab.agent.agentcfg.PathCF
+	(*BranchesRequest)(nil),                       // 2: gitlab.agent.gitops.rpc.BranchesRequest
+	(*BranchesResponse)(nil),                      // 3: gitlab.agent.gitops.rpc.BranchesResponse
+	(*ObjectsToSynchronizeResponse_Headers)(nil),  // 4: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Headers
+	(*ObjectsToSynchronizeResponse_Object)(nil),   // 5: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Object
+	(*ObjectsToSynchronizeResponse_Trailers)(nil), // 6: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Trailers
+	(*agentcfg.PathCF)(nil),                       // 7: gitlab.agent.agentcfg.PathCF
 }
 var file_internal_module_gitops_rpc_rpc_proto_depIdxs = []int32{
-	5, // 0: gitlab.agent.gitops.rpc.ObjectsToSynchronizeRequest.paths:type_name -> gitlab.agent.agentcfg.PathCF
-	2, // 1: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.headers:type_name -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Headers
-	3, // 2: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.object:type_name -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Object
-	4, // 3: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.trailers:type_name -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Trailers
+	7, // 0: gitlab.agent.gitops.rpc.ObjectsToSynchronizeRequest.paths:type_name -> gitlab.agent.agentcfg.PathCF
+	4, // 1: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.headers:type_name -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Headers
+	5, // 2: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.object:type_name -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Object
+	6, // 3: gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.trailers:type_name -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse.Trailers
 	0, // 4: gitlab.agent.gitops.rpc.Gitops.GetObjectsToSynchronize:input_type -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeRequest
-	1, // 5: gitlab.agent.gitops.rpc.Gitops.GetObjectsToSynchronize:output_type -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse
-	5, // [5:6] is the sub-list for method output_type
-	4, // [4:5] is the sub-list for method input_type
+	2, // 5: gitlab.agent.gitops.rpc.Gitops.GetBranches:input_type -> gitlab.agent.gitops.rpc.BranchesRequest
+	1, // 6: gitlab.agent.gitops.rpc.Gitops.GetObjectsToSynchronize:output_type -> gitlab.agent.gitops.rpc.ObjectsToSynchronizeResponse
+	3, // 7: gitlab.agent.gitops.rpc.Gitops.GetBranches:output_type -> gitlab.agent.gitops.rpc.BranchesResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
 	4, // [4:4] is the sub-list for extension type_name
 	4, // [4:4] is the sub-list for extension extendee
 	0, // [0:4] is the sub-list for field type_name
@@ -468,7 +619,7 @@ func file_internal_module_gitops_rpc_rpc_proto_init() {
 			}
 		}
 		file_internal_module_gitops_rpc_rpc_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ObjectsToSynchronizeResponse_Headers); i {
+			switch v := v.(*BranchesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -480,7 +631,7 @@ func file_internal_module_gitops_rpc_rpc_proto_init() {
 			}
 		}
 		file_internal_module_gitops_rpc_rpc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ObjectsToSynchronizeResponse_Object); i {
+			switch v := v.(*BranchesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -492,6 +643,30 @@ func file_internal_module_gitops_rpc_rpc_proto_init() {
 			}
 		}
 		file_internal_module_gitops_rpc_rpc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ObjectsToSynchronizeResponse_Headers); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_gitops_rpc_rpc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ObjectsToSynchronizeResponse_Object); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_module_gitops_rpc_rpc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ObjectsToSynchronizeResponse_Trailers); i {
 			case 0:
 				return &v.state
@@ -515,7 +690,7 @@ func file_internal_module_gitops_rpc_rpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_module_gitops_rpc_rpc_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -542,6 +717,7 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type GitopsClient interface {
 	GetObjectsToSynchronize(ctx context.Context, in *ObjectsToSynchronizeRequest, opts ...grpc.CallOption) (Gitops_GetObjectsToSynchronizeClient, error)
+	GetBranches(ctx context.Context, in *BranchesRequest, opts ...grpc.CallOption) (Gitops_GetBranchesClient, error)
 }
 
 type gitopsClient struct {
@@ -584,9 +760,42 @@ func (x *gitopsGetObjectsToSynchronizeClient) Recv() (*ObjectsToSynchronizeRespo
 	return m, nil
 }
 
+func (c *gitopsClient) GetBranches(ctx context.Context, in *BranchesRequest, opts ...grpc.CallOption) (Gitops_GetBranchesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Gitops_serviceDesc.Streams[1], "/gitlab.agent.gitops.rpc.Gitops/GetBranches", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gitopsGetBranchesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Gitops_GetBranchesClient interface {
+	Recv() (*BranchesResponse, error)
+	grpc.ClientStream
+}
+
+type gitopsGetBranchesClient struct {
+	grpc.ClientStream
+}
+
+func (x *gitopsGetBranchesClient) Recv() (*BranchesResponse, error) {
+	m := new(BranchesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // GitopsServer is the server API for Gitops service.
 type GitopsServer interface {
 	GetObjectsToSynchronize(*ObjectsToSynchronizeRequest, Gitops_GetObjectsToSynchronizeServer) error
+	GetBranches(*BranchesRequest, Gitops_GetBranchesServer) error
 }
 
 // UnimplementedGitopsServer can be embedded to have forward compatible implementations.
@@ -596,6 +805,9 @@ type UnimplementedGitopsServer struct {
 func (*UnimplementedGitopsServer) GetObjectsToSynchronize(*ObjectsToSynchronizeRequest, Gitops_GetObjectsToSynchronizeServer) error {
 	return status.Errorf(codes.Unimplemented, "method GetObjectsToSynchronize not implemented")
 }
+func (*UnimplementedGitopsServer) GetBranches(*BranchesRequest, Gitops_GetBranchesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetBranches not implemented")
+}
 
 func RegisterGitopsServer(s *grpc.Server, srv GitopsServer) {
 	s.RegisterService(&_Gitops_serviceDesc, srv)
@@ -622,6 +834,27 @@ func (x *gitopsGetObjectsToSynchronizeServer) Send(m *ObjectsToSynchronizeRespon
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Gitops_GetBranches_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BranchesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitopsServer).GetBranches(m, &gitopsGetBranchesServer{stream})
+}
+
+type Gitops_GetBranchesServer interface {
+	Send(*BranchesResponse) error
+	grpc.ServerStream
+}
+
+type gitopsGetBranchesServer struct {
+	grpc.ServerStream
+}
+
+func (x *gitopsGetBranchesServer) Send(m *BranchesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Gitops_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "gitlab.agent.gitops.rpc.Gitops",
 	HandlerType: (*GitopsServer)(nil),
@@ -632,6 +865,11 @@ var _Gitops_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Gitops_GetObjectsToSynchronize_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetBranches",
+			Handler:       _Gitops_GetBranches_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "internal/module/gitops/rpc/rpc.proto",
 }
@@ -75,6 +75,10 @@ func (m *ObjectsToSynchronizeRequest) Validate() error {
 
 	}
 
+	// no validation rules for SemverTagConstraint
+
+	// no validation rules for Branch
+
 	return nil
 }
 
@@ -249,6 +253,150 @@ var _ interface {
 	ErrorName() string
 } = ObjectsToSynchronizeResponseValidationError{}
 
+// Validate checks the field values on BranchesRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *BranchesRequest) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetProjectId()) < 1 {
+		return BranchesRequestValidationError{
+			field:  "ProjectId",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetBranchGlob()) < 1 {
+		return BranchesRequestValidationError{
+			field:  "BranchGlob",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	return nil
+}
+
+// BranchesRequestValidationError is the validation error returned by
+// BranchesRequest.Validate if the designated constraints aren't met.
+type BranchesRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BranchesRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BranchesRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BranchesRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BranchesRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BranchesRequestValidationError) ErrorName() string { return "BranchesRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e BranchesRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBranchesRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BranchesRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BranchesRequestValidationError{}
+
+// Validate checks the field values on BranchesResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *BranchesResponse) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	return nil
+}
+
+// BranchesResponseValidationError is the validation error returned by
+// BranchesResponse.Validate if the designated constraints aren't met.
+type BranchesResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e BranchesResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e BranchesResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e BranchesResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e BranchesResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e BranchesResponseValidationError) ErrorName() string { return "BranchesResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e BranchesResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sBranchesResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = BranchesResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = BranchesResponseValidationError{}
+
 // Validate checks the field values on ObjectsToSynchronizeResponse_Headers
 // with the rules defined in the proto definition for this message. If any
 // rules are violated, an error is returned.
@@ -264,6 +412,8 @@ func (m *ObjectsToSynchronizeResponse_Headers) Validate() error {
 		}
 	}
 
+	// no validation rules for Tag
+
 	return nil
 }
 
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v2"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modserver"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type branchesPollJob struct {
+	ctx               context.Context
+	log               *zap.Logger
+	api               modserver.API
+	gitalyPool        gitaly.PoolInterface
+	projectInfoClient *projectinfo.Client
+	req               *rpc.BranchesRequest
+	server            rpc.Gitops_GetBranchesServer
+	agentToken        api.AgentToken
+	// lastSentBranches is the list of branches that was sent last. nil if nothing has been sent yet.
+	lastSentBranches []string
+}
+
+func (j *branchesPollJob) Attempt() (bool /*done*/, error) {
+	projectInfo, err, retErr := getProjectInfo(j.ctx, j.log, j.api, j.projectInfoClient, j.agentToken, j.req.ProjectId)
+	if retErr {
+		return false, err
+	}
+	p, err := j.gitalyPool.Poller(j.ctx, &projectInfo.GitalyInfo)
+	if err != nil {
+		j.api.HandleProcessingError(j.ctx, j.log, "GitOps: Poller", err)
+		return false, nil // don't want to close the response stream, so report no error
+	}
+	allBranches, err := p.Branches(j.ctx, &projectInfo.Repository)
+	if err != nil {
+		j.api.HandleProcessingError(j.ctx, j.log, "GitOps: failed to list branches", err)
+		return false, nil // don't want to close the response stream, so report no error
+	}
+	branches := make([]string, 0, len(allBranches)) // not nil to distinguish from "nothing sent yet"
+	for _, branch := range allBranches {
+		match, err := doublestar.Match(j.req.BranchGlob, branch)
+		if err != nil {
+			return false, status.Errorf(codes.InvalidArgument, "invalid branch glob %q: %v", j.req.BranchGlob, err)
+		}
+		if match {
+			branches = append(branches, branch)
+		}
+	}
+	sort.Strings(branches)
+	if j.lastSentBranches != nil && stringSlicesEqual(j.lastSentBranches, branches) {
+		j.log.Debug("GitOps: no changes to branches")
+		return false, nil
+	}
+	err = j.server.Send(&rpc.BranchesResponse{
+		Branches: branches,
+	})
+	if err != nil {
+		return false, j.api.HandleSendError(j.log, "GitOps: failed to send branches", err)
+	}
+	j.lastSentBranches = branches
+	return false, nil
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"time"
 
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modserver"
@@ -23,11 +24,11 @@ func (f *Factory) New(config *modserver.Config) (modserver.Module, error) {
 	m := &module{
 		api:        config.Api,
 		gitalyPool: config.Gitaly,
-		projectInfoClient: &projectInfoClient{
-			GitLabClient:             config.GitLabClient,
-			ProjectInfoCacheTtl:      projectInfoCacheTtl,
-			ProjectInfoCacheErrorTtl: projectInfoCacheErrorTtl,
-			ProjectInfoCache:         cache.New(minDuration(projectInfoCacheTtl, projectInfoCacheErrorTtl)),
+		projectInfoClient: &projectinfo.Client{
+			GitLabClient:  config.GitLabClient,
+			CacheTtl:      projectInfoCacheTtl,
+			CacheErrorTtl: projectInfoCacheErrorTtl,
+			Cache:         cache.New(minDuration(projectInfoCacheTtl, projectInfoCacheErrorTtl)),
 		},
 		syncCount:                config.UsageTracker.RegisterCounter(gitopsSyncCountKnownMetric),
 		pollPeriod:               gitops.PollPeriod.AsDuration(),
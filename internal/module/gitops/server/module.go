@@ -4,8 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modserver"
@@ -19,7 +21,7 @@ import (
 type module struct {
 	api                      modserver.API
 	gitalyPool               gitaly.PoolInterface
-	projectInfoClient        *projectInfoClient
+	projectInfoClient        *projectinfo.Client
 	syncCount                usage_metrics.Counter
 	pollPeriod               time.Duration
 	maxConnectionAge         time.Duration
@@ -45,6 +47,10 @@ func (m *module) GetObjectsToSynchronize(req *rpc.ObjectsToSynchronizeRequest, s
 	if err != nil {
 		return err // no wrap
 	}
+	tagConstraint, err := parseSemverTagConstraint(req.SemverTagConstraint)
+	if err != nil {
+		return err // no wrap
+	}
 	p := pollJob{
 		ctx:                      ctx,
 		log:                      log.With(logz.AgentId(agentInfo.Id), logz.ProjectId(req.ProjectId)),
@@ -53,6 +59,7 @@ func (m *module) GetObjectsToSynchronize(req *rpc.ObjectsToSynchronizeRequest, s
 		projectInfoClient:        m.projectInfoClient,
 		syncCount:                m.syncCount,
 		req:                      req,
+		tagConstraint:            tagConstraint,
 		server:                   server,
 		agentToken:               agentToken,
 		maxManifestFileSize:      m.maxManifestFileSize,
@@ -62,6 +69,27 @@ func (m *module) GetObjectsToSynchronize(req *rpc.ObjectsToSynchronizeRequest, s
 	return m.api.PollImmediateUntil(ctx, m.pollPeriod, m.maxConnectionAge, p.Attempt)
 }
 
+func (m *module) GetBranches(req *rpc.BranchesRequest, server rpc.Gitops_GetBranchesServer) error {
+	ctx := server.Context()
+	agentToken := api.AgentTokenFromContext(ctx)
+	log := grpctool.LoggerFromContext(ctx)
+	agentInfo, err, retErr := m.api.GetAgentInfo(ctx, log, agentToken, false)
+	if retErr {
+		return err // no wrap
+	}
+	p := branchesPollJob{
+		ctx:               ctx,
+		log:               log.With(logz.AgentId(agentInfo.Id), logz.ProjectId(req.ProjectId)),
+		api:               m.api,
+		gitalyPool:        m.gitalyPool,
+		projectInfoClient: m.projectInfoClient,
+		req:               req,
+		server:            server,
+		agentToken:        agentToken,
+	}
+	return m.api.PollImmediateUntil(ctx, m.pollPeriod, m.maxConnectionAge, p.Attempt)
+}
+
 func (m *module) Name() string {
 	return gitops.ModuleName
 }
@@ -73,5 +101,21 @@ func (m *module) validateGetObjectsToSynchronizeRequest(req *rpc.ObjectsToSynchr
 		// This check must be here, but there too.
 		return status.Errorf(codes.InvalidArgument, "maximum number of GitOps paths per manifest project is %d, but %d was requested", m.maxNumberOfPaths, numberOfPaths)
 	}
+	if req.Branch != "" && req.SemverTagConstraint != "" {
+		return status.Error(codes.InvalidArgument, "branch and semantic version tag constraint cannot be used together")
+	}
 	return nil
 }
+
+// parseSemverTagConstraint parses the optional semantic version constraint.
+// nil is returned if the constraint is empty.
+func parseSemverTagConstraint(constraint string) (*semver.Constraints, error) {
+	if constraint == "" {
+		return nil, nil
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid semantic version tag constraint %q: %v", constraint, err)
+	}
+	return c, nil
+}
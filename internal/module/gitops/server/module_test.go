@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/golang/mock/gomock"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +15,7 @@ import (
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modserver"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/testing/kube_testing"
@@ -72,17 +74,17 @@ func TestGetObjectsToSynchronizeGetProjectInfoFailures(t *testing.T) {
 		}).Times(2)
 	expectedErr := &gitlab.ClientError{Kind: gitlab.ErrorKindOther, StatusCode: http.StatusInternalServerError}
 	query := url.Values{
-		projectIdQueryParam: []string{projectId},
+		projectinfo.ProjectIdQueryParam: []string{projectId},
 	}
 	gomock.InOrder(
 		gitlabClient.EXPECT().
-			DoJSON(gomock.Any(), http.MethodGet, projectInfoApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
+			DoJSON(gomock.Any(), http.MethodGet, projectinfo.ApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
 			Return(&gitlab.ClientError{Kind: gitlab.ErrorKindForbidden, StatusCode: http.StatusForbidden}),
 		gitlabClient.EXPECT().
-			DoJSON(gomock.Any(), http.MethodGet, projectInfoApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
+			DoJSON(gomock.Any(), http.MethodGet, projectinfo.ApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
 			Return(&gitlab.ClientError{Kind: gitlab.ErrorKindUnauthorized, StatusCode: http.StatusUnauthorized}),
 		gitlabClient.EXPECT().
-			DoJSON(gomock.Any(), http.MethodGet, projectInfoApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
+			DoJSON(gomock.Any(), http.MethodGet, projectinfo.ApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
 			Return(expectedErr),
 		mockApi.EXPECT().
 			HandleProcessingError(gomock.Any(), gomock.Any(), "GetProjectInfo()", expectedErr).
@@ -183,10 +185,10 @@ func TestGetObjectsToSynchronize(t *testing.T) {
 			}),
 	)
 	query := url.Values{
-		projectIdQueryParam: []string{projectId},
+		projectinfo.ProjectIdQueryParam: []string{projectId},
 	}
 	gitlabClient.EXPECT().
-		DoJSON(gomock.Any(), http.MethodGet, projectInfoApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
+		DoJSON(gomock.Any(), http.MethodGet, projectinfo.ApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
 		DoAndReturn(func(ctx context.Context, method, path string, query url.Values, agentToken api.AgentToken, body, response interface{}) error {
 			mock_gitlab.AssignResult(response, projectInfoRest())
 			return nil
@@ -250,11 +252,11 @@ func TestGetObjectsToSynchronizeResumeConnection(t *testing.T) {
 		MinTimes(1)
 	p := mock_internalgitaly.NewMockPollerInterface(mockCtrl)
 	query := url.Values{
-		projectIdQueryParam: []string{projectId},
+		projectinfo.ProjectIdQueryParam: []string{projectId},
 	}
 	gomock.InOrder(
 		gitlabClient.EXPECT().
-			DoJSON(gomock.Any(), http.MethodGet, projectInfoApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
+			DoJSON(gomock.Any(), http.MethodGet, projectinfo.ApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
 			DoAndReturn(func(ctx context.Context, method, path string, query url.Values, agentToken api.AgentToken, body, response interface{}) error {
 				mock_gitlab.AssignResult(response, projectInfoRest())
 				return nil
@@ -279,6 +281,115 @@ func TestGetObjectsToSynchronizeResumeConnection(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestGetObjectsToSynchronizeSemverTag(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m, mockCtrl, gitalyPool, gitlabClient := setupModule(t, 1)
+	projInfo := projectInfo()
+	server := mock_rpc.NewMockGitops_GetObjectsToSynchronizeServer(mockCtrl)
+	server.EXPECT().
+		Context().
+		Return(mock_modserver.IncomingCtx(ctx, t, mock_gitlab.AgentkToken)).
+		MinTimes(1)
+	p := mock_internalgitaly.NewMockPollerInterface(mockCtrl)
+	query := url.Values{
+		projectinfo.ProjectIdQueryParam: []string{projectId},
+	}
+	gomock.InOrder(
+		gitlabClient.EXPECT().
+			DoJSON(gomock.Any(), http.MethodGet, projectinfo.ApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, method, path string, query url.Values, agentToken api.AgentToken, body, response interface{}) error {
+				mock_gitlab.AssignResult(response, projectInfoRest())
+				return nil
+			}),
+		gitalyPool.EXPECT().
+			Poller(gomock.Any(), &projInfo.GitalyInfo).
+			Return(p, nil),
+		p.EXPECT().
+			PollSemverTag(gomock.Any(), &projInfo.Repository, revision, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, repo *gitalypb.Repository, lastProcessedCommitId string, constraint *semver.Constraints) (*gitaly.PollInfo, error) {
+				assert.Equal(t, "~1.2", constraint.String())
+				cancel() // stop the test
+				return &gitaly.PollInfo{
+					UpdateAvailable: false,
+					CommitId:        revision,
+					Tag:             "v1.2.3",
+				}, nil
+			}),
+	)
+	err := m.GetObjectsToSynchronize(&rpc.ObjectsToSynchronizeRequest{
+		ProjectId:           projectId,
+		CommitId:            revision,
+		SemverTagConstraint: "~1.2",
+	}, server)
+	require.NoError(t, err)
+}
+
+func TestGetObjectsToSynchronizeInvalidSemverTagConstraint(t *testing.T) {
+	m, mockCtrl, _, _ := setupModule(t, 0)
+	server := mock_rpc.NewMockGitops_GetObjectsToSynchronizeServer(mockCtrl)
+	server.EXPECT().
+		Context().
+		Return(mock_modserver.IncomingCtx(context.Background(), t, mock_gitlab.AgentkToken)).
+		MinTimes(1)
+	err := m.GetObjectsToSynchronize(&rpc.ObjectsToSynchronizeRequest{
+		ProjectId:           projectId,
+		SemverTagConstraint: "not a constraint",
+	}, server)
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetBranches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m, mockCtrl, gitalyPool, gitlabClient := setupModule(t, 3)
+	projInfo := projectInfo()
+	server := mock_rpc.NewMockGitops_GetBranchesServer(mockCtrl)
+	server.EXPECT().
+		Context().
+		Return(mock_modserver.IncomingCtx(ctx, t, mock_gitlab.AgentkToken)).
+		MinTimes(1)
+	query := url.Values{
+		projectinfo.ProjectIdQueryParam: []string{projectId},
+	}
+	gitlabClient.EXPECT().
+		DoJSON(gomock.Any(), http.MethodGet, projectinfo.ApiPath, query, mock_gitlab.AgentkToken, nil, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, method, path string, query url.Values, agentToken api.AgentToken, body, response interface{}) error {
+			mock_gitlab.AssignResult(response, projectInfoRest())
+			return nil
+		}).
+		Times(3)
+	p := mock_internalgitaly.NewMockPollerInterface(mockCtrl)
+	gitalyPool.EXPECT().
+		Poller(gomock.Any(), &projInfo.GitalyInfo).
+		Return(p, nil).
+		Times(3)
+	gomock.InOrder(
+		p.EXPECT().
+			Branches(gomock.Any(), &projInfo.Repository).
+			Return([]string{"master", "review/b", "review/a"}, nil),
+		server.EXPECT().
+			Send(matcher.ProtoEq(t, &rpc.BranchesResponse{
+				Branches: []string{"review/a", "review/b"},
+			})),
+		// no changes, nothing is sent
+		p.EXPECT().
+			Branches(gomock.Any(), &projInfo.Repository).
+			Return([]string{"review/a", "master", "review/b"}, nil),
+		p.EXPECT().
+			Branches(gomock.Any(), &projInfo.Repository).
+			Return([]string{"master"}, nil),
+		server.EXPECT().
+			Send(matcher.ProtoEq(t, &rpc.BranchesResponse{})),
+	)
+	err := m.GetBranches(&rpc.BranchesRequest{
+		ProjectId:  projectId,
+		BranchGlob: "review/*",
+	}, server)
+	require.NoError(t, err)
+}
+
 func TestObjectsToSynchronizeVisitor(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -576,8 +687,8 @@ func TestGlobToGitaly(t *testing.T) {
 	}
 }
 
-func projectInfoRest() *projectInfoResponse {
-	return &projectInfoResponse{
+func projectInfoRest() *projectinfo.Response {
+	return &projectinfo.Response{
 		ProjectId: 234,
 		GitalyInfo: gitlab.GitalyInfo{
 			Address: "127.0.0.1:321321",
@@ -5,9 +5,11 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitlab/projectinfo"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modserver"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/usage_metrics"
@@ -35,9 +37,10 @@ type pollJob struct {
 	log                      *zap.Logger
 	api                      modserver.API
 	gitalyPool               gitaly.PoolInterface
-	projectInfoClient        *projectInfoClient
+	projectInfoClient        *projectinfo.Client
 	syncCount                usage_metrics.Counter
 	req                      *rpc.ObjectsToSynchronizeRequest
+	tagConstraint            *semver.Constraints
 	server                   rpc.Gitops_GetObjectsToSynchronizeServer
 	agentToken               api.AgentToken
 	maxManifestFileSize      int64
@@ -49,17 +52,21 @@ func (j *pollJob) Attempt() (bool /*done*/, error) {
 	// This call is made on each poll because:
 	// - it checks that the agent's token is still valid
 	// - repository location in Gitaly might have changed
-	projectInfo, err, retErr := j.getProjectInfo(j.ctx, j.log, j.agentToken, j.req.ProjectId)
+	projectInfo, err, retErr := getProjectInfo(j.ctx, j.log, j.api, j.projectInfoClient, j.agentToken, j.req.ProjectId)
 	if retErr {
 		return false, err
 	}
-	revision := gitaly.DefaultBranch // TODO support user-specified branches/tags
 	p, err := j.gitalyPool.Poller(j.ctx, &projectInfo.GitalyInfo)
 	if err != nil {
 		j.api.HandleProcessingError(j.ctx, j.log, "GitOps: Poller", err)
 		return false, nil // don't want to close the response stream, so report no error
 	}
-	info, err := p.Poll(j.ctx, &projectInfo.Repository, j.req.CommitId, revision)
+	var info *gitaly.PollInfo
+	if j.tagConstraint == nil {
+		info, err = p.Poll(j.ctx, &projectInfo.Repository, j.req.CommitId, j.req.Branch) // empty branch means gitaly.DefaultBranch
+	} else {
+		info, err = p.PollSemverTag(j.ctx, &projectInfo.Repository, j.req.CommitId, j.tagConstraint)
+	}
 	if err != nil {
 		j.api.HandleProcessingError(j.ctx, j.log, "GitOps: repository poll failed", err)
 		return false, nil // don't want to close the response stream, so report no error
@@ -69,8 +76,8 @@ func (j *pollJob) Attempt() (bool /*done*/, error) {
 		return false, nil
 	}
 	log := j.log.With(logz.CommitId(info.CommitId))
-	log.Info("GitOps: new commit")
-	err = j.sendObjectsToSynchronizeHeaders(j.server, log, info.CommitId)
+	log.Info("GitOps: new commit", logz.GitTag(info.Tag))
+	err = j.sendObjectsToSynchronizeHeaders(j.server, log, info.CommitId, info.Tag)
 	if err != nil {
 		return false, err // no wrap
 	}
@@ -87,11 +94,12 @@ func (j *pollJob) Attempt() (bool /*done*/, error) {
 	return true, nil
 }
 
-func (j *pollJob) sendObjectsToSynchronizeHeaders(server rpc.Gitops_GetObjectsToSynchronizeServer, log *zap.Logger, commitId string) error {
+func (j *pollJob) sendObjectsToSynchronizeHeaders(server rpc.Gitops_GetObjectsToSynchronizeServer, log *zap.Logger, commitId, tag string) error {
 	err := server.Send(&rpc.ObjectsToSynchronizeResponse{
 		Message: &rpc.ObjectsToSynchronizeResponse_Headers_{
 			Headers: &rpc.ObjectsToSynchronizeResponse_Headers{
 				CommitId: commitId,
+				Tag:      tag,
 			},
 		},
 	})
@@ -146,8 +154,8 @@ func (j *pollJob) sendObjectsToSynchronizeTrailers(server rpc.Gitops_GetObjectsT
 	return nil
 }
 
-func (j *pollJob) getProjectInfo(ctx context.Context, log *zap.Logger, agentToken api.AgentToken, projectId string) (*api.ProjectInfo, error, bool /* return the error? */) {
-	projectInfo, err := j.projectInfoClient.GetProjectInfo(ctx, agentToken, projectId)
+func getProjectInfo(ctx context.Context, log *zap.Logger, mApi modserver.API, client *projectinfo.Client, agentToken api.AgentToken, projectId string) (*api.ProjectInfo, error, bool /* return the error? */) {
+	projectInfo, err := client.GetProjectInfo(ctx, agentToken, projectId)
 	switch {
 	case err == nil:
 		return projectInfo, nil, false
@@ -158,7 +166,7 @@ func (j *pollJob) getProjectInfo(ctx context.Context, log *zap.Logger, agentToke
 	case gitlab.IsUnauthorized(err):
 		err = status.Error(codes.Unauthenticated, "unauthenticated")
 	default:
-		j.api.HandleProcessingError(ctx, log, "GetProjectInfo()", err)
+		mApi.HandleProcessingError(ctx, log, "GetProjectInfo()", err)
 		err = nil // don't want to close the response stream, so report no error
 	}
 	return nil, err, true
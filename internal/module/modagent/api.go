@@ -2,11 +2,13 @@ package modagent
 
 import (
 	"context"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap"
@@ -14,6 +16,10 @@ import (
 	"k8s.io/cli-runtime/pkg/resource"
 )
 
+// ErrAgentIdentityNotAvailable is returned by API.GetAgentIdentity if kas has not sent the agent's identity.
+// Older kas versions don't send it.
+var ErrAgentIdentityNotAvailable = errors.New("agent identity is not available")
+
 // Config holds configuration for a Module.
 type Config struct {
 	Log       *zap.Logger
@@ -23,6 +29,9 @@ type Config struct {
 	K8sClientGetter resource.RESTClientGetter
 	// KasConn is the gRPC connection to gitlab-kas.
 	KasConn grpc.ClientConnInterface
+	// Registerer allows to register metrics.
+	// Metrics should be registered in Run and unregistered before Run returns.
+	Registerer prometheus.Registerer
 }
 
 type GitLabResponse struct {
@@ -35,6 +44,12 @@ type GitLabResponse struct {
 // API provides the API for the module to use.
 type API interface {
 	MakeGitLabRequest(ctx context.Context, path string, opts ...GitLabRequestOption) (*GitLabResponse, error)
+	// GetAgentIdentity returns the identity of the agent in GitLab. The identity is sent by kas with the configuration.
+	// The identity is known by the time a module is started, but this method blocks until the first configuration
+	// has been received or ctx signals done, if called earlier.
+	// ErrAgentIdentityNotAvailable is returned if kas has not sent the identity.
+	// The returned object is a shared instance, must not be mutated.
+	GetAgentIdentity(ctx context.Context) (*modshared.AgentIdentity, error)
 }
 
 type Factory interface {
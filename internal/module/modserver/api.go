@@ -56,6 +56,9 @@ type API interface {
 	// PollImmediateUntil should be used by the top-level polling, so that it can be gracefully interrupted
 	// by the server when necessary.
 	PollImmediateUntil(ctx context.Context, interval, maxConnectionAge time.Duration, condition ConditionFunc) error
+	// PollImmediateUntilWithWakeup is like PollImmediateUntil, but condition is also called each time wakeup receives
+	// a value, without waiting for the interval to pass.
+	PollImmediateUntilWithWakeup(ctx context.Context, interval, maxConnectionAge time.Duration, wakeup <-chan struct{}, condition ConditionFunc) error
 	// HandleProcessingError can be used to handle errors occurring while handling a gRPC API call.
 	HandleProcessingError(ctx context.Context, log *zap.Logger, msg string, err error)
 	// HandleSendError can be used to handle error produced by gRPC SendMsg() method.
@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"github.com/prometheus/client_golang/prometheus"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modagent"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/observability"
 	"go.uber.org/zap"
@@ -8,12 +9,18 @@ import (
 
 type Factory struct {
 	LogLevel zap.AtomicLevel
+	// ListenAddress is the address to serve Prometheus metrics on. Metrics are not served if it is empty.
+	ListenAddress string
+	Gatherer      prometheus.Gatherer
 }
 
 func (f *Factory) New(config *modagent.Config) (modagent.Module, error) {
 	return &module{
-		log:      config.Log,
-		logLevel: f.LogLevel,
+		log:           config.Log,
+		logLevel:      f.LogLevel,
+		listenAddress: f.ListenAddress,
+		gatherer:      f.Gatherer,
+		registerer:    config.Registerer,
 	}, nil
 }
 
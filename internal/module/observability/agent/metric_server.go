@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/httpz"
+)
+
+const (
+	defaultPrometheusUrlPath = "/metrics"
+
+	defaultMaxRequestDuration = 15 * time.Second
+	shutdownTimeout           = defaultMaxRequestDuration
+	readTimeout               = 1 * time.Second
+	writeTimeout              = defaultMaxRequestDuration
+	idleTimeout               = 1 * time.Minute
+)
+
+type metricServer struct {
+	Listener          net.Listener
+	PrometheusUrlPath string
+	Gatherer          prometheus.Gatherer
+	Registerer        prometheus.Registerer
+}
+
+func (s *metricServer) Run(ctx context.Context) error {
+	srv := &http.Server{
+		Handler:      s.constructHandler(),
+		WriteTimeout: writeTimeout,
+		ReadTimeout:  readTimeout,
+		IdleTimeout:  idleTimeout,
+	}
+	return httpz.RunServer(ctx, srv, s.Listener, shutdownTimeout)
+}
+
+func (s *metricServer) constructHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(
+		s.PrometheusUrlPath,
+		promhttp.InstrumentMetricHandler(s.Registerer, promhttp.HandlerFor(s.Gatherer, promhttp.HandlerOpts{
+			Timeout: defaultMaxRequestDuration,
+		})),
+	)
+	return mux
+}
@@ -3,20 +3,58 @@ package agent
 import (
 	"context"
 	"fmt"
+	"net"
+
+	"github.com/prometheus/client_golang/prometheus"
 
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/observability"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/logz"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/tool/protodefault"
 	"gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 type module struct {
-	log      *zap.Logger
-	logLevel zap.AtomicLevel
+	log           *zap.Logger
+	logLevel      zap.AtomicLevel
+	listenAddress string
+	gatherer      prometheus.Gatherer
+	registerer    prometheus.Registerer
 }
 
 func (m *module) Run(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguration) error {
+	if m.listenAddress != "" {
+		lis, err := net.Listen("tcp", m.listenAddress)
+		if err != nil {
+			return err
+		}
+		// Error is ignored because metricSrv.Run() closes the listener and
+		// a second close always produces an error.
+		defer lis.Close() // nolint:errcheck
+
+		m.log.Info("Observability endpoint is up",
+			logz.NetNetworkFromAddr(lis.Addr()),
+			logz.NetAddressFromAddr(lis.Addr()),
+		)
+
+		metricSrv := &metricServer{
+			Listener:          lis,
+			PrometheusUrlPath: defaultPrometheusUrlPath,
+			Gatherer:          m.gatherer,
+			Registerer:        m.registerer,
+		}
+		var wg wait.Group
+		defer wg.Wait()
+		srvCtx, cancel := context.WithCancel(ctx)
+		defer cancel() // stop the server when configuration channel is closed
+		wg.StartWithContext(srvCtx, func(ctx context.Context) {
+			err := metricSrv.Run(ctx)
+			if err != nil {
+				m.log.Error("Observability endpoint failed", zap.Error(err))
+			}
+		})
+	}
 	for config := range cfg {
 		err := m.setConfigurationLogging(config.Observability.Logging)
 		if err != nil {
@@ -28,9 +66,14 @@ func (m *module) Run(ctx context.Context, cfg <-chan *agentcfg.AgentConfiguratio
 }
 
 func (m *module) DefaultAndValidateConfiguration(config *agentcfg.AgentConfiguration) error {
+	return DefaultAndValidateConfiguration(config)
+}
+
+// DefaultAndValidateConfiguration applies defaults to and validates the observability section.
+func DefaultAndValidateConfiguration(config *agentcfg.AgentConfiguration) error {
 	protodefault.NotNil(&config.Observability)
 	protodefault.NotNil(&config.Observability.Logging)
-	err := m.defaultAndValidateLogging(config.Observability.Logging)
+	err := defaultAndValidateLogging(config.Observability.Logging)
 	if err != nil {
 		return fmt.Errorf("logging: %v", err)
 	}
@@ -41,7 +84,7 @@ func (m *module) Name() string {
 	return observability.ModuleName
 }
 
-func (m *module) defaultAndValidateLogging(logging *agentcfg.LoggingCF) error {
+func defaultAndValidateLogging(logging *agentcfg.LoggingCF) error {
 	_, err := logz.LevelFromString(logging.Level.String())
 	return err
 }
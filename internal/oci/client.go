@@ -0,0 +1,387 @@
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	MediaTypeImageManifest   = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeDockerManifest  = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeImageLayer      = "application/vnd.oci.image.layer.v1.tar"
+	MediaTypeImageLayerGzip  = "application/vnd.oci.image.layer.v1.tar+gzip"
+	MediaTypeDockerLayerGzip = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+
+	// AnnotationTitle holds the file name of a layer that is a single file, not an archive.
+	AnnotationTitle = "org.opencontainers.image.title"
+)
+
+const (
+	dockerContentDigestHeader    = "Docker-Content-Digest"
+	acceptedManifestMediaTypes   = MediaTypeImageManifest + ", " + MediaTypeDockerManifest
+	maxManifestSize              = 4 * 1024 * 1024
+	maxTokenResponseSize         = 1024 * 1024
+	maxTagsResponseSize          = 4 * 1024 * 1024
+	maxTagsPages                 = 100
+	bearerAuthenticationScheme   = "bearer"
+	basicAuthenticationScheme    = "basic"
+	tokenScopeRepositoryTemplate = "repository:%s:pull"
+)
+
+// Descriptor describes a blob.
+// See https://github.com/opencontainers/image-spec/blob/master/descriptor.md.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an image or an artifact manifest.
+// See https://github.com/opencontainers/image-spec/blob/master/manifest.md.
+type Manifest struct {
+	MediaType string       `json:"mediaType"`
+	Config    Descriptor   `json:"config"`
+	Layers    []Descriptor `json:"layers"`
+}
+
+// Client is a minimal client for the OCI distribution API that can pull artifacts.
+// See https://github.com/opencontainers/distribution-spec/blob/master/spec.md.
+type Client struct {
+	HTTPClient *http.Client
+	// Username and Password are used for basic authentication and to get a bearer token. Optional.
+	Username string
+	Password string
+	// PlainHTTP makes the client use HTTP instead of HTTPS.
+	PlainHTTP bool
+}
+
+// Resolve returns the digest of the manifest the reference points to.
+func (c *Client) Resolve(ctx context.Context, ref Reference) (string, error) {
+	resp, err := c.do(ctx, http.MethodHead, ref, "/manifests/"+ref.manifestReference(), acceptedManifestMediaTypes)
+	if err != nil {
+		return "", err
+	}
+	defer discardAndClose(resp.Body)
+	digest := resp.Header.Get(dockerContentDigestHeader)
+	if digest != "" {
+		return digest, nil
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+	// Registry didn't send the digest, have to fetch and hash the manifest.
+	_, digest, err = c.fetchManifest(ctx, ref, ref.Tag)
+	return digest, err
+}
+
+// FetchManifest fetches the manifest with the digest, verifying its content.
+func (c *Client) FetchManifest(ctx context.Context, ref Reference, digest string) (*Manifest, error) {
+	manifest, actualDigest, err := c.fetchManifest(ctx, ref, digest)
+	if err != nil {
+		return nil, err
+	}
+	if actualDigest != digest {
+		return nil, fmt.Errorf("manifest digest mismatch: expected %s, got %s", digest, actualDigest)
+	}
+	return manifest, nil
+}
+
+// ListTags returns all tags in the repository the reference points to. Tag and Digest of the reference are ignored.
+func (c *Client) ListTags(ctx context.Context, ref Reference) ([]string, error) {
+	var tags []string
+	path := "/tags/list"
+	for i := 0; i < maxTagsPages; i++ {
+		resp, err := c.do(ctx, http.MethodGet, ref, path, "")
+		if err != nil {
+			return nil, err
+		}
+		var tagsResp struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, maxTagsResponseSize)).Decode(&tagsResp)
+		discardAndClose(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("tags response decode: %v", err)
+		}
+		tags = append(tags, tagsResp.Tags...)
+		next, err := nextPagePath(resp.Header.Get("Link"), ref)
+		if err != nil {
+			return nil, err
+		}
+		if next == "" {
+			return tags, nil
+		}
+		path = next
+	}
+	return nil, fmt.Errorf("more than %d pages of tags", maxTagsPages)
+}
+
+func (c *Client) fetchManifest(ctx context.Context, ref Reference, manifestRef string) (*Manifest, string /* digest */, error) {
+	resp, err := c.do(ctx, http.MethodGet, ref, "/manifests/"+manifestRef, acceptedManifestMediaTypes)
+	if err != nil {
+		return nil, "", err
+	}
+	defer discardAndClose(resp.Body)
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("manifest read: %v", err)
+	}
+	if len(data) > maxManifestSize {
+		return nil, "", fmt.Errorf("manifest is bigger than %d bytes", maxManifestSize)
+	}
+	manifest := &Manifest{}
+	err = json.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, "", fmt.Errorf("manifest decode: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	return manifest, "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// FetchBlob fetches the blob. The content is verified against the descriptor's digest and size
+// while it is being read, an error is returned from Read if it does not match.
+func (c *Client) FetchBlob(ctx context.Context, ref Reference, desc Descriptor) (io.ReadCloser, error) {
+	if !strings.HasPrefix(desc.Digest, "sha256:") {
+		return nil, fmt.Errorf("unsupported digest algorithm: %s", desc.Digest)
+	}
+	resp, err := c.do(ctx, http.MethodGet, ref, "/blobs/"+desc.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{
+		body:      resp.Body,
+		r:         io.LimitReader(resp.Body, desc.Size),
+		hash:      sha256.New(),
+		digest:    desc.Digest,
+		remaining: desc.Size,
+	}, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, ref Reference, path, accept string) (*http.Response, error) {
+	u := c.url(ref, path)
+	resp, err := c.doWithAuth(ctx, method, u, accept, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		discardAndClose(resp.Body)
+		authorization, err := c.authorization(ctx, ref, challenge)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = c.doWithAuth(ctx, method, u, accept, authorization)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		discardAndClose(resp.Body)
+		return nil, fmt.Errorf("%s %s: unexpected status code: %d", method, u, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (c *Client) doWithAuth(ctx context.Context, method, u, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return c.HTTPClient.Do(req)
+}
+
+// authorization constructs the Authorization header value to answer the challenge.
+func (c *Client) authorization(ctx context.Context, ref Reference, challenge string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case basicAuthenticationScheme:
+		if c.Username == "" && c.Password == "" {
+			return "", errors.New("registry requires authentication, but no credentials configured")
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password)), nil
+	case bearerAuthenticationScheme:
+		token, err := c.fetchToken(ctx, ref, params)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge: %q", challenge)
+	}
+}
+
+// fetchToken gets a bearer token from the token server.
+// See https://docs.docker.com/registry/spec/auth/token/.
+func (c *Client) fetchToken(ctx context.Context, ref Reference, params map[string]string) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", errors.New("bearer authentication challenge without realm")
+	}
+	u, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid realm: %v", err)
+	}
+	q := u.Query()
+	if service := params["service"]; service != "" {
+		q.Set("service", service)
+	}
+	q.Set("scope", fmt.Sprintf(tokenScopeRepositoryTemplate, ref.Repository))
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer discardAndClose(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request: unexpected status code: %d", resp.StatusCode)
+	}
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxTokenResponseSize)).Decode(&tokenResp)
+	if err != nil {
+		return "", fmt.Errorf("token response decode: %v", err)
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	if tokenResp.AccessToken != "" {
+		return tokenResp.AccessToken, nil
+	}
+	return "", errors.New("token response without a token")
+}
+
+func (c *Client) url(ref Reference, path string) string {
+	scheme := "https"
+	if c.PlainHTTP {
+		scheme = "http"
+	}
+	return scheme + "://" + ref.Registry + "/v2/" + ref.Repository + path
+}
+
+// nextPagePath extracts the path of the next page, relative to the repository, from a Link header value.
+// e.g. </v2/group/project/tags/list?n=100&last=v1>; rel="next"
+func nextPagePath(link string, ref Reference) (string, error) {
+	if link == "" {
+		return "", nil
+	}
+	start := strings.IndexByte(link, '<')
+	end := strings.IndexByte(link, '>')
+	if start == -1 || end < start || !strings.Contains(link[end:], `rel="next"`) {
+		return "", nil
+	}
+	u, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return "", fmt.Errorf("invalid Link header: %v", err)
+	}
+	prefix := "/v2/" + ref.Repository
+	if !strings.HasPrefix(u.Path, prefix+"/") {
+		return "", fmt.Errorf("unexpected Link header: %s", link)
+	}
+	next := u.Path[len(prefix):]
+	if u.RawQuery != "" {
+		next += "?" + u.RawQuery
+	}
+	return next, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header value with a single challenge.
+// e.g. Bearer realm="https://gitlab.com/jwt/auth",service="container_registry"
+func parseChallenge(challenge string) (string /* scheme */, map[string]string /* params */) {
+	challenge = strings.TrimSpace(challenge)
+	space := strings.IndexByte(challenge, ' ')
+	if space == -1 {
+		return strings.ToLower(challenge), nil
+	}
+	scheme := strings.ToLower(challenge[:space])
+	params := make(map[string]string)
+	rest := challenge[space+1:]
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		eq := strings.IndexByte(rest, '=')
+		if eq == -1 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end == -1 {
+				break
+			}
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end == -1 {
+				end = len(rest)
+			}
+			value = strings.TrimSpace(rest[:end])
+			rest = rest[end:]
+		}
+		params[key] = value
+	}
+	return scheme, params
+}
+
+type verifyingReader struct {
+	body      io.ReadCloser
+	r         io.Reader
+	hash      hash.Hash
+	digest    string
+	remaining int64
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.hash.Write(p[:n]) // nolint: errcheck
+	v.remaining -= int64(n)
+	if v.remaining < 0 {
+		return n, fmt.Errorf("blob %s: more data than expected", v.digest)
+	}
+	if errors.Is(err, io.EOF) {
+		if v.remaining != 0 {
+			return n, fmt.Errorf("blob %s: unexpected end of data", v.digest)
+		}
+		if actual := "sha256:" + hex.EncodeToString(v.hash.Sum(nil)); actual != v.digest {
+			return n, fmt.Errorf("blob digest mismatch: expected %s, got %s", v.digest, actual)
+		}
+	}
+	return n, err
+}
+
+func (v *verifyingReader) Close() error {
+	return v.body.Close()
+}
+
+func discardAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(body, 8*1024))
+	_ = body.Close()
+}
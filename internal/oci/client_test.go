@@ -0,0 +1,239 @@
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	repository = "group/project/bundle"
+	tag        = "v1"
+	username   = "user"
+	password   = "pass"
+	token      = "token1"
+)
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + hex.EncodeToString(make([]byte, sha256.Size))
+	tests := []struct {
+		ref         string
+		expected    Reference
+		expectedErr string
+	}{
+		{
+			ref: "registry.gitlab.com/group/project/bundle:v1.2.3",
+			expected: Reference{
+				Registry:   "registry.gitlab.com",
+				Repository: "group/project/bundle",
+				Tag:        "v1.2.3",
+			},
+		},
+		{
+			ref: "localhost:5000/bundle:latest",
+			expected: Reference{
+				Registry:   "localhost:5000",
+				Repository: "bundle",
+				Tag:        "latest",
+			},
+		},
+		{
+			ref: "registry.gitlab.com/group/bundle@" + digest,
+			expected: Reference{
+				Registry:   "registry.gitlab.com",
+				Repository: "group/bundle",
+				Digest:     digest,
+			},
+		},
+		{
+			ref:         "group/bundle:v1",
+			expectedErr: `invalid reference "group/bundle:v1": registry is required`,
+		},
+		{
+			ref:         "registry.gitlab.com/group/bundle@sha256:abc",
+			expectedErr: `invalid reference "registry.gitlab.com/group/bundle@sha256:abc": invalid digest`,
+		},
+		{
+			ref:         "registry.gitlab.com/Group/bundle",
+			expectedErr: `invalid reference "registry.gitlab.com/Group/bundle": invalid repository name`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ref, func(t *testing.T) {
+			ref, err := ParseReference(tc.ref) // nolint: scopelint
+			if tc.expectedErr != "" {          // nolint: scopelint
+				assert.EqualError(t, err, tc.expectedErr) // nolint: scopelint
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ref)     // nolint: scopelint
+			assert.Equal(t, tc.ref, ref.String()) // nolint: scopelint
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://gitlab.com/jwt/auth",service="container_registry", scope="repository:a/b:pull"`)
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://gitlab.com/jwt/auth",
+		"service": "container_registry",
+		"scope":   "repository:a/b:pull",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm=Registry`)
+	assert.Equal(t, "basic", scheme)
+	assert.Equal(t, map[string]string{
+		"realm": "Registry",
+	}, params)
+}
+
+func TestPullWithBearerToken(t *testing.T) {
+	blob := []byte("blob data")
+	blobDigest := digestOf(blob)
+	manifest, err := json.Marshal(Manifest{
+		MediaType: MediaTypeImageManifest,
+		Layers: []Descriptor{
+			{
+				MediaType: "application/yaml",
+				Digest:    blobDigest,
+				Size:      int64(len(blob)),
+				Annotations: map[string]string{
+					AnnotationTitle: "manifest.yaml",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	manifestDigest := digestOf(manifest)
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/jwt/auth", func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok || u != username || p != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "registry", r.URL.Query().Get("service"))
+		assert.Equal(t, "repository:"+repository+":pull", r.URL.Query().Get("scope"))
+		_, _ = w.Write([]byte(`{"token":"` + token + `"}`))
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/jwt/auth",service="registry"`, srv.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/" + repository + "/manifests/" + tag, "/v2/" + repository + "/manifests/" + manifestDigest:
+			assert.Contains(t, r.Header.Get("Accept"), MediaTypeImageManifest)
+			w.Header().Set(dockerContentDigestHeader, manifestDigest)
+			_, _ = w.Write(manifest)
+		case "/v2/" + repository + "/blobs/" + blobDigest:
+			_, _ = w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	c := newClient(srv)
+	ref := Reference{
+		Registry:   registryHost(t, srv),
+		Repository: repository,
+		Tag:        tag,
+	}
+	ctx := context.Background()
+	digest, err := c.Resolve(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, manifestDigest, digest)
+
+	m, err := c.FetchManifest(ctx, ref, digest)
+	require.NoError(t, err)
+	require.Len(t, m.Layers, 1)
+	assert.Equal(t, "manifest.yaml", m.Layers[0].Annotations[AnnotationTitle])
+
+	r, err := c.FetchBlob(ctx, ref, m.Layers[0])
+	require.NoError(t, err)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, blob, data)
+}
+
+func TestFetchBlobDigestMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("tampered"))
+	}))
+	defer srv.Close()
+	c := newClient(srv)
+	ref := Reference{
+		Registry:   registryHost(t, srv),
+		Repository: repository,
+		Tag:        tag,
+	}
+	expected := []byte("original")
+	r, err := c.FetchBlob(context.Background(), ref, Descriptor{
+		Digest: digestOf(expected),
+		Size:   int64(len("tampered")),
+	})
+	require.NoError(t, err)
+	defer r.Close()
+	_, err = ioutil.ReadAll(r)
+	assert.EqualError(t, err, fmt.Sprintf("blob digest mismatch: expected %s, got %s", digestOf(expected), digestOf([]byte("tampered"))))
+}
+
+func TestListTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/"+repository+"/tags/list", r.URL.Path)
+		switch r.URL.Query().Get("last") {
+		case "":
+			w.Header().Set("Link", `</v2/`+repository+`/tags/list?last=v2&n=2>; rel="next"`)
+			_, _ = w.Write([]byte(`{"name":"` + repository + `","tags":["v1","v2"]}`))
+		case "v2":
+			_, _ = w.Write([]byte(`{"name":"` + repository + `","tags":["v3"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := newClient(srv)
+	tags, err := c.ListTags(context.Background(), Reference{
+		Registry:   registryHost(t, srv),
+		Repository: repository,
+		Tag:        tag,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v2", "v3"}, tags)
+}
+
+func newClient(srv *httptest.Server) *Client {
+	return &Client{
+		HTTPClient: srv.Client(),
+		Username:   username,
+		Password:   password,
+		PlainHTTP:  true,
+	}
+}
+
+func registryHost(t *testing.T, srv *httptest.Server) string {
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	return u.Host
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
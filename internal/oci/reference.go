@@ -0,0 +1,79 @@
+package oci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	digestRegex = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+	tagRegex    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	// repositoryRegex is a simplified version of the path component grammar of the distribution spec.
+	repositoryRegex = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+)
+
+// Reference is a reference to an artifact in a registry.
+type Reference struct {
+	// Registry is the host of the registry with an optional port. e.g. registry.gitlab.com
+	Registry string
+	// Repository is the name of the repository in the registry. e.g. group/project/bundle
+	Repository string
+	// Tag is the tag of the artifact. Empty if Digest is set.
+	Tag string
+	// Digest is the digest of the artifact manifest. Empty if Tag is set.
+	Digest string
+}
+
+// ParseReference parses a reference in registry/repository:tag or registry/repository@digest form.
+// Registry is required because there is no default registry.
+func ParseReference(ref string) (Reference, error) {
+	slash := strings.IndexByte(ref, '/')
+	if slash == -1 {
+		return Reference{}, fmt.Errorf("invalid reference %q: registry is required", ref)
+	}
+	r := Reference{
+		Registry: ref[:slash],
+	}
+	if !strings.ContainsAny(r.Registry, ".:") && r.Registry != "localhost" {
+		return Reference{}, fmt.Errorf("invalid reference %q: registry is required", ref)
+	}
+	rest := ref[slash+1:]
+	if at := strings.IndexByte(rest, '@'); at != -1 {
+		r.Repository = rest[:at]
+		r.Digest = rest[at+1:]
+		if !digestRegex.MatchString(r.Digest) {
+			return Reference{}, fmt.Errorf("invalid reference %q: invalid digest", ref)
+		}
+	} else {
+		if colon := strings.LastIndexByte(rest, ':'); colon != -1 {
+			r.Repository = rest[:colon]
+			r.Tag = rest[colon+1:]
+		} else {
+			r.Repository = rest
+			r.Tag = "latest"
+		}
+		if !tagRegex.MatchString(r.Tag) {
+			return Reference{}, fmt.Errorf("invalid reference %q: invalid tag", ref)
+		}
+	}
+	if !repositoryRegex.MatchString(r.Repository) {
+		return Reference{}, fmt.Errorf("invalid reference %q: invalid repository name", ref)
+	}
+	return r, nil
+}
+
+// manifestReference returns the tag or the digest, whichever is set.
+func (r Reference) manifestReference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+func (r Reference) String() string {
+	if r.Digest != "" {
+		return r.Registry + "/" + r.Repository + "@" + r.Digest
+	}
+	return r.Registry + "/" + r.Repository + ":" + r.Tag
+}
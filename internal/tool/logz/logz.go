@@ -52,6 +52,29 @@ func CommitId(commitId string) zap.Field {
 	return zap.String("commit_id", commitId)
 }
 
+// GitTag is the name of a Git tag. Skipped if empty.
+func GitTag(tag string) zap.Field {
+	if tag == "" {
+		return zap.Skip()
+	}
+	return zap.String("git_tag", tag)
+}
+
+// GitBranch is the name of a Git branch.
+func GitBranch(branch string) zap.Field {
+	return zap.String("git_branch", branch)
+}
+
+// Kubernetes namespace name.
+func Namespace(namespace string) zap.Field {
+	return zap.String("namespace", namespace)
+}
+
+// Kubernetes object name.
+func ObjectName(name string) zap.Field {
+	return zap.String("object_name", name)
+}
+
 func NumberOfFiles(n uint32) zap.Field {
 	return zap.Uint32("number_of_files", n)
 }
@@ -18,3 +18,32 @@ func JitterUntil(ctx context.Context, period time.Duration, f func(context.Conte
 func PollImmediateUntil(ctx context.Context, interval time.Duration, condition wait.ConditionFunc) error {
 	return wait.PollImmediateUntil(interval, condition, ctx.Done())
 }
+
+// PollImmediateUntilWithWakeup is like PollImmediateUntil, but condition is also called each time wakeup receives
+// a value, without waiting for the interval to pass. The interval starts again after each call.
+// A nil wakeup channel is never ready.
+func PollImmediateUntilWithWakeup(ctx context.Context, interval time.Duration, wakeup <-chan struct{}, condition wait.ConditionFunc) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return wait.ErrWaitTimeout
+		default:
+		}
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return wait.ErrWaitTimeout
+		case <-t.C:
+		case <-wakeup:
+			t.Stop()
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestPollImmediateUntilWithWakeup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	wakeup := make(chan struct{}, 1)
+	calls := 0
+	err := PollImmediateUntilWithWakeup(ctx, time.Hour, wakeup, func() (bool /*done*/, error) {
+		calls++
+		if calls == 3 {
+			return true, nil
+		}
+		wakeup <- struct{}{} // don't wait for an hour
+		return false, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPollImmediateUntilWithWakeupContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := PollImmediateUntilWithWakeup(ctx, time.Hour, nil, func() (bool /*done*/, error) {
+		calls++
+		cancel()
+		return false, nil
+	})
+	assert.Equal(t, wait.ErrWaitTimeout, err)
+	assert.Equal(t, 1, calls)
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	semver "github.com/Masterminds/semver/v3"
 	gomock "github.com/golang/mock/gomock"
 	api "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/api"
 	gitaly "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/gitaly"
@@ -248,6 +249,21 @@ func (m *MockPollerInterface) EXPECT() *MockPollerInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Branches mocks base method.
+func (m *MockPollerInterface) Branches(arg0 context.Context, arg1 *gitalypb.Repository) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Branches", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Branches indicates an expected call of Branches.
+func (mr *MockPollerInterfaceMockRecorder) Branches(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Branches", reflect.TypeOf((*MockPollerInterface)(nil).Branches), arg0, arg1)
+}
+
 // Poll mocks base method.
 func (m *MockPollerInterface) Poll(arg0 context.Context, arg1 *gitalypb.Repository, arg2, arg3 string) (*gitaly.PollInfo, error) {
 	m.ctrl.T.Helper()
@@ -262,3 +278,18 @@ func (mr *MockPollerInterfaceMockRecorder) Poll(arg0, arg1, arg2, arg3 interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poll", reflect.TypeOf((*MockPollerInterface)(nil).Poll), arg0, arg1, arg2, arg3)
 }
+
+// PollSemverTag mocks base method.
+func (m *MockPollerInterface) PollSemverTag(arg0 context.Context, arg1 *gitalypb.Repository, arg2 string, arg3 *semver.Constraints) (*gitaly.PollInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollSemverTag", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*gitaly.PollInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PollSemverTag indicates an expected call of PollSemverTag.
+func (mr *MockPollerInterfaceMockRecorder) PollSemverTag(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollSemverTag", reflect.TypeOf((*MockPollerInterface)(nil).PollSemverTag), arg0, arg1, arg2, arg3)
+}
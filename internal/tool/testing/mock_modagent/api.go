@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	modagent "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modagent"
+	modshared "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/modshared"
 	agentcfg "gitlab.com/gitlab-org/cluster-integration/gitlab-agent/pkg/agentcfg"
 )
 
@@ -36,6 +37,21 @@ func (m *MockAPI) EXPECT() *MockAPIMockRecorder {
 	return m.recorder
 }
 
+// GetAgentIdentity mocks base method.
+func (m *MockAPI) GetAgentIdentity(arg0 context.Context) (*modshared.AgentIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAgentIdentity", arg0)
+	ret0, _ := ret[0].(*modshared.AgentIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgentIdentity indicates an expected call of GetAgentIdentity.
+func (mr *MockAPIMockRecorder) GetAgentIdentity(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgentIdentity", reflect.TypeOf((*MockAPI)(nil).GetAgentIdentity), arg0)
+}
+
 // MakeGitLabRequest mocks base method.
 func (m *MockAPI) MakeGitLabRequest(arg0 context.Context, arg1 string, arg2 ...modagent.GitLabRequestOption) (*modagent.GitLabResponse, error) {
 	m.ctrl.T.Helper()
@@ -111,3 +111,17 @@ func (mr *MockAPIMockRecorder) PollImmediateUntil(arg0, arg1, arg2, arg3 interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollImmediateUntil", reflect.TypeOf((*MockAPI)(nil).PollImmediateUntil), arg0, arg1, arg2, arg3)
 }
+
+// PollImmediateUntilWithWakeup mocks base method.
+func (m *MockAPI) PollImmediateUntilWithWakeup(arg0 context.Context, arg1, arg2 time.Duration, arg3 <-chan struct{}, arg4 modserver.ConditionFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollImmediateUntilWithWakeup", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PollImmediateUntilWithWakeup indicates an expected call of PollImmediateUntilWithWakeup.
+func (mr *MockAPIMockRecorder) PollImmediateUntilWithWakeup(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollImmediateUntilWithWakeup", reflect.TypeOf((*MockAPI)(nil).PollImmediateUntilWithWakeup), arg0, arg1, arg2, arg3, arg4)
+}
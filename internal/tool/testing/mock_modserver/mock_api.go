@@ -25,3 +25,21 @@ func NewMockAPIWithMockPoller(ctrl *gomock.Controller, pollTimes int) *MockAPI {
 	}
 	return mockApi
 }
+
+func NewMockAPIWithMockWakeupPoller(ctrl *gomock.Controller, pollTimes int) *MockAPI {
+	mockApi := NewMockAPI(ctrl)
+	if pollTimes > 0 {
+		mockApi.EXPECT().
+			PollImmediateUntilWithWakeup(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, interval, connectionMaxAge time.Duration, wakeup <-chan struct{}, condition modserver.ConditionFunc) error {
+				for i := 0; i < pollTimes; i++ {
+					done, err := condition()
+					if err != nil || done {
+						return err
+					}
+				}
+				return nil
+			})
+	}
+	return mockApi
+}
@@ -57,6 +57,46 @@ func (mr *MockAgentConfigurationClientMockRecorder) GetConfiguration(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfiguration", reflect.TypeOf((*MockAgentConfigurationClient)(nil).GetConfiguration), varargs...)
 }
 
+// ReportAppliedConfiguration mocks base method.
+func (m *MockAgentConfigurationClient) ReportAppliedConfiguration(arg0 context.Context, arg1 *rpc.ReportAppliedConfigurationRequest, arg2 ...grpc.CallOption) (*rpc.ReportAppliedConfigurationResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReportAppliedConfiguration", varargs...)
+	ret0, _ := ret[0].(*rpc.ReportAppliedConfigurationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportAppliedConfiguration indicates an expected call of ReportAppliedConfiguration.
+func (mr *MockAgentConfigurationClientMockRecorder) ReportAppliedConfiguration(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportAppliedConfiguration", reflect.TypeOf((*MockAgentConfigurationClient)(nil).ReportAppliedConfiguration), varargs...)
+}
+
+// ReportConfigurationErrors mocks base method.
+func (m *MockAgentConfigurationClient) ReportConfigurationErrors(arg0 context.Context, arg1 *rpc.ReportConfigurationErrorsRequest, arg2 ...grpc.CallOption) (*rpc.ReportConfigurationErrorsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReportConfigurationErrors", varargs...)
+	ret0, _ := ret[0].(*rpc.ReportConfigurationErrorsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportConfigurationErrors indicates an expected call of ReportConfigurationErrors.
+func (mr *MockAgentConfigurationClientMockRecorder) ReportConfigurationErrors(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportConfigurationErrors", reflect.TypeOf((*MockAgentConfigurationClient)(nil).ReportConfigurationErrors), varargs...)
+}
+
 // MockAgentConfiguration_GetConfigurationClient is a mock of AgentConfiguration_GetConfigurationClient interface.
 type MockAgentConfiguration_GetConfigurationClient struct {
 	ctrl     *gomock.Controller
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc (interfaces: GitopsClient,Gitops_GetObjectsToSynchronizeClient,Gitops_GetObjectsToSynchronizeServer,ObjectsToSynchronizeWatcherInterface)
+// Source: gitlab.com/gitlab-org/cluster-integration/gitlab-agent/internal/module/gitops/rpc (interfaces: GitopsClient,Gitops_GetObjectsToSynchronizeClient,Gitops_GetObjectsToSynchronizeServer,ObjectsToSynchronizeWatcherInterface,Gitops_GetBranchesClient,Gitops_GetBranchesServer,BranchesWatcherInterface)
 
 // Package mock_rpc is a generated GoMock package.
 package mock_rpc
@@ -37,6 +37,26 @@ func (m *MockGitopsClient) EXPECT() *MockGitopsClientMockRecorder {
 	return m.recorder
 }
 
+// GetBranches mocks base method.
+func (m *MockGitopsClient) GetBranches(arg0 context.Context, arg1 *rpc.BranchesRequest, arg2 ...grpc.CallOption) (rpc.Gitops_GetBranchesClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetBranches", varargs...)
+	ret0, _ := ret[0].(rpc.Gitops_GetBranchesClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranches indicates an expected call of GetBranches.
+func (mr *MockGitopsClientMockRecorder) GetBranches(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranches", reflect.TypeOf((*MockGitopsClient)(nil).GetBranches), varargs...)
+}
+
 // GetObjectsToSynchronize mocks base method.
 func (m *MockGitopsClient) GetObjectsToSynchronize(arg0 context.Context, arg1 *rpc.ObjectsToSynchronizeRequest, arg2 ...grpc.CallOption) (rpc.Gitops_GetObjectsToSynchronizeClient, error) {
 	m.ctrl.T.Helper()
@@ -335,3 +355,280 @@ func (mr *MockObjectsToSynchronizeWatcherInterfaceMockRecorder) Watch(arg0, arg1
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockObjectsToSynchronizeWatcherInterface)(nil).Watch), arg0, arg1, arg2)
 }
+
+// MockGitops_GetBranchesClient is a mock of Gitops_GetBranchesClient interface.
+type MockGitops_GetBranchesClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockGitops_GetBranchesClientMockRecorder
+}
+
+// MockGitops_GetBranchesClientMockRecorder is the mock recorder for MockGitops_GetBranchesClient.
+type MockGitops_GetBranchesClientMockRecorder struct {
+	mock *MockGitops_GetBranchesClient
+}
+
+// NewMockGitops_GetBranchesClient creates a new mock instance.
+func NewMockGitops_GetBranchesClient(ctrl *gomock.Controller) *MockGitops_GetBranchesClient {
+	mock := &MockGitops_GetBranchesClient{ctrl: ctrl}
+	mock.recorder = &MockGitops_GetBranchesClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGitops_GetBranchesClient) EXPECT() *MockGitops_GetBranchesClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockGitops_GetBranchesClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockGitops_GetBranchesClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockGitops_GetBranchesClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockGitops_GetBranchesClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockGitops_GetBranchesClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockGitops_GetBranchesClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockGitops_GetBranchesClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockGitops_GetBranchesClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockGitops_GetBranchesClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockGitops_GetBranchesClient) Recv() (*rpc.BranchesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*rpc.BranchesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockGitops_GetBranchesClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockGitops_GetBranchesClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockGitops_GetBranchesClient) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockGitops_GetBranchesClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockGitops_GetBranchesClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockGitops_GetBranchesClient) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockGitops_GetBranchesClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockGitops_GetBranchesClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockGitops_GetBranchesClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockGitops_GetBranchesClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockGitops_GetBranchesClient)(nil).Trailer))
+}
+
+// MockGitops_GetBranchesServer is a mock of Gitops_GetBranchesServer interface.
+type MockGitops_GetBranchesServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockGitops_GetBranchesServerMockRecorder
+}
+
+// MockGitops_GetBranchesServerMockRecorder is the mock recorder for MockGitops_GetBranchesServer.
+type MockGitops_GetBranchesServerMockRecorder struct {
+	mock *MockGitops_GetBranchesServer
+}
+
+// NewMockGitops_GetBranchesServer creates a new mock instance.
+func NewMockGitops_GetBranchesServer(ctrl *gomock.Controller) *MockGitops_GetBranchesServer {
+	mock := &MockGitops_GetBranchesServer{ctrl: ctrl}
+	mock.recorder = &MockGitops_GetBranchesServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGitops_GetBranchesServer) EXPECT() *MockGitops_GetBranchesServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockGitops_GetBranchesServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockGitops_GetBranchesServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockGitops_GetBranchesServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m *MockGitops_GetBranchesServer) RecvMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockGitops_GetBranchesServerMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockGitops_GetBranchesServer)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockGitops_GetBranchesServer) Send(arg0 *rpc.BranchesResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockGitops_GetBranchesServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockGitops_GetBranchesServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockGitops_GetBranchesServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockGitops_GetBranchesServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockGitops_GetBranchesServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockGitops_GetBranchesServer) SendMsg(arg0 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockGitops_GetBranchesServerMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockGitops_GetBranchesServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockGitops_GetBranchesServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockGitops_GetBranchesServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockGitops_GetBranchesServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockGitops_GetBranchesServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockGitops_GetBranchesServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockGitops_GetBranchesServer)(nil).SetTrailer), arg0)
+}
+
+// MockBranchesWatcherInterface is a mock of BranchesWatcherInterface interface.
+type MockBranchesWatcherInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockBranchesWatcherInterfaceMockRecorder
+}
+
+// MockBranchesWatcherInterfaceMockRecorder is the mock recorder for MockBranchesWatcherInterface.
+type MockBranchesWatcherInterfaceMockRecorder struct {
+	mock *MockBranchesWatcherInterface
+}
+
+// NewMockBranchesWatcherInterface creates a new mock instance.
+func NewMockBranchesWatcherInterface(ctrl *gomock.Controller) *MockBranchesWatcherInterface {
+	mock := &MockBranchesWatcherInterface{ctrl: ctrl}
+	mock.recorder = &MockBranchesWatcherInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBranchesWatcherInterface) EXPECT() *MockBranchesWatcherInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Watch mocks base method.
+func (m *MockBranchesWatcherInterface) Watch(arg0 context.Context, arg1 *rpc.BranchesRequest, arg2 rpc.BranchesCallback) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Watch", arg0, arg1, arg2)
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockBranchesWatcherInterfaceMockRecorder) Watch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockBranchesWatcherInterface)(nil).Watch), arg0, arg1, arg2)
+}
@@ -12,6 +12,8 @@ import (
 
 	_ "github.com/envoyproxy/protoc-gen-validate/validate"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 )
@@ -139,7 +141,8 @@ type PathCF struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Glob string `protobuf:"bytes,1,opt,name=glob,proto3" json:"glob,omitempty"`
+	Glob     string      `protobuf:"bytes,1,opt,name=glob,proto3" json:"glob,omitempty"`
+	Renderer *RendererCF `protobuf:"bytes,2,opt,name=renderer,proto3" json:"renderer,omitempty"`
 }
 
 func (x *PathCF) Reset() {
@@ -181,22 +184,109 @@ func (x *PathCF) GetGlob() string {
 	return ""
 }
 
+func (x *PathCF) GetRenderer() *RendererCF {
+	if x != nil {
+		return x.Renderer
+	}
+	return nil
+}
+
+type RendererCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Command      []string           `protobuf:"bytes,1,rep,name=command,proto3" json:"command,omitempty"`
+	Timeout      *duration.Duration `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	MaxMemoryMib uint32             `protobuf:"varint,3,opt,name=max_memory_mib,proto3" json:"max_memory_mib,omitempty"`
+	MaxCpuTime   *duration.Duration `protobuf:"bytes,4,opt,name=max_cpu_time,proto3" json:"max_cpu_time,omitempty"`
+}
+
+func (x *RendererCF) Reset() {
+	*x = RendererCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RendererCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RendererCF) ProtoMessage() {}
+
+func (x *RendererCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RendererCF.ProtoReflect.Descriptor instead.
+func (*RendererCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RendererCF) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *RendererCF) GetTimeout() *duration.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *RendererCF) GetMaxMemoryMib() uint32 {
+	if x != nil {
+		return x.MaxMemoryMib
+	}
+	return 0
+}
+
+func (x *RendererCF) GetMaxCpuTime() *duration.Duration {
+	if x != nil {
+		return x.MaxCpuTime
+	}
+	return nil
+}
+
 type ManifestProjectCF struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id                 string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ResourceInclusions []*ResourceFilterCF `protobuf:"bytes,2,rep,name=resource_inclusions,proto3" json:"resource_inclusions,omitempty"`
-	ResourceExclusions []*ResourceFilterCF `protobuf:"bytes,3,rep,name=resource_exclusions,proto3" json:"resource_exclusions,omitempty"`
-	DefaultNamespace   string              `protobuf:"bytes,4,opt,name=default_namespace,proto3" json:"default_namespace,omitempty"`
-	Paths              []*PathCF           `protobuf:"bytes,5,rep,name=paths,proto3" json:"paths,omitempty"`
+	Id                    string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ResourceInclusions    []*ResourceFilterCF      `protobuf:"bytes,2,rep,name=resource_inclusions,proto3" json:"resource_inclusions,omitempty"`
+	ResourceExclusions    []*ResourceFilterCF      `protobuf:"bytes,3,rep,name=resource_exclusions,proto3" json:"resource_exclusions,omitempty"`
+	DefaultNamespace      string                   `protobuf:"bytes,4,opt,name=default_namespace,proto3" json:"default_namespace,omitempty"`
+	Paths                 []*PathCF                `protobuf:"bytes,5,rep,name=paths,proto3" json:"paths,omitempty"`
+	SemverTagConstraint   string                   `protobuf:"bytes,6,opt,name=semver_tag_constraint,proto3" json:"semver_tag_constraint,omitempty"`
+	PreviewEnvironments   *PreviewEnvironmentsCF   `protobuf:"bytes,7,opt,name=preview_environments,proto3" json:"preview_environments,omitempty"`
+	GitRemote             *GitRemoteCF             `protobuf:"bytes,8,opt,name=git_remote,proto3" json:"git_remote,omitempty"`
+	OciArtifact           *OciArtifactCF           `protobuf:"bytes,9,opt,name=oci_artifact,proto3" json:"oci_artifact,omitempty"`
+	ImageUpdateAutomation *ImageUpdateAutomationCF `protobuf:"bytes,10,opt,name=image_update_automation,proto3" json:"image_update_automation,omitempty"`
+	Variables             *VariablesCF             `protobuf:"bytes,11,opt,name=variables,proto3" json:"variables,omitempty"`
+	DependsOn             []string                 `protobuf:"bytes,12,rep,name=depends_on,proto3" json:"depends_on,omitempty"`
+	Cluster               string                   `protobuf:"bytes,13,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	DependsOnTimeout      *duration.Duration       `protobuf:"bytes,14,opt,name=depends_on_timeout,proto3" json:"depends_on_timeout,omitempty"`
 }
 
 func (x *ManifestProjectCF) Reset() {
 	*x = ManifestProjectCF{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[2]
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -209,7 +299,7 @@ func (x *ManifestProjectCF) String() string {
 func (*ManifestProjectCF) ProtoMessage() {}
 
 func (x *ManifestProjectCF) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[2]
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -222,7 +312,7 @@ func (x *ManifestProjectCF) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ManifestProjectCF.ProtoReflect.Descriptor instead.
 func (*ManifestProjectCF) Descriptor() ([]byte, []int) {
-	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{2}
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ManifestProjectCF) GetId() string {
@@ -260,18 +350,527 @@ func (x *ManifestProjectCF) GetPaths() []*PathCF {
 	return nil
 }
 
+func (x *ManifestProjectCF) GetSemverTagConstraint() string {
+	if x != nil {
+		return x.SemverTagConstraint
+	}
+	return ""
+}
+
+func (x *ManifestProjectCF) GetPreviewEnvironments() *PreviewEnvironmentsCF {
+	if x != nil {
+		return x.PreviewEnvironments
+	}
+	return nil
+}
+
+func (x *ManifestProjectCF) GetGitRemote() *GitRemoteCF {
+	if x != nil {
+		return x.GitRemote
+	}
+	return nil
+}
+
+func (x *ManifestProjectCF) GetOciArtifact() *OciArtifactCF {
+	if x != nil {
+		return x.OciArtifact
+	}
+	return nil
+}
+
+func (x *ManifestProjectCF) GetImageUpdateAutomation() *ImageUpdateAutomationCF {
+	if x != nil {
+		return x.ImageUpdateAutomation
+	}
+	return nil
+}
+
+func (x *ManifestProjectCF) GetVariables() *VariablesCF {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *ManifestProjectCF) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *ManifestProjectCF) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
+func (x *ManifestProjectCF) GetDependsOnTimeout() *duration.Duration {
+	if x != nil {
+		return x.DependsOnTimeout
+	}
+	return nil
+}
+
+type ClusterCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name                string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	KubeconfigSecret    string `protobuf:"bytes,2,opt,name=kubeconfig_secret,proto3" json:"kubeconfig_secret,omitempty"`
+	KubeconfigSecretKey string `protobuf:"bytes,3,opt,name=kubeconfig_secret_key,proto3" json:"kubeconfig_secret_key,omitempty"`
+	Context             string `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+}
+
+func (x *ClusterCF) Reset() {
+	*x = ClusterCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClusterCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterCF) ProtoMessage() {}
+
+func (x *ClusterCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterCF.ProtoReflect.Descriptor instead.
+func (*ClusterCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ClusterCF) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ClusterCF) GetKubeconfigSecret() string {
+	if x != nil {
+		return x.KubeconfigSecret
+	}
+	return ""
+}
+
+func (x *ClusterCF) GetKubeconfigSecretKey() string {
+	if x != nil {
+		return x.KubeconfigSecretKey
+	}
+	return ""
+}
+
+func (x *ClusterCF) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+type VariablesCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values    map[string]string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ConfigMap string            `protobuf:"bytes,2,opt,name=config_map,proto3" json:"config_map,omitempty"`
+}
+
+func (x *VariablesCF) Reset() {
+	*x = VariablesCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VariablesCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariablesCF) ProtoMessage() {}
+
+func (x *VariablesCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariablesCF.ProtoReflect.Descriptor instead.
+func (*VariablesCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VariablesCF) GetValues() map[string]string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *VariablesCF) GetConfigMap() string {
+	if x != nil {
+		return x.ConfigMap
+	}
+	return ""
+}
+
+type ImageUpdateAutomationCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Images             []*ImagePolicyCF `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	CreateMergeRequest bool             `protobuf:"varint,2,opt,name=create_merge_request,proto3" json:"create_merge_request,omitempty"`
+	GitlabUrl          string           `protobuf:"bytes,3,opt,name=gitlab_url,proto3" json:"gitlab_url,omitempty"`
+	TokenSecret        string           `protobuf:"bytes,4,opt,name=token_secret,proto3" json:"token_secret,omitempty"`
+}
+
+func (x *ImageUpdateAutomationCF) Reset() {
+	*x = ImageUpdateAutomationCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImageUpdateAutomationCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageUpdateAutomationCF) ProtoMessage() {}
+
+func (x *ImageUpdateAutomationCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageUpdateAutomationCF.ProtoReflect.Descriptor instead.
+func (*ImageUpdateAutomationCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ImageUpdateAutomationCF) GetImages() []*ImagePolicyCF {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *ImageUpdateAutomationCF) GetCreateMergeRequest() bool {
+	if x != nil {
+		return x.CreateMergeRequest
+	}
+	return false
+}
+
+func (x *ImageUpdateAutomationCF) GetGitlabUrl() string {
+	if x != nil {
+		return x.GitlabUrl
+	}
+	return ""
+}
+
+func (x *ImageUpdateAutomationCF) GetTokenSecret() string {
+	if x != nil {
+		return x.TokenSecret
+	}
+	return ""
+}
+
+type ImagePolicyCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Image             string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	SemverRange       string `protobuf:"bytes,2,opt,name=semver_range,proto3" json:"semver_range,omitempty"`
+	TagRegex          string `protobuf:"bytes,3,opt,name=tag_regex,proto3" json:"tag_regex,omitempty"`
+	CredentialsSecret string `protobuf:"bytes,4,opt,name=credentials_secret,proto3" json:"credentials_secret,omitempty"`
+}
+
+func (x *ImagePolicyCF) Reset() {
+	*x = ImagePolicyCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImagePolicyCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImagePolicyCF) ProtoMessage() {}
+
+func (x *ImagePolicyCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImagePolicyCF.ProtoReflect.Descriptor instead.
+func (*ImagePolicyCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ImagePolicyCF) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ImagePolicyCF) GetSemverRange() string {
+	if x != nil {
+		return x.SemverRange
+	}
+	return ""
+}
+
+func (x *ImagePolicyCF) GetTagRegex() string {
+	if x != nil {
+		return x.TagRegex
+	}
+	return ""
+}
+
+func (x *ImagePolicyCF) GetCredentialsSecret() string {
+	if x != nil {
+		return x.CredentialsSecret
+	}
+	return ""
+}
+
+type GitRemoteCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url               string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Ref               string `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`
+	CredentialsSecret string `protobuf:"bytes,3,opt,name=credentials_secret,proto3" json:"credentials_secret,omitempty"`
+}
+
+func (x *GitRemoteCF) Reset() {
+	*x = GitRemoteCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GitRemoteCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GitRemoteCF) ProtoMessage() {}
+
+func (x *GitRemoteCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GitRemoteCF.ProtoReflect.Descriptor instead.
+func (*GitRemoteCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GitRemoteCF) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *GitRemoteCF) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *GitRemoteCF) GetCredentialsSecret() string {
+	if x != nil {
+		return x.CredentialsSecret
+	}
+	return ""
+}
+
+type OciArtifactCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ref               string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	CredentialsSecret string `protobuf:"bytes,2,opt,name=credentials_secret,proto3" json:"credentials_secret,omitempty"`
+}
+
+func (x *OciArtifactCF) Reset() {
+	*x = OciArtifactCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OciArtifactCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OciArtifactCF) ProtoMessage() {}
+
+func (x *OciArtifactCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OciArtifactCF.ProtoReflect.Descriptor instead.
+func (*OciArtifactCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OciArtifactCF) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *OciArtifactCF) GetCredentialsSecret() string {
+	if x != nil {
+		return x.CredentialsSecret
+	}
+	return ""
+}
+
+type PreviewEnvironmentsCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BranchGlob        string `protobuf:"bytes,1,opt,name=branch_glob,proto3" json:"branch_glob,omitempty"`
+	NamespaceTemplate string `protobuf:"bytes,2,opt,name=namespace_template,proto3" json:"namespace_template,omitempty"`
+}
+
+func (x *PreviewEnvironmentsCF) Reset() {
+	*x = PreviewEnvironmentsCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PreviewEnvironmentsCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewEnvironmentsCF) ProtoMessage() {}
+
+func (x *PreviewEnvironmentsCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewEnvironmentsCF.ProtoReflect.Descriptor instead.
+func (*PreviewEnvironmentsCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PreviewEnvironmentsCF) GetBranchGlob() string {
+	if x != nil {
+		return x.BranchGlob
+	}
+	return ""
+}
+
+func (x *PreviewEnvironmentsCF) GetNamespaceTemplate() string {
+	if x != nil {
+		return x.NamespaceTemplate
+	}
+	return ""
+}
+
 type GitopsCF struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ManifestProjects []*ManifestProjectCF `protobuf:"bytes,1,rep,name=manifest_projects,proto3" json:"manifest_projects,omitempty"`
+	ManifestProjects                      []*ManifestProjectCF `protobuf:"bytes,1,rep,name=manifest_projects,proto3" json:"manifest_projects,omitempty"`
+	Namespaces                            []string             `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Clusters                              []*ClusterCF         `protobuf:"bytes,3,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	ManifestProjectResources              bool                 `protobuf:"varint,4,opt,name=manifest_project_resources,proto3" json:"manifest_project_resources,omitempty"`
+	ManifestProjectResourceProjects       []string             `protobuf:"bytes,5,rep,name=manifest_project_resource_projects,proto3" json:"manifest_project_resource_projects,omitempty"`
+	ManifestProjectResourceServiceAccount string               `protobuf:"bytes,6,opt,name=manifest_project_resource_service_account,proto3" json:"manifest_project_resource_service_account,omitempty"`
 }
 
 func (x *GitopsCF) Reset() {
 	*x = GitopsCF{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[3]
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -283,30 +882,65 @@ func (x *GitopsCF) String() string {
 
 func (*GitopsCF) ProtoMessage() {}
 
-func (x *GitopsCF) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *GitopsCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GitopsCF.ProtoReflect.Descriptor instead.
+func (*GitopsCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GitopsCF) GetManifestProjects() []*ManifestProjectCF {
+	if x != nil {
+		return x.ManifestProjects
+	}
+	return nil
+}
+
+func (x *GitopsCF) GetNamespaces() []string {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *GitopsCF) GetClusters() []*ClusterCF {
+	if x != nil {
+		return x.Clusters
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use GitopsCF.ProtoReflect.Descriptor instead.
-func (*GitopsCF) Descriptor() ([]byte, []int) {
-	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{3}
+func (x *GitopsCF) GetManifestProjectResources() bool {
+	if x != nil {
+		return x.ManifestProjectResources
+	}
+	return false
 }
 
-func (x *GitopsCF) GetManifestProjects() []*ManifestProjectCF {
+func (x *GitopsCF) GetManifestProjectResourceProjects() []string {
 	if x != nil {
-		return x.ManifestProjects
+		return x.ManifestProjectResourceProjects
 	}
 	return nil
 }
 
+func (x *GitopsCF) GetManifestProjectResourceServiceAccount() string {
+	if x != nil {
+		return x.ManifestProjectResourceServiceAccount
+	}
+	return ""
+}
+
 type ObservabilityCF struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -318,7 +952,7 @@ type ObservabilityCF struct {
 func (x *ObservabilityCF) Reset() {
 	*x = ObservabilityCF{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[4]
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -331,7 +965,7 @@ func (x *ObservabilityCF) String() string {
 func (*ObservabilityCF) ProtoMessage() {}
 
 func (x *ObservabilityCF) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[4]
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -344,7 +978,7 @@ func (x *ObservabilityCF) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ObservabilityCF.ProtoReflect.Descriptor instead.
 func (*ObservabilityCF) Descriptor() ([]byte, []int) {
-	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{4}
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ObservabilityCF) GetLogging() *LoggingCF {
@@ -365,7 +999,7 @@ type LoggingCF struct {
 func (x *LoggingCF) Reset() {
 	*x = LoggingCF{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[5]
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -378,7 +1012,7 @@ func (x *LoggingCF) String() string {
 func (*LoggingCF) ProtoMessage() {}
 
 func (x *LoggingCF) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[5]
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -391,7 +1025,7 @@ func (x *LoggingCF) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoggingCF.ProtoReflect.Descriptor instead.
 func (*LoggingCF) Descriptor() ([]byte, []int) {
-	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{5}
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *LoggingCF) GetLevel() LoggingLevelEnum {
@@ -412,7 +1046,7 @@ type CiliumCF struct {
 func (x *CiliumCF) Reset() {
 	*x = CiliumCF{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[6]
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -425,7 +1059,7 @@ func (x *CiliumCF) String() string {
 func (*CiliumCF) ProtoMessage() {}
 
 func (x *CiliumCF) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[6]
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -438,7 +1072,7 @@ func (x *CiliumCF) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CiliumCF.ProtoReflect.Descriptor instead.
 func (*CiliumCF) Descriptor() ([]byte, []int) {
-	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{6}
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *CiliumCF) GetHubbleRelayAddress() string {
@@ -448,20 +1082,140 @@ func (x *CiliumCF) GetHubbleRelayAddress() string {
 	return ""
 }
 
+type ModuleCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled *wrappers.BoolValue `protobuf:"bytes,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+}
+
+func (x *ModuleCF) Reset() {
+	*x = ModuleCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModuleCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleCF) ProtoMessage() {}
+
+func (x *ModuleCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleCF.ProtoReflect.Descriptor instead.
+func (*ModuleCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ModuleCF) GetEnabled() *wrappers.BoolValue {
+	if x != nil {
+		return x.Enabled
+	}
+	return nil
+}
+
+type IncludeCF struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Local   string `protobuf:"bytes,1,opt,name=local,proto3" json:"local,omitempty"`
+	Project string `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
+	File    string `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+	Ref     string `protobuf:"bytes,4,opt,name=ref,proto3" json:"ref,omitempty"`
+}
+
+func (x *IncludeCF) Reset() {
+	*x = IncludeCF{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IncludeCF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncludeCF) ProtoMessage() {}
+
+func (x *IncludeCF) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncludeCF.ProtoReflect.Descriptor instead.
+func (*IncludeCF) Descriptor() ([]byte, []int) {
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *IncludeCF) GetLocal() string {
+	if x != nil {
+		return x.Local
+	}
+	return ""
+}
+
+func (x *IncludeCF) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *IncludeCF) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *IncludeCF) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
 type ConfigurationFile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Gitops        *GitopsCF        `protobuf:"bytes,1,opt,name=gitops,proto3" json:"gitops,omitempty"`
-	Observability *ObservabilityCF `protobuf:"bytes,2,opt,name=observability,proto3" json:"observability,omitempty"`
-	Cilium        *CiliumCF        `protobuf:"bytes,3,opt,name=cilium,proto3" json:"cilium,omitempty"`
+	Gitops        *GitopsCF            `protobuf:"bytes,1,opt,name=gitops,proto3" json:"gitops,omitempty"`
+	Observability *ObservabilityCF     `protobuf:"bytes,2,opt,name=observability,proto3" json:"observability,omitempty"`
+	Cilium        *CiliumCF            `protobuf:"bytes,3,opt,name=cilium,proto3" json:"cilium,omitempty"`
+	Include       []*IncludeCF         `protobuf:"bytes,4,rep,name=include,proto3" json:"include,omitempty"`
+	Modules       map[string]*ModuleCF `protobuf:"bytes,5,rep,name=modules,proto3" json:"modules,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *ConfigurationFile) Reset() {
 	*x = ConfigurationFile{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[7]
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -474,7 +1228,7 @@ func (x *ConfigurationFile) String() string {
 func (*ConfigurationFile) ProtoMessage() {}
 
 func (x *ConfigurationFile) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[7]
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -487,7 +1241,7 @@ func (x *ConfigurationFile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfigurationFile.ProtoReflect.Descriptor instead.
 func (*ConfigurationFile) Descriptor() ([]byte, []int) {
-	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{7}
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ConfigurationFile) GetGitops() *GitopsCF {
@@ -511,20 +1265,35 @@ func (x *ConfigurationFile) GetCilium() *CiliumCF {
 	return nil
 }
 
+func (x *ConfigurationFile) GetInclude() []*IncludeCF {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *ConfigurationFile) GetModules() map[string]*ModuleCF {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
 type AgentConfiguration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Gitops        *GitopsCF        `protobuf:"bytes,1,opt,name=gitops,proto3" json:"gitops,omitempty"`
-	Observability *ObservabilityCF `protobuf:"bytes,2,opt,name=observability,proto3" json:"observability,omitempty"`
-	Cilium        *CiliumCF        `protobuf:"bytes,3,opt,name=cilium,proto3" json:"cilium,omitempty"`
+	Gitops        *GitopsCF            `protobuf:"bytes,1,opt,name=gitops,proto3" json:"gitops,omitempty"`
+	Observability *ObservabilityCF     `protobuf:"bytes,2,opt,name=observability,proto3" json:"observability,omitempty"`
+	Cilium        *CiliumCF            `protobuf:"bytes,3,opt,name=cilium,proto3" json:"cilium,omitempty"`
+	Modules       map[string]*ModuleCF `protobuf:"bytes,4,rep,name=modules,proto3" json:"modules,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *AgentConfiguration) Reset() {
 	*x = AgentConfiguration{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[8]
+		mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -537,7 +1306,7 @@ func (x *AgentConfiguration) String() string {
 func (*AgentConfiguration) ProtoMessage() {}
 
 func (x *AgentConfiguration) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[8]
+	mi := &file_pkg_agentcfg_agentcfg_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -550,7 +1319,7 @@ func (x *AgentConfiguration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AgentConfiguration.ProtoReflect.Descriptor instead.
 func (*AgentConfiguration) Descriptor() ([]byte, []int) {
-	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{8}
+	return file_pkg_agentcfg_agentcfg_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *AgentConfiguration) GetGitops() *GitopsCF {
@@ -574,6 +1343,13 @@ func (x *AgentConfiguration) GetCilium() *CiliumCF {
 	return nil
 }
 
+func (x *AgentConfiguration) GetModules() map[string]*ModuleCF {
+	if x != nil {
+		return x.Modules
+	}
+	return nil
+}
+
 var File_pkg_agentcfg_agentcfg_proto protoreflect.FileDescriptor
 
 var file_pkg_agentcfg_agentcfg_proto_rawDesc = []byte{
@@ -581,93 +1357,281 @@ var file_pkg_agentcfg_agentcfg_proto_rawDesc = []byte{
 	0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x67,
 	0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e,
 	0x74, 0x63, 0x66, 0x67, 0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76,
-	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x68, 0x0a,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x77,
+	0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x62, 0x0a,
 	0x10, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x43,
 	0x46, 0x12, 0x28, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18,
 	0x01, 0x20, 0x03, 0x28, 0x09, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02, 0x08, 0x01, 0x52,
-	0x0a, 0x61, 0x70, 0x69, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x2a, 0x0a, 0x05, 0x6b,
-	0x69, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x42, 0x14, 0xfa, 0x42, 0x05, 0x92,
-	0x01, 0x02, 0x08, 0x01, 0xfa, 0x42, 0x09, 0x92, 0x01, 0x06, 0x22, 0x04, 0x72, 0x02, 0x10, 0x01,
-	0x52, 0x05, 0x6b, 0x69, 0x6e, 0x64, 0x73, 0x22, 0x25, 0x0a, 0x06, 0x50, 0x61, 0x74, 0x68, 0x43,
-	0x46, 0x12, 0x1b, 0x0a, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
-	0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x22, 0xc5,
-	0x02, 0x0a, 0x11, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x6a, 0x65,
-	0x63, 0x74, 0x43, 0x46, 0x12, 0x17, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x02, 0x69, 0x64, 0x12, 0x59, 0x0a,
-	0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73,
-	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x67, 0x69, 0x74,
+	0x0a, 0x61, 0x70, 0x69, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x24, 0x0a, 0x05, 0x6b,
+	0x69, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0e, 0xfa, 0x42, 0x0b, 0x92,
+	0x01, 0x08, 0x08, 0x01, 0x22, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x05, 0x6b, 0x69, 0x6e, 0x64,
+	0x73, 0x22, 0x64, 0x0a, 0x06, 0x50, 0x61, 0x74, 0x68, 0x43, 0x46, 0x12, 0x1b, 0x0a, 0x04, 0x67,
+	0x6c, 0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02,
+	0x10, 0x01, 0x52, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x12, 0x3d, 0x0a, 0x08, 0x72, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x67, 0x69, 0x74,
 	0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63,
-	0x66, 0x67, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65,
-	0x72, 0x43, 0x46, 0x52, 0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69, 0x6e,
-	0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x59, 0x0a, 0x13, 0x72, 0x65, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18,
-	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x52, 0x65,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x43, 0x46, 0x52, 0x13,
-	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69,
-	0x6f, 0x6e, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x6e,
-	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11,
-	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
-	0x65, 0x12, 0x33, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x1d, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x43, 0x46, 0x52,
-	0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x22, 0x62, 0x0a, 0x08, 0x47, 0x69, 0x74, 0x6f, 0x70, 0x73,
-	0x43, 0x46, 0x12, 0x56, 0x0a, 0x11, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x70,
-	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e,
-	0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65,
-	0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x50, 0x72,
-	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x46, 0x52, 0x11, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73,
-	0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x22, 0x4d, 0x0a, 0x0f, 0x4f, 0x62,
-	0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x43, 0x46, 0x12, 0x3a, 0x0a,
-	0x07, 0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
-	0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x43, 0x46,
-	0x52, 0x07, 0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x22, 0x4c, 0x0a, 0x09, 0x4c, 0x6f, 0x67,
-	0x67, 0x69, 0x6e, 0x67, 0x43, 0x46, 0x12, 0x3f, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x6c, 0x6f,
-	0x67, 0x67, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f, 0x65, 0x6e, 0x75, 0x6d,
-	0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x47, 0x0a, 0x08, 0x43, 0x69, 0x6c, 0x69, 0x75,
-	0x6d, 0x43, 0x46, 0x12, 0x3b, 0x0a, 0x14, 0x68, 0x75, 0x62, 0x62, 0x6c, 0x65, 0x5f, 0x72, 0x65,
-	0x6c, 0x61, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x14, 0x68, 0x75, 0x62, 0x62,
-	0x6c, 0x65, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x22, 0xd3, 0x01, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x47,
-	0x69, 0x74, 0x6f, 0x70, 0x73, 0x43, 0x46, 0x52, 0x06, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x12,
-	0x4c, 0x0a, 0x0d, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x4f,
-	0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x43, 0x46, 0x52, 0x0d,
-	0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x37, 0x0a,
-	0x06, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x66, 0x67, 0x2e, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x43, 0x46, 0x52, 0x08, 0x72,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x22, 0xf7, 0x01, 0x0a, 0x0a, 0x52, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x65, 0x72, 0x43, 0x46, 0x12, 0x22, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02, 0x08,
+	0x01, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x42, 0x0a, 0x07, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0d, 0xfa, 0x42, 0x0a, 0xaa, 0x01, 0x07, 0x22, 0x03,
+	0x08, 0xd8, 0x04, 0x2a, 0x00, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x31,
+	0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x69, 0x62,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x09, 0xfa, 0x42, 0x06, 0x2a, 0x04, 0x18, 0x80, 0x80,
+	0x01, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x69,
+	0x62, 0x12, 0x4e, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x70, 0x75, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x42, 0x0f, 0xfa, 0x42, 0x0c, 0xaa, 0x01, 0x09, 0x22, 0x03, 0x08, 0xd8, 0x04, 0x32,
+	0x02, 0x08, 0x01, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x70, 0x75, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x22, 0xb6, 0x07, 0x0a, 0x11, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x50, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x46, 0x12, 0x17, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x59, 0x0a, 0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e,
 	0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65,
-	0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x43, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x43, 0x46, 0x52, 0x06,
-	0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x22, 0xd4, 0x01, 0x0a, 0x12, 0x41, 0x67, 0x65, 0x6e, 0x74,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x37, 0x0a,
-	0x06, 0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x43, 0x46, 0x52, 0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x59, 0x0a, 0x13, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61,
+	0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67,
+	0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x43,
+	0x46, 0x52, 0x13, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x65, 0x78, 0x63, 0x6c,
+	0x75, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x11, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x12, 0x33, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x50, 0x61, 0x74, 0x68,
+	0x43, 0x46, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12, 0x34, 0x0a, 0x15, 0x73, 0x65, 0x6d,
+	0x76, 0x65, 0x72, 0x5f, 0x74, 0x61, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69,
+	0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x73, 0x65, 0x6d, 0x76, 0x65, 0x72,
+	0x5f, 0x74, 0x61, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12,
+	0x60, 0x0a, 0x14, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x5f, 0x65, 0x6e, 0x76, 0x69, 0x72,
+	0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e,
 	0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65,
-	0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x47, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x43, 0x46, 0x52, 0x06,
-	0x67, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x12, 0x4c, 0x0a, 0x0d, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76,
-	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e,
+	0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x45, 0x6e, 0x76,
+	0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x43, 0x46, 0x52, 0x14, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x5f, 0x65, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x42, 0x0a, 0x0a, 0x67, 0x69, 0x74, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x47, 0x69,
+	0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x46, 0x52, 0x0a, 0x67, 0x69, 0x74, 0x5f, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x12, 0x48, 0x0a, 0x0c, 0x6f, 0x63, 0x69, 0x5f, 0x61, 0x72, 0x74,
+	0x69, 0x66, 0x61, 0x63, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x63, 0x66, 0x67, 0x2e, 0x4f, 0x63, 0x69, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x43,
+	0x46, 0x52, 0x0c, 0x6f, 0x63, 0x69, 0x5f, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x12,
+	0x68, 0x0a, 0x17, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f,
+	0x61, 0x75, 0x74, 0x6f, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x2e, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x41, 0x75, 0x74, 0x6f, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x46,
+	0x52, 0x17, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x61,
+	0x75, 0x74, 0x6f, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x40, 0x0a, 0x09, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x67,
+	0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x63, 0x66, 0x67, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x43, 0x46,
+	0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x0a, 0x64,
+	0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x5f, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x42,
+	0x0c, 0xfa, 0x42, 0x09, 0x92, 0x01, 0x06, 0x22, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0a, 0x64,
+	0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x5f, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x12, 0x55, 0x0a, 0x12, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x5f, 0x6f,
+	0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0a, 0xfa, 0x42, 0x07, 0xaa,
+	0x01, 0x04, 0x32, 0x02, 0x08, 0x01, 0x52, 0x12, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x5f,
+	0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0xaf, 0x01, 0x0a, 0x09, 0x43,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x43, 0x46, 0x12, 0x1b, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x11, 0x6b, 0x75, 0x62, 0x65, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x11, 0x6b, 0x75, 0x62, 0x65, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x34, 0x0a, 0x15,
+	0x6b, 0x75, 0x62, 0x65, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x6b, 0x75, 0x62,
+	0x65, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x6b,
+	0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x22, 0xb0, 0x01, 0x0a,
+	0x0b, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x43, 0x46, 0x12, 0x46, 0x0a, 0x06,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x67,
+	0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x63, 0x66, 0x67, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x43, 0x46,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x6d,
+	0x61, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x5f, 0x6d, 0x61, 0x70, 0x1a, 0x39, 0x0a, 0x0b, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0xeb, 0x01, 0x0a, 0x17, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41,
+	0x75, 0x74, 0x6f, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x46, 0x12, 0x46, 0x0a, 0x06, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x63, 0x66, 0x67, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43,
+	0x46, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02, 0x08, 0x01, 0x52, 0x06, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x14, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x65,
+	0x72, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x14, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x5f,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0a, 0x67, 0x69, 0x74, 0x6c, 0x61,
+	0x62, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04,
+	0x72, 0x02, 0x10, 0x01, 0x52, 0x0a, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x5f, 0x75, 0x72, 0x6c,
+	0x12, 0x2b, 0x0a, 0x0c, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52,
+	0x0c, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0xa0, 0x01,
+	0x0a, 0x0d, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x43, 0x46, 0x12,
+	0x1d, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07,
+	0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x22,
+	0x0a, 0x0c, 0x73, 0x65, 0x6d, 0x76, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x6d, 0x76, 0x65, 0x72, 0x5f, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x61, 0x67, 0x5f, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x67, 0x5f, 0x72, 0x65, 0x67, 0x65, 0x78,
+	0x12, 0x2e, 0x0a, 0x12, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x5f,
+	0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x63, 0x72,
+	0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x22, 0x6a, 0x0a, 0x0b, 0x47, 0x69, 0x74, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x46, 0x12,
+	0x19, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42,
+	0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65,
+	0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x2e, 0x0a, 0x12,
+	0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x61, 0x6c, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0x5a, 0x0a, 0x0d,
+	0x4f, 0x63, 0x69, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x43, 0x46, 0x12, 0x19, 0x0a,
+	0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72,
+	0x02, 0x10, 0x01, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x2e, 0x0a, 0x12, 0x63, 0x72, 0x65, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c,
+	0x73, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0x72, 0x0a, 0x15, 0x50, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x45, 0x6e, 0x76, 0x69, 0x72, 0x6f, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x43,
+	0x46, 0x12, 0x29, 0x0a, 0x0b, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x5f, 0x67, 0x6c, 0x6f, 0x62,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52,
+	0x0b, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x5f, 0x67, 0x6c, 0x6f, 0x62, 0x12, 0x2e, 0x0a, 0x12,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0xca, 0x03, 0x0a,
+	0x08, 0x47, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x43, 0x46, 0x12, 0x56, 0x0a, 0x11, 0x6d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x4d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x43, 0x46, 0x52, 0x11,
+	0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x73, 0x12, 0x2c, 0x0a, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0c, 0xfa, 0x42, 0x09, 0x92, 0x01, 0x06, 0x22, 0x04, 0x72,
+	0x02, 0x10, 0x01, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12,
+	0x3c, 0x0a, 0x08, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x20, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x43, 0x46, 0x52, 0x08, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x73, 0x12, 0x3e, 0x0a,
+	0x1a, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x1a, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x5c, 0x0a,
+	0x22, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0c, 0xfa, 0x42, 0x09, 0x92, 0x01,
+	0x06, 0x22, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x22, 0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73,
+	0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x5c, 0x0a, 0x29, 0x6d,
+	0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x5f,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x29,
+	0x6d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x4d, 0x0a, 0x0f, 0x4f, 0x62, 0x73,
+	0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x43, 0x46, 0x12, 0x3a, 0x0a, 0x07,
+	0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
 	0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65,
-	0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c,
-	0x69, 0x74, 0x79, 0x43, 0x46, 0x52, 0x0d, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69,
-	0x6c, 0x69, 0x74, 0x79, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x43, 0x69, 0x6c,
-	0x69, 0x75, 0x6d, 0x43, 0x46, 0x52, 0x06, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x2a, 0x3e, 0x0a,
-	0x12, 0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f, 0x65,
-	0x6e, 0x75, 0x6d, 0x12, 0x08, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x10, 0x00, 0x12, 0x09, 0x0a,
-	0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x77, 0x61, 0x72, 0x6e,
-	0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x03, 0x42, 0x45, 0x5a,
-	0x43, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x69, 0x74, 0x6c,
-	0x61, 0x62, 0x2d, 0x6f, 0x72, 0x67, 0x2f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2d, 0x69,
-	0x6e, 0x74, 0x65, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61,
-	0x62, 0x2d, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x67, 0x65, 0x6e,
-	0x74, 0x63, 0x66, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x43, 0x46, 0x52,
+	0x07, 0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x22, 0x4c, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x67,
+	0x69, 0x6e, 0x67, 0x43, 0x46, 0x12, 0x3f, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x6c, 0x6f, 0x67,
+	0x67, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f, 0x65, 0x6e, 0x75, 0x6d, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x47, 0x0a, 0x08, 0x43, 0x69, 0x6c, 0x69, 0x75, 0x6d,
+	0x43, 0x46, 0x12, 0x3b, 0x0a, 0x14, 0x68, 0x75, 0x62, 0x62, 0x6c, 0x65, 0x5f, 0x72, 0x65, 0x6c,
+	0x61, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x14, 0x68, 0x75, 0x62, 0x62, 0x6c,
+	0x65, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22,
+	0x40, 0x0a, 0x08, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x43, 0x46, 0x12, 0x34, 0x0a, 0x07, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x42,
+	0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x22, 0x61, 0x0a, 0x09, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x43, 0x46, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69,
+	0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x72, 0x65, 0x66, 0x22, 0xbd, 0x03, 0x0a, 0x11, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x67, 0x69,
+	0x74, 0x6f, 0x70, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x69, 0x74,
+	0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63,
+	0x66, 0x67, 0x2e, 0x47, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x43, 0x46, 0x52, 0x06, 0x67, 0x69, 0x74,
+	0x6f, 0x70, 0x73, 0x12, 0x4c, 0x0a, 0x0d, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x67, 0x69, 0x74,
+	0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63,
+	0x66, 0x67, 0x2e, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x43, 0x46, 0x52, 0x0d, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x43, 0x69, 0x6c, 0x69, 0x75, 0x6d,
+	0x43, 0x46, 0x52, 0x06, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x12, 0x3a, 0x0a, 0x07, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x63, 0x66, 0x67, 0x2e, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x43, 0x46, 0x52, 0x07, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12, 0x4f, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x69, 0x6c,
+	0x65, 0x2e, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07,
+	0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x1a, 0x5b, 0x0a, 0x0c, 0x4d, 0x6f, 0x64, 0x75, 0x6c,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x35, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61,
+	0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67,
+	0x2e, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x43, 0x46, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x83, 0x03, 0x0a, 0x12, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x06, 0x67,
+	0x69, 0x74, 0x6f, 0x70, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x63, 0x66, 0x67, 0x2e, 0x47, 0x69, 0x74, 0x6f, 0x70, 0x73, 0x43, 0x46, 0x52, 0x06, 0x67, 0x69,
+	0x74, 0x6f, 0x70, 0x73, 0x12, 0x4c, 0x0a, 0x0d, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74,
+	0x63, 0x66, 0x67, 0x2e, 0x4f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x43, 0x46, 0x52, 0x0d, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x43, 0x69, 0x6c, 0x69, 0x75,
+	0x6d, 0x43, 0x46, 0x52, 0x06, 0x63, 0x69, 0x6c, 0x69, 0x75, 0x6d, 0x12, 0x50, 0x0a, 0x07, 0x6d,
+	0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x67,
+	0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x63, 0x66, 0x67, 0x2e, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x1a, 0x5b, 0x0a,
+	0x0c, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x35, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x43, 0x46, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x2a, 0x3e, 0x0a, 0x12, 0x6c, 0x6f,
+	0x67, 0x67, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f, 0x65, 0x6e, 0x75, 0x6d,
+	0x12, 0x08, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x64, 0x65,
+	0x62, 0x75, 0x67, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x77, 0x61, 0x72, 0x6e, 0x10, 0x02, 0x12,
+	0x09, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x03, 0x42, 0x45, 0x5a, 0x43, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d,
+	0x6f, 0x72, 0x67, 0x2f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2d, 0x69, 0x6e, 0x74, 0x65,
+	0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2d, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x63, 0x66,
+	0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -683,37 +1647,70 @@ func file_pkg_agentcfg_agentcfg_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_agentcfg_agentcfg_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_agentcfg_agentcfg_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_pkg_agentcfg_agentcfg_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_pkg_agentcfg_agentcfg_proto_goTypes = []interface{}{
-	(LoggingLevelEnum)(0),      // 0: gitlab.agent.agentcfg.logging_level_enum
-	(*ResourceFilterCF)(nil),   // 1: gitlab.agent.agentcfg.ResourceFilterCF
-	(*PathCF)(nil),             // 2: gitlab.agent.agentcfg.PathCF
-	(*ManifestProjectCF)(nil),  // 3: gitlab.agent.agentcfg.ManifestProjectCF
-	(*GitopsCF)(nil),           // 4: gitlab.agent.agentcfg.GitopsCF
-	(*ObservabilityCF)(nil),    // 5: gitlab.agent.agentcfg.ObservabilityCF
-	(*LoggingCF)(nil),          // 6: gitlab.agent.agentcfg.LoggingCF
-	(*CiliumCF)(nil),           // 7: gitlab.agent.agentcfg.CiliumCF
-	(*ConfigurationFile)(nil),  // 8: gitlab.agent.agentcfg.ConfigurationFile
-	(*AgentConfiguration)(nil), // 9: gitlab.agent.agentcfg.AgentConfiguration
+	(LoggingLevelEnum)(0),           // 0: gitlab.agent.agentcfg.logging_level_enum
+	(*ResourceFilterCF)(nil),        // 1: gitlab.agent.agentcfg.ResourceFilterCF
+	(*PathCF)(nil),                  // 2: gitlab.agent.agentcfg.PathCF
+	(*RendererCF)(nil),              // 3: gitlab.agent.agentcfg.RendererCF
+	(*ManifestProjectCF)(nil),       // 4: gitlab.agent.agentcfg.ManifestProjectCF
+	(*ClusterCF)(nil),               // 5: gitlab.agent.agentcfg.ClusterCF
+	(*VariablesCF)(nil),             // 6: gitlab.agent.agentcfg.VariablesCF
+	(*ImageUpdateAutomationCF)(nil), // 7: gitlab.agent.agentcfg.ImageUpdateAutomationCF
+	(*ImagePolicyCF)(nil),           // 8: gitlab.agent.agentcfg.ImagePolicyCF
+	(*GitRemoteCF)(nil),             // 9: gitlab.agent.agentcfg.GitRemoteCF
+	(*OciArtifactCF)(nil),           // 10: gitlab.agent.agentcfg.OciArtifactCF
+	(*PreviewEnvironmentsCF)(nil),   // 11: gitlab.agent.agentcfg.PreviewEnvironmentsCF
+	(*GitopsCF)(nil),                // 12: gitlab.agent.agentcfg.GitopsCF
+	(*ObservabilityCF)(nil),         // 13: gitlab.agent.agentcfg.ObservabilityCF
+	(*LoggingCF)(nil),               // 14: gitlab.agent.agentcfg.LoggingCF
+	(*CiliumCF)(nil),                // 15: gitlab.agent.agentcfg.CiliumCF
+	(*ModuleCF)(nil),                // 16: gitlab.agent.agentcfg.ModuleCF
+	(*IncludeCF)(nil),               // 17: gitlab.agent.agentcfg.IncludeCF
+	(*ConfigurationFile)(nil),       // 18: gitlab.agent.agentcfg.ConfigurationFile
+	(*AgentConfiguration)(nil),      // 19: gitlab.agent.agentcfg.AgentConfiguration
+	nil,                             // 20: gitlab.agent.agentcfg.VariablesCF.ValuesEntry
+	nil,                             // 21: gitlab.agent.agentcfg.ConfigurationFile.ModulesEntry
+	nil,                             // 22: gitlab.agent.agentcfg.AgentConfiguration.ModulesEntry
+	(*duration.Duration)(nil),       // 23: google.protobuf.Duration
+	(*wrappers.BoolValue)(nil),      // 24: google.protobuf.BoolValue
 }
 var file_pkg_agentcfg_agentcfg_proto_depIdxs = []int32{
-	1,  // 0: gitlab.agent.agentcfg.ManifestProjectCF.resource_inclusions:type_name -> gitlab.agent.agentcfg.ResourceFilterCF
-	1,  // 1: gitlab.agent.agentcfg.ManifestProjectCF.resource_exclusions:type_name -> gitlab.agent.agentcfg.ResourceFilterCF
-	2,  // 2: gitlab.agent.agentcfg.ManifestProjectCF.paths:type_name -> gitlab.agent.agentcfg.PathCF
-	3,  // 3: gitlab.agent.agentcfg.GitopsCF.manifest_projects:type_name -> gitlab.agent.agentcfg.ManifestProjectCF
-	6,  // 4: gitlab.agent.agentcfg.ObservabilityCF.logging:type_name -> gitlab.agent.agentcfg.LoggingCF
-	0,  // 5: gitlab.agent.agentcfg.LoggingCF.level:type_name -> gitlab.agent.agentcfg.logging_level_enum
-	4,  // 6: gitlab.agent.agentcfg.ConfigurationFile.gitops:type_name -> gitlab.agent.agentcfg.GitopsCF
-	5,  // 7: gitlab.agent.agentcfg.ConfigurationFile.observability:type_name -> gitlab.agent.agentcfg.ObservabilityCF
-	7,  // 8: gitlab.agent.agentcfg.ConfigurationFile.cilium:type_name -> gitlab.agent.agentcfg.CiliumCF
-	4,  // 9: gitlab.agent.agentcfg.AgentConfiguration.gitops:type_name -> gitlab.agent.agentcfg.GitopsCF
-	5,  // 10: gitlab.agent.agentcfg.AgentConfiguration.observability:type_name -> gitlab.agent.agentcfg.ObservabilityCF
-	7,  // 11: gitlab.agent.agentcfg.AgentConfiguration.cilium:type_name -> gitlab.agent.agentcfg.CiliumCF
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	3,  // 0: gitlab.agent.agentcfg.PathCF.renderer:type_name -> gitlab.agent.agentcfg.RendererCF
+	23, // 1: gitlab.agent.agentcfg.RendererCF.timeout:type_name -> google.protobuf.Duration
+	23, // 2: gitlab.agent.agentcfg.RendererCF.max_cpu_time:type_name -> google.protobuf.Duration
+	1,  // 3: gitlab.agent.agentcfg.ManifestProjectCF.resource_inclusions:type_name -> gitlab.agent.agentcfg.ResourceFilterCF
+	1,  // 4: gitlab.agent.agentcfg.ManifestProjectCF.resource_exclusions:type_name -> gitlab.agent.agentcfg.ResourceFilterCF
+	2,  // 5: gitlab.agent.agentcfg.ManifestProjectCF.paths:type_name -> gitlab.agent.agentcfg.PathCF
+	11, // 6: gitlab.agent.agentcfg.ManifestProjectCF.preview_environments:type_name -> gitlab.agent.agentcfg.PreviewEnvironmentsCF
+	9,  // 7: gitlab.agent.agentcfg.ManifestProjectCF.git_remote:type_name -> gitlab.agent.agentcfg.GitRemoteCF
+	10, // 8: gitlab.agent.agentcfg.ManifestProjectCF.oci_artifact:type_name -> gitlab.agent.agentcfg.OciArtifactCF
+	7,  // 9: gitlab.agent.agentcfg.ManifestProjectCF.image_update_automation:type_name -> gitlab.agent.agentcfg.ImageUpdateAutomationCF
+	6,  // 10: gitlab.agent.agentcfg.ManifestProjectCF.variables:type_name -> gitlab.agent.agentcfg.VariablesCF
+	23, // 11: gitlab.agent.agentcfg.ManifestProjectCF.depends_on_timeout:type_name -> google.protobuf.Duration
+	20, // 12: gitlab.agent.agentcfg.VariablesCF.values:type_name -> gitlab.agent.agentcfg.VariablesCF.ValuesEntry
+	8,  // 13: gitlab.agent.agentcfg.ImageUpdateAutomationCF.images:type_name -> gitlab.agent.agentcfg.ImagePolicyCF
+	4,  // 14: gitlab.agent.agentcfg.GitopsCF.manifest_projects:type_name -> gitlab.agent.agentcfg.ManifestProjectCF
+	5,  // 15: gitlab.agent.agentcfg.GitopsCF.clusters:type_name -> gitlab.agent.agentcfg.ClusterCF
+	14, // 16: gitlab.agent.agentcfg.ObservabilityCF.logging:type_name -> gitlab.agent.agentcfg.LoggingCF
+	0,  // 17: gitlab.agent.agentcfg.LoggingCF.level:type_name -> gitlab.agent.agentcfg.logging_level_enum
+	24, // 18: gitlab.agent.agentcfg.ModuleCF.enabled:type_name -> google.protobuf.BoolValue
+	12, // 19: gitlab.agent.agentcfg.ConfigurationFile.gitops:type_name -> gitlab.agent.agentcfg.GitopsCF
+	13, // 20: gitlab.agent.agentcfg.ConfigurationFile.observability:type_name -> gitlab.agent.agentcfg.ObservabilityCF
+	15, // 21: gitlab.agent.agentcfg.ConfigurationFile.cilium:type_name -> gitlab.agent.agentcfg.CiliumCF
+	17, // 22: gitlab.agent.agentcfg.ConfigurationFile.include:type_name -> gitlab.agent.agentcfg.IncludeCF
+	21, // 23: gitlab.agent.agentcfg.ConfigurationFile.modules:type_name -> gitlab.agent.agentcfg.ConfigurationFile.ModulesEntry
+	12, // 24: gitlab.agent.agentcfg.AgentConfiguration.gitops:type_name -> gitlab.agent.agentcfg.GitopsCF
+	13, // 25: gitlab.agent.agentcfg.AgentConfiguration.observability:type_name -> gitlab.agent.agentcfg.ObservabilityCF
+	15, // 26: gitlab.agent.agentcfg.AgentConfiguration.cilium:type_name -> gitlab.agent.agentcfg.CiliumCF
+	22, // 27: gitlab.agent.agentcfg.AgentConfiguration.modules:type_name -> gitlab.agent.agentcfg.AgentConfiguration.ModulesEntry
+	16, // 28: gitlab.agent.agentcfg.ConfigurationFile.ModulesEntry.value:type_name -> gitlab.agent.agentcfg.ModuleCF
+	16, // 29: gitlab.agent.agentcfg.AgentConfiguration.ModulesEntry.value:type_name -> gitlab.agent.agentcfg.ModuleCF
+	30, // [30:30] is the sub-list for method output_type
+	30, // [30:30] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_pkg_agentcfg_agentcfg_proto_init() }
@@ -747,7 +1744,7 @@ func file_pkg_agentcfg_agentcfg_proto_init() {
 			}
 		}
 		file_pkg_agentcfg_agentcfg_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ManifestProjectCF); i {
+			switch v := v.(*RendererCF); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -759,7 +1756,7 @@ func file_pkg_agentcfg_agentcfg_proto_init() {
 			}
 		}
 		file_pkg_agentcfg_agentcfg_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GitopsCF); i {
+			switch v := v.(*ManifestProjectCF); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -771,7 +1768,7 @@ func file_pkg_agentcfg_agentcfg_proto_init() {
 			}
 		}
 		file_pkg_agentcfg_agentcfg_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ObservabilityCF); i {
+			switch v := v.(*ClusterCF); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -783,7 +1780,7 @@ func file_pkg_agentcfg_agentcfg_proto_init() {
 			}
 		}
 		file_pkg_agentcfg_agentcfg_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LoggingCF); i {
+			switch v := v.(*VariablesCF); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -795,7 +1792,7 @@ func file_pkg_agentcfg_agentcfg_proto_init() {
 			}
 		}
 		file_pkg_agentcfg_agentcfg_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CiliumCF); i {
+			switch v := v.(*ImageUpdateAutomationCF); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -807,7 +1804,7 @@ func file_pkg_agentcfg_agentcfg_proto_init() {
 			}
 		}
 		file_pkg_agentcfg_agentcfg_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConfigurationFile); i {
+			switch v := v.(*ImagePolicyCF); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -819,6 +1816,126 @@ func file_pkg_agentcfg_agentcfg_proto_init() {
 			}
 		}
 		file_pkg_agentcfg_agentcfg_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GitRemoteCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OciArtifactCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PreviewEnvironmentsCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GitopsCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ObservabilityCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoggingCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CiliumCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModuleCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IncludeCF); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigurationFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_agentcfg_agentcfg_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AgentConfiguration); i {
 			case 0:
 				return &v.state
@@ -837,7 +1954,7 @@ func file_pkg_agentcfg_agentcfg_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_agentcfg_agentcfg_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   9,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
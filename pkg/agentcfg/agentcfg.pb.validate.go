@@ -141,6 +141,16 @@ func (m *PathCF) Validate() error {
 		}
 	}
 
+	if v, ok := interface{}(m.GetRenderer()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return PathCFValidationError{
+				field:  "Renderer",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -154,22 +164,836 @@ type PathCFValidationError struct {
 }
 
 // Field function returns field value.
-func (e PathCFValidationError) Field() string { return e.field }
+func (e PathCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PathCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PathCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PathCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PathCFValidationError) ErrorName() string { return "PathCFValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PathCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPathCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PathCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PathCFValidationError{}
+
+// Validate checks the field values on RendererCF with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *RendererCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if len(m.GetCommand()) < 1 {
+		return RendererCFValidationError{
+			field:  "Command",
+			reason: "value must contain at least 1 item(s)",
+		}
+	}
+
+	if d := m.GetTimeout(); d != nil {
+		dur, err := ptypes.Duration(d)
+		if err != nil {
+			return RendererCFValidationError{
+				field:  "Timeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+		}
+
+		lte := time.Duration(600*time.Second + 0*time.Nanosecond)
+		gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+		if dur <= gt || dur > lte {
+			return RendererCFValidationError{
+				field:  "Timeout",
+				reason: "value must be inside range (0s, 10m0s]",
+			}
+		}
+
+	}
+
+	if m.GetMaxMemoryMib() > 16384 {
+		return RendererCFValidationError{
+			field:  "MaxMemoryMib",
+			reason: "value must be less than or equal to 16384",
+		}
+	}
+
+	if d := m.GetMaxCpuTime(); d != nil {
+		dur, err := ptypes.Duration(d)
+		if err != nil {
+			return RendererCFValidationError{
+				field:  "MaxCpuTime",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+		}
+
+		lte := time.Duration(600*time.Second + 0*time.Nanosecond)
+		gte := time.Duration(1*time.Second + 0*time.Nanosecond)
+
+		if dur < gte || dur > lte {
+			return RendererCFValidationError{
+				field:  "MaxCpuTime",
+				reason: "value must be inside range [1s, 10m0s]",
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// RendererCFValidationError is the validation error returned by
+// RendererCF.Validate if the designated constraints aren't met.
+type RendererCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RendererCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RendererCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RendererCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RendererCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RendererCFValidationError) ErrorName() string { return "RendererCFValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RendererCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRendererCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RendererCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RendererCFValidationError{}
+
+// Validate checks the field values on ManifestProjectCF with the rules defined
+// in the proto definition for this message. If any rules are violated, an
+// error is returned.
+func (m *ManifestProjectCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetId()) < 1 {
+		return ManifestProjectCFValidationError{
+			field:  "Id",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	for idx, item := range m.GetResourceInclusions() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ManifestProjectCFValidationError{
+					field:  fmt.Sprintf("ResourceInclusions[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetResourceExclusions() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ManifestProjectCFValidationError{
+					field:  fmt.Sprintf("ResourceExclusions[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for DefaultNamespace
+
+	for idx, item := range m.GetPaths() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ManifestProjectCFValidationError{
+					field:  fmt.Sprintf("Paths[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for SemverTagConstraint
+
+	if v, ok := interface{}(m.GetPreviewEnvironments()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ManifestProjectCFValidationError{
+				field:  "PreviewEnvironments",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetGitRemote()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ManifestProjectCFValidationError{
+				field:  "GitRemote",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetOciArtifact()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ManifestProjectCFValidationError{
+				field:  "OciArtifact",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetImageUpdateAutomation()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ManifestProjectCFValidationError{
+				field:  "ImageUpdateAutomation",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if v, ok := interface{}(m.GetVariables()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ManifestProjectCFValidationError{
+				field:  "Variables",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	for idx, item := range m.GetDependsOn() {
+		_, _ = idx, item
+
+		if utf8.RuneCountInString(item) < 1 {
+			return ManifestProjectCFValidationError{
+				field:  fmt.Sprintf("DependsOn[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+		}
+
+	}
+
+	// no validation rules for Cluster
+
+	if d := m.GetDependsOnTimeout(); d != nil {
+		dur, err := ptypes.Duration(d)
+		if err != nil {
+			return ManifestProjectCFValidationError{
+				field:  "DependsOnTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+		}
+
+		gte := time.Duration(1*time.Second + 0*time.Nanosecond)
+
+		if dur < gte {
+			return ManifestProjectCFValidationError{
+				field:  "DependsOnTimeout",
+				reason: "value must be greater than or equal to 1s",
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// ManifestProjectCFValidationError is the validation error returned by
+// ManifestProjectCF.Validate if the designated constraints aren't met.
+type ManifestProjectCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ManifestProjectCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ManifestProjectCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ManifestProjectCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ManifestProjectCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ManifestProjectCFValidationError) ErrorName() string {
+	return "ManifestProjectCFValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ManifestProjectCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sManifestProjectCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ManifestProjectCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ManifestProjectCFValidationError{}
+
+// Validate checks the field values on ClusterCF with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *ClusterCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetName()) < 1 {
+		return ClusterCFValidationError{
+			field:  "Name",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetKubeconfigSecret()) < 1 {
+		return ClusterCFValidationError{
+			field:  "KubeconfigSecret",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	// no validation rules for KubeconfigSecretKey
+
+	// no validation rules for Context
+
+	return nil
+}
+
+// ClusterCFValidationError is the validation error returned by
+// ClusterCF.Validate if the designated constraints aren't met.
+type ClusterCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ClusterCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ClusterCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ClusterCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ClusterCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ClusterCFValidationError) ErrorName() string { return "ClusterCFValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ClusterCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sClusterCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ClusterCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ClusterCFValidationError{}
+
+// Validate checks the field values on VariablesCF with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *VariablesCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Values
+
+	// no validation rules for ConfigMap
+
+	return nil
+}
+
+// VariablesCFValidationError is the validation error returned by
+// VariablesCF.Validate if the designated constraints aren't met.
+type VariablesCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e VariablesCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e VariablesCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e VariablesCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e VariablesCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e VariablesCFValidationError) ErrorName() string { return "VariablesCFValidationError" }
+
+// Error satisfies the builtin error interface
+func (e VariablesCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sVariablesCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = VariablesCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = VariablesCFValidationError{}
+
+// Validate checks the field values on ImageUpdateAutomationCF with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *ImageUpdateAutomationCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if len(m.GetImages()) < 1 {
+		return ImageUpdateAutomationCFValidationError{
+			field:  "Images",
+			reason: "value must contain at least 1 item(s)",
+		}
+	}
+
+	for idx, item := range m.GetImages() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ImageUpdateAutomationCFValidationError{
+					field:  fmt.Sprintf("Images[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for CreateMergeRequest
+
+	if utf8.RuneCountInString(m.GetGitlabUrl()) < 1 {
+		return ImageUpdateAutomationCFValidationError{
+			field:  "GitlabUrl",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetTokenSecret()) < 1 {
+		return ImageUpdateAutomationCFValidationError{
+			field:  "TokenSecret",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	return nil
+}
+
+// ImageUpdateAutomationCFValidationError is the validation error returned by
+// ImageUpdateAutomationCF.Validate if the designated constraints aren't met.
+type ImageUpdateAutomationCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImageUpdateAutomationCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImageUpdateAutomationCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImageUpdateAutomationCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImageUpdateAutomationCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImageUpdateAutomationCFValidationError) ErrorName() string {
+	return "ImageUpdateAutomationCFValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ImageUpdateAutomationCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImageUpdateAutomationCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImageUpdateAutomationCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImageUpdateAutomationCFValidationError{}
+
+// Validate checks the field values on ImagePolicyCF with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *ImagePolicyCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetImage()) < 1 {
+		return ImagePolicyCFValidationError{
+			field:  "Image",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	// no validation rules for SemverRange
+
+	// no validation rules for TagRegex
+
+	// no validation rules for CredentialsSecret
+
+	return nil
+}
+
+// ImagePolicyCFValidationError is the validation error returned by
+// ImagePolicyCF.Validate if the designated constraints aren't met.
+type ImagePolicyCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ImagePolicyCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ImagePolicyCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ImagePolicyCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ImagePolicyCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ImagePolicyCFValidationError) ErrorName() string { return "ImagePolicyCFValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ImagePolicyCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sImagePolicyCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ImagePolicyCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ImagePolicyCFValidationError{}
+
+// Validate checks the field values on GitRemoteCF with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *GitRemoteCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetUrl()) < 1 {
+		return GitRemoteCFValidationError{
+			field:  "Url",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	// no validation rules for Ref
+
+	// no validation rules for CredentialsSecret
+
+	return nil
+}
+
+// GitRemoteCFValidationError is the validation error returned by
+// GitRemoteCF.Validate if the designated constraints aren't met.
+type GitRemoteCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e GitRemoteCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e GitRemoteCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e GitRemoteCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e GitRemoteCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e GitRemoteCFValidationError) ErrorName() string { return "GitRemoteCFValidationError" }
+
+// Error satisfies the builtin error interface
+func (e GitRemoteCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sGitRemoteCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = GitRemoteCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = GitRemoteCFValidationError{}
+
+// Validate checks the field values on OciArtifactCF with the rules defined in
+// the proto definition for this message. If any rules are violated, an error
+// is returned.
+func (m *OciArtifactCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if utf8.RuneCountInString(m.GetRef()) < 1 {
+		return OciArtifactCFValidationError{
+			field:  "Ref",
+			reason: "value length must be at least 1 runes",
+		}
+	}
+
+	// no validation rules for CredentialsSecret
+
+	return nil
+}
+
+// OciArtifactCFValidationError is the validation error returned by
+// OciArtifactCF.Validate if the designated constraints aren't met.
+type OciArtifactCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e OciArtifactCFValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e PathCFValidationError) Reason() string { return e.reason }
+func (e OciArtifactCFValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e PathCFValidationError) Cause() error { return e.cause }
+func (e OciArtifactCFValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e PathCFValidationError) Key() bool { return e.key }
+func (e OciArtifactCFValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e PathCFValidationError) ErrorName() string { return "PathCFValidationError" }
+func (e OciArtifactCFValidationError) ErrorName() string { return "OciArtifactCFValidationError" }
 
 // Error satisfies the builtin error interface
-func (e PathCFValidationError) Error() string {
+func (e OciArtifactCFValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -181,14 +1005,14 @@ func (e PathCFValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sPathCF.%s: %s%s",
+		"invalid %sOciArtifactCF.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = PathCFValidationError{}
+var _ error = OciArtifactCFValidationError{}
 
 var _ interface {
 	Field() string
@@ -196,76 +1020,31 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = PathCFValidationError{}
+} = OciArtifactCFValidationError{}
 
-// Validate checks the field values on ManifestProjectCF with the rules defined
-// in the proto definition for this message. If any rules are violated, an
-// error is returned.
-func (m *ManifestProjectCF) Validate() error {
+// Validate checks the field values on PreviewEnvironmentsCF with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, an error is returned.
+func (m *PreviewEnvironmentsCF) Validate() error {
 	if m == nil {
 		return nil
 	}
 
-	if utf8.RuneCountInString(m.GetId()) < 1 {
-		return ManifestProjectCFValidationError{
-			field:  "Id",
+	if utf8.RuneCountInString(m.GetBranchGlob()) < 1 {
+		return PreviewEnvironmentsCFValidationError{
+			field:  "BranchGlob",
 			reason: "value length must be at least 1 runes",
 		}
 	}
 
-	for idx, item := range m.GetResourceInclusions() {
-		_, _ = idx, item
-
-		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
-			if err := v.Validate(); err != nil {
-				return ManifestProjectCFValidationError{
-					field:  fmt.Sprintf("ResourceInclusions[%v]", idx),
-					reason: "embedded message failed validation",
-					cause:  err,
-				}
-			}
-		}
-
-	}
-
-	for idx, item := range m.GetResourceExclusions() {
-		_, _ = idx, item
-
-		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
-			if err := v.Validate(); err != nil {
-				return ManifestProjectCFValidationError{
-					field:  fmt.Sprintf("ResourceExclusions[%v]", idx),
-					reason: "embedded message failed validation",
-					cause:  err,
-				}
-			}
-		}
-
-	}
-
-	// no validation rules for DefaultNamespace
-
-	for idx, item := range m.GetPaths() {
-		_, _ = idx, item
-
-		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
-			if err := v.Validate(); err != nil {
-				return ManifestProjectCFValidationError{
-					field:  fmt.Sprintf("Paths[%v]", idx),
-					reason: "embedded message failed validation",
-					cause:  err,
-				}
-			}
-		}
-
-	}
+	// no validation rules for NamespaceTemplate
 
 	return nil
 }
 
-// ManifestProjectCFValidationError is the validation error returned by
-// ManifestProjectCF.Validate if the designated constraints aren't met.
-type ManifestProjectCFValidationError struct {
+// PreviewEnvironmentsCFValidationError is the validation error returned by
+// PreviewEnvironmentsCF.Validate if the designated constraints aren't met.
+type PreviewEnvironmentsCFValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -273,24 +1052,24 @@ type ManifestProjectCFValidationError struct {
 }
 
 // Field function returns field value.
-func (e ManifestProjectCFValidationError) Field() string { return e.field }
+func (e PreviewEnvironmentsCFValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e ManifestProjectCFValidationError) Reason() string { return e.reason }
+func (e PreviewEnvironmentsCFValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e ManifestProjectCFValidationError) Cause() error { return e.cause }
+func (e PreviewEnvironmentsCFValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e ManifestProjectCFValidationError) Key() bool { return e.key }
+func (e PreviewEnvironmentsCFValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e ManifestProjectCFValidationError) ErrorName() string {
-	return "ManifestProjectCFValidationError"
+func (e PreviewEnvironmentsCFValidationError) ErrorName() string {
+	return "PreviewEnvironmentsCFValidationError"
 }
 
 // Error satisfies the builtin error interface
-func (e ManifestProjectCFValidationError) Error() string {
+func (e PreviewEnvironmentsCFValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -302,14 +1081,14 @@ func (e ManifestProjectCFValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sManifestProjectCF.%s: %s%s",
+		"invalid %sPreviewEnvironmentsCF.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = ManifestProjectCFValidationError{}
+var _ error = PreviewEnvironmentsCFValidationError{}
 
 var _ interface {
 	Field() string
@@ -317,7 +1096,7 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = ManifestProjectCFValidationError{}
+} = PreviewEnvironmentsCFValidationError{}
 
 // Validate checks the field values on GitopsCF with the rules defined in the
 // proto definition for this message. If any rules are violated, an error is returned.
@@ -341,6 +1120,49 @@ func (m *GitopsCF) Validate() error {
 
 	}
 
+	for idx, item := range m.GetNamespaces() {
+		_, _ = idx, item
+
+		if utf8.RuneCountInString(item) < 1 {
+			return GitopsCFValidationError{
+				field:  fmt.Sprintf("Namespaces[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetClusters() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return GitopsCFValidationError{
+					field:  fmt.Sprintf("Clusters[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for ManifestProjectResources
+
+	for idx, item := range m.GetManifestProjectResourceProjects() {
+		_, _ = idx, item
+
+		if utf8.RuneCountInString(item) < 1 {
+			return GitopsCFValidationError{
+				field:  fmt.Sprintf("ManifestProjectResourceProjects[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+		}
+
+	}
+
+	// no validation rules for ManifestProjectResourceServiceAccount
+
 	return nil
 }
 
@@ -610,6 +1432,152 @@ var _ interface {
 	ErrorName() string
 } = CiliumCFValidationError{}
 
+// Validate checks the field values on ModuleCF with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *ModuleCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if v, ok := interface{}(m.GetEnabled()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ModuleCFValidationError{
+				field:  "Enabled",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// ModuleCFValidationError is the validation error returned by
+// ModuleCF.Validate if the designated constraints aren't met.
+type ModuleCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ModuleCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ModuleCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ModuleCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ModuleCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ModuleCFValidationError) ErrorName() string { return "ModuleCFValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ModuleCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sModuleCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ModuleCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ModuleCFValidationError{}
+
+// Validate checks the field values on IncludeCF with the rules defined in the
+// proto definition for this message. If any rules are violated, an error is returned.
+func (m *IncludeCF) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	// no validation rules for Local
+
+	// no validation rules for Project
+
+	// no validation rules for File
+
+	// no validation rules for Ref
+
+	return nil
+}
+
+// IncludeCFValidationError is the validation error returned by
+// IncludeCF.Validate if the designated constraints aren't met.
+type IncludeCFValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e IncludeCFValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e IncludeCFValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e IncludeCFValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e IncludeCFValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e IncludeCFValidationError) ErrorName() string { return "IncludeCFValidationError" }
+
+// Error satisfies the builtin error interface
+func (e IncludeCFValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sIncludeCF.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = IncludeCFValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = IncludeCFValidationError{}
+
 // Validate checks the field values on ConfigurationFile with the rules defined
 // in the proto definition for this message. If any rules are violated, an
 // error is returned.
@@ -648,6 +1616,38 @@ func (m *ConfigurationFile) Validate() error {
 		}
 	}
 
+	for idx, item := range m.GetInclude() {
+		_, _ = idx, item
+
+		if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ConfigurationFileValidationError{
+					field:  fmt.Sprintf("Include[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for key, val := range m.GetModules() {
+		_ = val
+
+		// no validation rules for Modules[key]
+
+		if v, ok := interface{}(val).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ConfigurationFileValidationError{
+					field:  fmt.Sprintf("Modules[%v]", key),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	return nil
 }
 
@@ -745,6 +1745,23 @@ func (m *AgentConfiguration) Validate() error {
 		}
 	}
 
+	for key, val := range m.GetModules() {
+		_ = val
+
+		// no validation rules for Modules[key]
+
+		if v, ok := interface{}(val).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentConfigurationValidationError{
+					field:  fmt.Sprintf("Modules[%v]", key),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	return nil
 }
 